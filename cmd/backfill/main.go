@@ -0,0 +1,144 @@
+package main
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/exporter"
+	"eth2-exporter/metrics"
+	"eth2-exporter/rpc"
+	"eth2-exporter/services"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"eth2-exporter/version"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	configPath := flag.String("config", "", "Path to the config file, if empty string defaults will be used")
+	startEpoch := flag.Uint64("start", 0, "First epoch to add to the backfill queue")
+	endEpoch := flag.Uint64("end", math.MaxUint64, "Last epoch to add to the backfill queue, if not set no epochs are added and only the existing queue is processed")
+	workers := flag.Int("workers", 0, "Number of parallel workers, overrides the indexer.backfill.workers config")
+	retryFailed := flag.Bool("retry-failed", false, "Requeue ranges that failed in a previous run")
+	status := flag.Bool("status", false, "Print the progress of the backfill queue and exit")
+	flag.Parse()
+
+	cfg := &types.Config{}
+	err := utils.ReadConfig(cfg, *configPath)
+	if err != nil {
+		logrus.Fatalf("error reading config file: %v", err)
+	}
+	utils.Config = cfg
+	logrus.WithField("config", *configPath).WithField("version", version.Version).WithField("chainName", utils.Config.Chain.Config.ConfigName).Printf("starting")
+
+	if *workers > 0 {
+		utils.Config.Indexer.Backfill.Workers = *workers
+	}
+
+	db.MustInitDB(&types.DatabaseConfig{
+		Username: cfg.WriterDatabase.Username,
+		Password: cfg.WriterDatabase.Password,
+		Name:     cfg.WriterDatabase.Name,
+		Host:     cfg.WriterDatabase.Host,
+		Port:     cfg.WriterDatabase.Port,
+	}, &types.DatabaseConfig{
+		Username: cfg.ReaderDatabase.Username,
+		Password: cfg.ReaderDatabase.Password,
+		Name:     cfg.ReaderDatabase.Name,
+		Host:     cfg.ReaderDatabase.Host,
+		Port:     cfg.ReaderDatabase.Port,
+	})
+	defer db.ReaderDb.Close()
+	defer db.WriterDb.Close()
+
+	if *status {
+		progress, err := db.GetBackfillProgress()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		logrus.Infof("backfill queue: %v pending, %v running, %v done, %v failed ranges, %v epochs remaining", progress.Pending, progress.Running, progress.Done, progress.Failed, progress.Remaining)
+		return
+	}
+
+	_, err = db.InitBigtable(cfg.Bigtable.Project, cfg.Bigtable.Instance, fmt.Sprintf("%d", utils.Config.Chain.Config.DepositChainID))
+	if err != nil {
+		logrus.Fatalf("error initializing bigtable %v", err)
+	}
+
+	err = services.InitLastAttestationCache(utils.Config.LastAttestationCachePath)
+	if err != nil {
+		logrus.Fatalf("error initializing last attesation cache: %v", err)
+	}
+
+	if utils.Config.Metrics.Enabled {
+		go func(addr string) {
+			logrus.Infof("Serving metrics on %v", addr)
+			if err := metrics.Serve(addr); err != nil {
+				logrus.WithError(err).Fatal("Error serving metrics")
+			}
+		}(utils.Config.Metrics.Address)
+	}
+
+	chainID := new(big.Int).SetUint64(utils.Config.Chain.Config.DepositChainID)
+	client, err := newBeaconClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	if *retryFailed {
+		reset, err := db.ResetFailedBackfillRanges()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		logrus.Infof("requeued %v failed backfill ranges", reset)
+	}
+
+	if *endEpoch != math.MaxUint64 {
+		head, err := client.GetChainHead()
+		if err != nil {
+			logrus.Fatalf("error retrieving chain head: %v", err)
+		}
+		if *endEpoch > head.FinalizedEpoch {
+			logrus.Infof("limiting backfill to the last finalized epoch %v", head.FinalizedEpoch)
+			*endEpoch = head.FinalizedEpoch
+		}
+		err = exporter.EnqueueBackfill(*startEpoch, *endEpoch)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	}
+
+	if utils.Config.Indexer.LeaderElection.Enabled {
+		// only the exporter leader writes epochs. If a live exporter holds the leadership the backfill does not wait for
+		// it, the enqueued ranges are exported by the leader which works off the backfill queue while it is running.
+		leader, err := exporter.TryExporterLeadership()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		if !leader {
+			logrus.Infof("another instance is the exporter leader, the enqueued ranges will be exported by the leader")
+			return
+		}
+	}
+
+	err = exporter.Backfill(client)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// newBeaconClient creates the rpc client for the configured node type
+func newBeaconClient(endpoint string, chainID *big.Int) (rpc.Client, error) {
+	switch utils.Config.Indexer.Node.Type {
+	case "lighthouse":
+		return rpc.NewLighthouseClient(endpoint, chainID)
+	case "standard", "prysm", "teku", "nimbus", "lodestar":
+		return rpc.NewStandardBeaconClient(endpoint, chainID)
+	default:
+		return nil, fmt.Errorf("invalid node type %v specified. supported node types are lighthouse, prysm, teku, nimbus, lodestar and standard", utils.Config.Indexer.Node.Type)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"testing"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"cloud.google.com/go/bigtable/bttest"
+	"github.com/karlseguin/ccache/v2"
+)
+
+// TestReplayIndexFromNode indexes the execution block 16 of the fixtures in rpc/testdata/replay into an in-memory
+// bigtable emulator
+func TestReplayIndexFromNode(t *testing.T) {
+	srv, err := bttest.NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	t.Setenv("BIGTABLE_EMULATOR_HOST", srv.Addr)
+
+	ctx := context.Background()
+	admin, err := gcp_bigtable.NewAdminClient(ctx, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	tables := map[string][]string{
+		"blocks":           {db.DEFAULT_FAMILY_BLOCKS},
+		"data":             {db.DEFAULT_FAMILY},
+		"metadata_updates": {db.DEFAULT_FAMILY, db.METADATA_UPDATES_FAMILY_BLOCKS},
+	}
+	for table, families := range tables {
+		if err := admin.CreateTable(ctx, table); err != nil {
+			t.Fatal(err)
+		}
+		for _, family := range families {
+			if err := admin.CreateColumnFamily(ctx, table, family); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	bt, err := db.InitBigtable("test", "test", "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bt.Close()
+
+	client, stop, err := rpc.NewReplayErigonClient("../../rpc/testdata/replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	err = IndexFromNode(bt, client, 16, 16, 1)
+	if err != nil {
+		t.Fatalf("error indexing replayed block: %v", err)
+	}
+	lastBlock, err := bt.GetLastBlockInBlocksTable()
+	if err != nil || lastBlock != 16 {
+		t.Fatalf("expected block 16 to be the last block of the blocks table, got %v (%v)", lastBlock, err)
+	}
+
+	transforms := []func(blk *types.Eth1Block, cache *ccache.Cache) (*types.BulkMutations, *types.BulkMutations, error){bt.TransformBlock, bt.TransformTx, bt.TransformItx}
+	err = IndexFromBigtable(bt, 16, 16, transforms, 1)
+	if err != nil {
+		t.Fatalf("error transforming replayed block: %v", err)
+	}
+	blocks, err := bt.GetBlocksDescending(16, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the top level call of the transaction is not counted as internal transaction
+	if len(blocks) != 1 || blocks[0].Number != 16 || blocks[0].TransactionCount != 1 || blocks[0].InternalTransactionCount != 1 {
+		t.Errorf("unexpected indexed blocks %v", blocks)
+	}
+}
@@ -195,13 +195,26 @@ func main() {
 		var rpcClient rpc.Client
 
 		chainID := new(big.Int).SetUint64(utils.Config.Chain.Config.DepositChainID)
-		if utils.Config.Indexer.Node.Type == "lighthouse" {
-			rpcClient, err = rpc.NewLighthouseClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
+		rpcClient, err = newBeaconClient("http://"+cfg.Indexer.Node.Host+":"+cfg.Indexer.Node.Port, chainID)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
+		if len(utils.Config.Indexer.Node.Endpoints) > 0 {
+			endpoints := []string{"http://" + cfg.Indexer.Node.Host + ":" + cfg.Indexer.Node.Port}
+			clients := []rpc.Client{rpcClient}
+			for _, endpoint := range utils.Config.Indexer.Node.Endpoints {
+				client, err := newBeaconClient("http://"+endpoint, chainID)
+				if err != nil {
+					logrus.Fatal(err)
+				}
+				endpoints = append(endpoints, "http://"+endpoint)
+				clients = append(clients, client)
+			}
+			rpcClient, err = rpc.NewMultiClient(endpoints, clients, utils.Config.Indexer.Node.Quorum)
 			if err != nil {
 				logrus.Fatal(err)
 			}
-		} else {
-			logrus.Fatalf("invalid note type %v specified. supported node types are prysm and lighthouse", utils.Config.Indexer.Node.Type)
 		}
 
 		if utils.Config.Indexer.OneTimeExport.Enabled {
@@ -242,6 +255,7 @@ func main() {
 		apiV1Router.HandleFunc("/block/{slot}/attesterslashings", handlers.ApiBlockAttesterSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/block/{slot}/proposerslashings", handlers.ApiBlockProposerSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/block/{slot}/voluntaryexits", handlers.ApiBlockVoluntaryExits).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/block/{slot}/withdrawals", handlers.ApiBlockWithdrawals).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/sync_committee/{period}", handlers.ApiSyncCommittee).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/eth1deposit/{txhash}", handlers.ApiEth1Deposit).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/leaderboard", handlers.ApiValidatorLeaderboard).Methods("GET", "OPTIONS")
@@ -252,11 +266,22 @@ func main() {
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestations", handlers.ApiValidatorAttestations).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/proposals", handlers.ApiValidatorProposals).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/deposits", handlers.ApiValidatorDeposits).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/withdrawals", handlers.ApiValidatorWithdrawals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/rewards", handlers.ApiValidatorRewards).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/reorgs", handlers.ApiReorgs).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/blschange", handlers.ApiValidatorBlsChange).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/queue", handlers.ApiValidatorQueueEstimate).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/potentialslashings", handlers.ApiValidatorPotentialSlashings).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/packing", handlers.ApiValidatorBlockPacking).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/duties", handlers.ApiValidatorDuties).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationefficiency", handlers.ApiValidatorAttestationEfficiency).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationeffectiveness", handlers.ApiValidatorAttestationEffectiveness).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/stats/{index}", handlers.ApiValidatorDailyStats).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/eth1/{address}", handlers.ApiValidatorByEth1Address).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validators/queue", handlers.ApiValidatorQueue).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/slashings/potential", handlers.ApiPotentialSlashings).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/packing/leaderboard", handlers.ApiPackingLeaderboard).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/duties/proposer/{epoch}", handlers.ApiProposerDuties).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/graffitiwall", handlers.ApiGraffitiwall).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/chart/{chart}", handlers.ApiChart).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/user/token", handlers.APIGetToken).Methods("POST", "OPTIONS")
@@ -271,6 +296,11 @@ func main() {
 		apiV1Router.HandleFunc("/app/dashboard", handlers.ApiDashboard).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/rocketpool/stats", handlers.ApiRocketpoolStats).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/rocketpool/validator/{indexOrPubkey}", handlers.ApiRocketpoolValidators).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/ssv/operators", handlers.ApiSSVOperators).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/ssv/operator/{id}", handlers.ApiSSVOperator).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/relays/underpayments", handlers.ApiRelayUnderpayments).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/relays/bids/{slot}", handlers.ApiRelayBids).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/builders", handlers.ApiBuilders).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/ethstore/{day}", handlers.ApiEthStoreDay).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/{addressIndexOrPubkey}/produced", handlers.ApiETH1AccountProducedBlocks).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/block/{blockNumber}", handlers.ApiETH1ExecBlocks).Methods("GET", "OPTIONS")
@@ -346,6 +376,8 @@ func main() {
 			router.HandleFunc("/slot/{slotOrHash}/votes", handlers.BlockVoteData).Methods("GET")
 			router.HandleFunc("/slots", handlers.Blocks).Methods("GET")
 			router.HandleFunc("/slots/data", handlers.BlocksData).Methods("GET")
+			router.HandleFunc("/reorgs", handlers.Reorgs).Methods("GET")
+			router.HandleFunc("/reorgs/data", handlers.ReorgsData).Methods("GET")
 			router.HandleFunc("/blocks", handlers.Eth1Blocks).Methods("GET")
 			router.HandleFunc("/blocks/data", handlers.Eth1BlocksData).Methods("GET")
 			router.HandleFunc("/blocks/highest", handlers.Eth1BlocksHighest).Methods("GET")
@@ -380,6 +412,7 @@ func main() {
 			router.HandleFunc("/validator/{index}/history", handlers.ValidatorHistory).Methods("GET")
 			router.HandleFunc("/validator/{pubkey}/deposits", handlers.ValidatorDeposits).Methods("GET")
 			router.HandleFunc("/validator/{index}/slashings", handlers.ValidatorSlashings).Methods("GET")
+			router.HandleFunc("/validator/{index}/withdrawals", handlers.ValidatorWithdrawals).Methods("GET")
 			router.HandleFunc("/validator/{index}/effectiveness", handlers.ValidatorAttestationInclusionEffectiveness).Methods("GET")
 			router.HandleFunc("/validator/{pubkey}/save", handlers.ValidatorSave).Methods("POST")
 			router.HandleFunc("/validator/{pubkey}/add", handlers.UserValidatorWatchlistAdd).Methods("POST")
@@ -391,6 +424,7 @@ func main() {
 			router.HandleFunc("/validators/slashings/data", handlers.ValidatorsSlashingsData).Methods("GET")
 			router.HandleFunc("/validators/leaderboard", handlers.ValidatorsLeaderboard).Methods("GET")
 			router.HandleFunc("/validators/leaderboard/data", handlers.ValidatorsLeaderboardData).Methods("GET")
+			router.HandleFunc("/validators/packing", handlers.BlockPacking).Methods("GET")
 			router.HandleFunc("/validators/streakleaderboard", handlers.ValidatorsStreakLeaderboard).Methods("GET")
 			router.HandleFunc("/validators/streakleaderboard/data", handlers.ValidatorsStreakLeaderboardData).Methods("GET")
 			router.HandleFunc("/validators/eth1deposits", handlers.Eth1Deposits).Methods("GET")
@@ -410,6 +444,7 @@ func main() {
 			router.HandleFunc("/dashboard/data/validators", handlers.DashboardDataValidators).Methods("GET")
 			router.HandleFunc("/dashboard/data/effectiveness", handlers.DashboardDataEffectiveness).Methods("GET")
 			router.HandleFunc("/dashboard/data/earnings", handlers.DashboardDataEarnings).Methods("GET")
+			router.HandleFunc("/dashboard/data/duties", handlers.DashboardDataDuties).Methods("GET")
 			router.HandleFunc("/graffitiwall", handlers.Graffitiwall).Methods("GET")
 			router.HandleFunc("/calculator", handlers.StakingCalculator).Methods("GET")
 			router.HandleFunc("/search", handlers.Search).Methods("POST")
@@ -436,11 +471,14 @@ func main() {
 				// router.HandleFunc("/pools/chart/income_per_eth", handlers.GetIncomePerEthChart).Methods("GET")
 			}
 			router.HandleFunc("/relays", handlers.Relays).Methods("GET")
+			router.HandleFunc("/builders", handlers.Builders).Methods("GET")
 			router.HandleFunc("/pools/rocketpool", handlers.PoolsRocketpool).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/minipools", handlers.PoolsRocketpoolDataMinipools).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/nodes", handlers.PoolsRocketpoolDataNodes).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/dao_proposals", handlers.PoolsRocketpoolDataDAOProposals).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/dao_members", handlers.PoolsRocketpoolDataDAOMembers).Methods("GET")
+			router.HandleFunc("/pools/ssv", handlers.PoolsSSV).Methods("GET")
+			router.HandleFunc("/pools/ssv/data/operators", handlers.PoolsSSVDataOperators).Methods("GET")
 
 			router.HandleFunc("/advertisewithus", handlers.AdvertiseWithUs).Methods("GET")
 			router.HandleFunc("/advertisewithus", handlers.AdvertiseWithUsPost).Methods("POST")
@@ -605,3 +643,15 @@ func main() {
 
 	logrus.Println("exiting...")
 }
+
+// newBeaconClient creates the rpc client for the configured node type
+func newBeaconClient(endpoint string, chainID *big.Int) (rpc.Client, error) {
+	switch utils.Config.Indexer.Node.Type {
+	case "lighthouse":
+		return rpc.NewLighthouseClient(endpoint, chainID)
+	case "standard", "prysm", "teku", "nimbus", "lodestar":
+		return rpc.NewStandardBeaconClient(endpoint, chainID)
+	default:
+		return nil, fmt.Errorf("invalid node type %v specified. supported node types are lighthouse, prysm, teku, nimbus, lodestar and standard", utils.Config.Indexer.Node.Type)
+	}
+}
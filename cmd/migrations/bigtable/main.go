@@ -71,7 +71,8 @@ func main() {
 		})
 
 		g.Go(func() error {
-			return bt.SaveAttestations(data.Blocks)
+			// the votes are not checked as the canonical blocks are not available without the database
+			return bt.SaveAttestations(data.Blocks, nil)
 		})
 
 		g.Go(func() error {
@@ -149,7 +150,8 @@ func monitor(configPath string) {
 			})
 
 			g.Go(func() error {
-				return bt.SaveAttestations(data.Blocks)
+				// the votes are not checked as the canonical blocks are not available without the database
+				return bt.SaveAttestations(data.Blocks, nil)
 			})
 
 			g.Go(func() error {
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"eth2-exporter/db"
+	"eth2-exporter/pools"
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"eth2-exporter/version"
@@ -12,20 +14,37 @@ import (
 
 	"flag"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
 )
 
 var opts = struct {
-	Command string
-	User    uint64
+	Command    string
+	User       uint64
+	Epoch      uint64
+	StartEpoch uint64
+	EndEpoch   uint64
+	All        bool
 }{}
 
 func main() {
 	configPath := flag.String("config", "config/default.config.yml", "Path to the config file")
-	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey")
+	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey, listEpochExportFailures, retryEpochExport, clearEpochExportFailure, discoverLidoValidators, attributeBlockBuilders")
 	flag.Uint64Var(&opts.User, "user", 0, "user id")
+	flag.Uint64Var(&opts.Epoch, "epoch", 0, "epoch to retry or clear, required unless -all is set")
+	flag.Uint64Var(&opts.StartEpoch, "start-epoch", 0, "first epoch to process")
+	flag.Uint64Var(&opts.EndEpoch, "end-epoch", 0, "last epoch to process")
+	flag.BoolVar(&opts.All, "all", false, "retry or clear all failed epochs")
 	flag.Parse()
 
+	epochSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "epoch" {
+			epochSet = true
+		}
+	})
+
 	logrus.WithField("config", *configPath).WithField("version", version.Version).Printf("starting")
 	cfg := &types.Config{}
 	err := utils.ReadConfig(cfg, *configPath)
@@ -71,6 +90,37 @@ func main() {
 		if err != nil {
 			logrus.WithError(err).Fatal("error updating API key")
 		}
+	case "listEpochExportFailures":
+		err := ListEpochExportFailures()
+		if err != nil {
+			logrus.WithError(err).Fatal("error listing epoch export failures")
+		}
+	case "retryEpochExport":
+		if !epochSet && !opts.All {
+			logrus.Fatal("the -epoch or -all flag is required for the retryEpochExport command")
+		}
+		err := RetryEpochExport(opts.Epoch, opts.All)
+		if err != nil {
+			logrus.WithError(err).Fatal("error retrying epoch export")
+		}
+	case "clearEpochExportFailure":
+		if !epochSet && !opts.All {
+			logrus.Fatal("the -epoch or -all flag is required for the clearEpochExportFailure command")
+		}
+		err := ClearEpochExportFailure(opts.Epoch, opts.All)
+		if err != nil {
+			logrus.WithError(err).Fatal("error clearing epoch export failure")
+		}
+	case "discoverLidoValidators":
+		err := DiscoverLidoValidators()
+		if err != nil {
+			logrus.WithError(err).Fatal("error discovering lido validators")
+		}
+	case "attributeBlockBuilders":
+		err := AttributeBlockBuilders(opts.StartEpoch, opts.EndEpoch)
+		if err != nil {
+			logrus.WithError(err).Fatal("error attributing blocks to builders")
+		}
 	case "checkTransactions":
 
 	default:
@@ -133,3 +183,126 @@ func UpdateAPIKey(user uint64) error {
 
 	return nil
 }
+
+// Prints all epochs whose export failed
+func ListEpochExportFailures() error {
+	failures, err := db.GetEpochExportFailures()
+	if err != nil {
+		return err
+	}
+
+	if len(failures) == 0 {
+		logrus.Infof("no failed epoch exports recorded")
+		return nil
+	}
+
+	for _, failure := range failures {
+		status := "retrying"
+		if db.IsEpochExportStuck(failure.Epoch, failure.Attempts) {
+			status = "stuck"
+		}
+		logrus.WithFields(logrus.Fields{
+			"epoch":         failure.Epoch,
+			"attempts":      failure.Attempts,
+			"status":        status,
+			"firstFailedAt": failure.FirstFailedAt,
+			"lastFailedAt":  failure.LastFailedAt,
+		}).Infof("last error: %v", failure.LastError)
+	}
+	return nil
+}
+
+// Resets the failed attempts of an epoch so the exporter retries it during the next export run
+func RetryEpochExport(epoch uint64, all bool) error {
+	if all {
+		count, err := db.RetryAllEpochExports()
+		if err != nil {
+			return err
+		}
+		logrus.Infof("queued %v failed epochs for retry", count)
+		return nil
+	}
+
+	err := db.RetryEpochExport(epoch)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("queued epoch %v for retry", epoch)
+	return nil
+}
+
+// Removes the recorded export failures of an epoch, the epoch is not retried anymore
+func ClearEpochExportFailure(epoch uint64, all bool) error {
+	if all {
+		count, err := db.ClearAllEpochExportFailures()
+		if err != nil {
+			return err
+		}
+		logrus.Infof("cleared %v failed epochs", count)
+		return nil
+	}
+
+	err := db.ClearEpochExportFailure(epoch)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("cleared failures of epoch %v", epoch)
+	return nil
+}
+
+// Prints the operators and validators of the configured lido node operators registry without saving them, this allows
+// checking the adapter against a local dev chain
+func DiscoverLidoValidators() error {
+	address := utils.Config.PoolAdapters.Lido.NodeOperatorsRegistryAddress
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid lido node operators registry address %v", address)
+	}
+	client, err := ethclient.Dial(utils.Config.Eth1GethEndpoint)
+	if err != nil {
+		return err
+	}
+	adapter, err := pools.NewLidoAdapter(client, common.HexToAddress(address))
+	if err != nil {
+		return err
+	}
+
+	operators, err := adapter.Operators(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	for _, operator := range operators {
+		logrus.WithFields(logrus.Fields{
+			"id":         operator.ID,
+			"name":       operator.Name,
+			"address":    fmt.Sprintf("%#x", operator.Address),
+			"active":     operator.Active,
+			"validators": operator.ValidatorCount,
+		}).Infof("lido node operator")
+		for _, pubkey := range operator.NewValidators {
+			logrus.Debugf("validator %#x of operator %v", pubkey, operator.ID)
+		}
+	}
+	return nil
+}
+
+// Attributes the blocks of the epochs from startEpoch to endEpoch to their builders again, this is required after
+// adding new builder patterns as the exporter only attributes the blocks of the last day
+func AttributeBlockBuilders(startEpoch, endEpoch uint64) error {
+	if endEpoch < startEpoch {
+		return fmt.Errorf("invalid epoch range %v-%v", startEpoch, endEpoch)
+	}
+	const batchSize = 100
+
+	for epoch := startEpoch; epoch <= endEpoch; epoch += batchSize {
+		lastEpoch := epoch + batchSize - 1
+		if lastEpoch > endEpoch {
+			lastEpoch = endEpoch
+		}
+		attributed, err := db.AttributeBlockBuilders(epoch*utils.Config.Chain.Config.SlotsPerEpoch, (lastEpoch+1)*utils.Config.Chain.Config.SlotsPerEpoch-1)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("attributed %v blocks of epochs %v-%v to builders", attributed, epoch, lastEpoch)
+	}
+	return nil
+}
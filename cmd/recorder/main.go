@@ -0,0 +1,37 @@
+package main
+
+import (
+	"eth2-exporter/rpc"
+	"eth2-exporter/version"
+	"flag"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	upstream := flag.String("upstream", "http://localhost:4000", "Endpoint of the beacon node or execution client to record")
+	fixtures := flag.String("fixtures", "fixtures", "Directory the fixtures are stored in")
+	listen := flag.String("listen", "127.0.0.1:5052", "Address the proxy listens on")
+	replay := flag.Bool("replay", false, "Serve the stored fixtures instead of recording new ones")
+	flag.Parse()
+
+	logrus.WithField("version", version.Version).WithField("upstream", *upstream).WithField("fixtures", *fixtures).Printf("starting")
+
+	var handler http.Handler
+	var err error
+	if *replay {
+		handler, err = rpc.NewFixtureReplayer(*fixtures)
+	} else {
+		handler, err = rpc.NewFixtureRecorder(*upstream, *fixtures)
+	}
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	logrus.Infof("listening on %v", *listen)
+	err = http.ListenAndServe(*listen, handler)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+)
+
+// SaveBlockArrival stores the time at which the block event of a block was received, the earliest arrival is kept
+func SaveBlockArrival(slot uint64, blockRoot []byte, receivedAt time.Time) error {
+	delay := receivedAt.Sub(utils.SlotToTime(slot)).Milliseconds()
+	_, err := WriterDb.Exec(`
+		INSERT INTO blocks_arrival (slot, blockroot, block_received_at, block_delay_ms)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (slot, blockroot) DO UPDATE SET
+			block_received_at = LEAST(blocks_arrival.block_received_at, excluded.block_received_at),
+			block_delay_ms = LEAST(blocks_arrival.block_delay_ms, excluded.block_delay_ms)`,
+		slot, blockRoot, receivedAt, delay)
+	if err != nil {
+		return fmt.Errorf("error saving block arrival of block %#x at slot %v: %v", blockRoot, slot, err)
+	}
+	return nil
+}
+
+// SaveHeadArrival stores the time at which a block first became the head of the chain
+func SaveHeadArrival(slot uint64, blockRoot []byte, receivedAt time.Time) error {
+	delay := receivedAt.Sub(utils.SlotToTime(slot)).Milliseconds()
+	_, err := WriterDb.Exec(`
+		INSERT INTO blocks_arrival (slot, blockroot, head_received_at, head_delay_ms)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (slot, blockroot) DO UPDATE SET
+			head_received_at = LEAST(blocks_arrival.head_received_at, excluded.head_received_at),
+			head_delay_ms = LEAST(blocks_arrival.head_delay_ms, excluded.head_delay_ms)`,
+		slot, blockRoot, receivedAt, delay)
+	if err != nil {
+		return fmt.Errorf("error saving head arrival of block %#x at slot %v: %v", blockRoot, slot, err)
+	}
+	return nil
+}
+
+// GetProposerBlockArrival returns the number of blocks of a proposer with a known arrival time, their average arrival
+// delay and the number of blocks that arrived after the late block threshold
+func GetProposerBlockArrival(proposer uint64) (*types.ValidatorBlockArrival, error) {
+	arrival := &types.ValidatorBlockArrival{}
+	err := ReaderDb.Get(arrival, `
+		SELECT
+			COUNT(*) AS blocks,
+			COALESCE(AVG(blocks_arrival.block_delay_ms), 0) AS avg_delay_ms,
+			COUNT(*) FILTER (WHERE blocks_arrival.block_delay_ms > $2) AS late_blocks
+		FROM blocks
+		INNER JOIN blocks_arrival ON blocks_arrival.slot = blocks.slot AND blocks_arrival.blockroot = blocks.blockroot
+		WHERE blocks.proposer = $1 AND blocks_arrival.block_delay_ms IS NOT NULL`,
+		proposer, utils.LateBlockThreshold().Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block arrival of proposer %v: %v", proposer, err)
+	}
+	return arrival, nil
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// GetAttestationSlotContexts returns the status and arrival delay of the block proposed at each slot and the number of
+// votes for the slot that were included in canonical blocks, grouped by whether they voted for the canonical head
+func GetAttestationSlotContexts(slots []uint64) (map[uint64]*types.AttestationSlotContext, error) {
+	deadlines := make([]uint64, 0, len(slots))
+	for _, slot := range slots {
+		deadlines = append(deadlines, utils.AttestationInclusionDeadline(slot))
+	}
+
+	contexts := []*types.AttestationSlotContext{}
+	err := ReaderDb.Select(&contexts, `
+		SELECT
+			s.slot,
+			COALESCE(b.status, '') AS block_status,
+			arrival.block_delay_ms,
+			COALESCE(votes.included_votes, 0) AS included_votes,
+			COALESCE(votes.correct_head_votes, 0) AS correct_head_votes
+		FROM unnest($1::int[], $2::int[]) AS s(slot, deadline)
+		LEFT JOIN LATERAL (
+			SELECT status, blockroot FROM blocks WHERE blocks.slot = s.slot ORDER BY status = '1' DESC LIMIT 1
+		) b ON true
+		LEFT JOIN blocks_arrival arrival ON arrival.slot = s.slot AND arrival.blockroot = b.blockroot
+		LEFT JOIN LATERAL (
+			SELECT blockroot FROM blocks WHERE blocks.slot <= s.slot AND blocks.status = '1' ORDER BY blocks.slot DESC LIMIT 1
+		) head ON true
+		LEFT JOIN LATERAL (
+			SELECT
+				SUM(cardinality(ba.validators)) AS included_votes,
+				SUM(cardinality(ba.validators)) FILTER (WHERE ba.beaconblockroot = head.blockroot) AS correct_head_votes
+			FROM blocks_attestations ba
+			INNER JOIN blocks ON blocks.slot = ba.block_slot AND blocks.blockroot = ba.block_root AND blocks.status = '1'
+			WHERE ba.block_slot BETWEEN s.slot + 1 AND s.deadline AND ba.slot = s.slot
+		) votes ON true`, pq.Array(slots), pq.Array(deadlines))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestation context of %v slots: %v", len(slots), err)
+	}
+
+	res := make(map[uint64]*types.AttestationSlotContext, len(contexts))
+	for _, c := range contexts {
+		res[c.Slot] = c
+	}
+	return res, nil
+}
+
+// ClassifyMissedAttestations sets the reason of every missed or too late included attestation in the history. The
+// history of a validator has to be ordered by epoch, as returned by GetValidatorAttestationHistory. Duties of the last
+// two epochs can still be included and are not classified.
+func ClassifyMissedAttestations(history map[uint64][]*types.ValidatorAttestation) error {
+	currentEpoch := utils.TimeToEpoch(time.Now())
+	lateInclusionDelay := uint64(math.Sqrt(float64(utils.Config.Chain.Config.SlotsPerEpoch)))
+
+	slots := []uint64{}
+	seen := make(map[uint64]bool)
+	for _, attestations := range history {
+		for _, a := range attestations {
+			if a.Status == 0 && int64(a.Epoch) < currentEpoch-1 && !seen[a.AttesterSlot] {
+				seen[a.AttesterSlot] = true
+				slots = append(slots, a.AttesterSlot)
+			}
+		}
+	}
+
+	contexts := map[uint64]*types.AttestationSlotContext{}
+	if len(slots) > 0 {
+		var err error
+		contexts, err = GetAttestationSlotContexts(slots)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, attestations := range history {
+		for i, a := range attestations {
+			if a.Status == 1 {
+				if a.InclusionSlot-a.AttesterSlot > lateInclusionDelay {
+					a.MissedReason = types.MissedAttestationReasonIncludedTooLate
+				}
+				continue
+			}
+			if int64(a.Epoch) >= currentEpoch-1 {
+				continue
+			}
+			a.MissedReason = classifyMissedAttestation(attestations, i, contexts[a.AttesterSlot], currentEpoch)
+		}
+	}
+	return nil
+}
+
+func classifyMissedAttestation(attestations []*types.ValidatorAttestation, i int, ctx *types.AttestationSlotContext, currentEpoch int64) string {
+	// a validator that also missed its neighbouring duties is most likely offline
+	neighbours := 0
+	missedNeighbours := 0
+	for _, j := range []int{i - 1, i + 1} {
+		if j < 0 || j >= len(attestations) || int64(attestations[j].Epoch) >= currentEpoch-1 {
+			continue
+		}
+		neighbours++
+		if attestations[j].Status == 0 {
+			missedNeighbours++
+		}
+	}
+	if neighbours > 0 && neighbours == missedNeighbours {
+		return types.MissedAttestationReasonOffline
+	}
+
+	if ctx == nil {
+		return ""
+	}
+	if ctx.BlockStatus == "2" || ctx.BlockStatus == "3" {
+		return types.MissedAttestationReasonBlockMissing
+	}
+	if ctx.BlockDelayMs.Valid && ctx.BlockDelayMs.Int64 > utils.LateBlockThreshold().Milliseconds() {
+		return types.MissedAttestationReasonBlockLate
+	}
+	if ctx.IncludedVotes == 0 {
+		return ""
+	}
+	// the vote of the validator is unknown, the remaining reasons are guessed from the votes of the rest of the network
+	if ctx.CorrectHeadVotes*2 < ctx.IncludedVotes {
+		return types.MissedAttestationReasonWrongHeadGuess
+	}
+	return types.MissedAttestationReasonNotAggregatedGuess
+}
@@ -23,11 +23,21 @@ const (
 	ATTESTATIONS_FAMILY       = "at"
 	PROPOSALS_FAMILY          = "pr"
 	SYNC_COMMITTEES_FAMILY    = "sc"
+	VALIDATOR_REWARDS_FAMILY  = "vr"
 
 	max_block_number = 1000000000
 	max_epoch        = 1000000000
 )
 
+// flags stored in the attestation cell of a validator, cells without the checked flag have been written before the
+// votes were checked
+const (
+	attestationVotesChecked byte = 1 << iota
+	attestationSourceCorrect
+	attestationTargetCorrect
+	attestationHeadCorrect
+)
+
 type Bigtable struct {
 	client *gcp_bigtable.Client
 
@@ -214,14 +224,20 @@ func (bigtable *Bigtable) SaveSyncCommitteesAssignments(startSlot, endSlot uint6
 	return nil
 }
 
-func (bigtable *Bigtable) SaveAttestations(blocks map[uint64]map[string]*types.Block) error {
+// SaveAttestations stores the earliest inclusion of every attestation duty together with the correctness of its votes,
+// the votes are only checked if the canonical block roots are provided
+func (bigtable *Bigtable) SaveAttestations(blocks map[uint64]map[string]*types.Block, canonicalRoots *types.CanonicalBlockRoots) error {
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 
 	start := time.Now()
 
-	attestationsBySlot := make(map[uint64]map[uint64]uint64) //map[attestedSlot]map[validator]includedSlot
+	type inclusion struct {
+		slot  uint64
+		votes types.AttestationVotes
+	}
+	attestationsBySlot := make(map[uint64]map[uint64]*inclusion) //map[attestedSlot]map[validator]inclusion
 
 	slots := make([]uint64, 0, len(blocks))
 	for slot := range blocks {
@@ -235,15 +251,16 @@ func (bigtable *Bigtable) SaveAttestations(blocks map[uint64]map[string]*types.B
 		for _, b := range blocks[slot] {
 			logger.Infof("processing slot %v", slot)
 			for _, a := range b.Attestations {
+				votes := utils.CheckAttestationVotes(a.Data, canonicalRoots)
 				for _, validator := range a.Attesters {
 					inclusionSlot := slot
 					attestedSlot := a.Data.Slot
 					if attestationsBySlot[attestedSlot] == nil {
-						attestationsBySlot[attestedSlot] = make(map[uint64]uint64)
+						attestationsBySlot[attestedSlot] = make(map[uint64]*inclusion)
 					}
 
-					if attestationsBySlot[attestedSlot][validator] == 0 || inclusionSlot < attestationsBySlot[attestedSlot][validator] {
-						attestationsBySlot[attestedSlot][validator] = inclusionSlot
+					if attestationsBySlot[attestedSlot][validator] == nil || inclusionSlot < attestationsBySlot[attestedSlot][validator].slot {
+						attestationsBySlot[attestedSlot][validator] = &inclusion{slot: inclusionSlot, votes: votes}
 					}
 				}
 			}
@@ -252,8 +269,8 @@ func (bigtable *Bigtable) SaveAttestations(blocks map[uint64]map[string]*types.B
 
 	for attestedSlot, inclusions := range attestationsBySlot {
 		mut := gcp_bigtable.NewMutation()
-		for validator, inclusionSlot := range inclusions {
-			mut.Set(ATTESTATIONS_FAMILY, fmt.Sprintf("%d", validator), gcp_bigtable.Timestamp((max_block_number-inclusionSlot)*1000), []byte{})
+		for validator, inclusion := range inclusions {
+			mut.Set(ATTESTATIONS_FAMILY, fmt.Sprintf("%d", validator), gcp_bigtable.Timestamp((max_block_number-inclusion.slot)*1000), encodeAttestationVotes(inclusion.votes))
 		}
 		err := bigtable.tableBeaconchain.Apply(ctx, fmt.Sprintf("%s:e:%s:s:%s", bigtable.chainId, reversedPaddedEpoch(attestedSlot/utils.Config.Chain.Config.SlotsPerEpoch), reversedPaddedSlot(attestedSlot)), mut)
 
@@ -265,6 +282,35 @@ func (bigtable *Bigtable) SaveAttestations(blocks map[uint64]map[string]*types.B
 	return nil
 }
 
+func encodeAttestationVotes(votes types.AttestationVotes) []byte {
+	if !votes.Checked {
+		return []byte{}
+	}
+	flags := attestationVotesChecked
+	if votes.Source {
+		flags |= attestationSourceCorrect
+	}
+	if votes.Target {
+		flags |= attestationTargetCorrect
+	}
+	if votes.Head {
+		flags |= attestationHeadCorrect
+	}
+	return []byte{flags}
+}
+
+func decodeAttestationVotes(value []byte) types.AttestationVotes {
+	if len(value) == 0 || value[0]&attestationVotesChecked == 0 {
+		return types.AttestationVotes{}
+	}
+	return types.AttestationVotes{
+		Checked: true,
+		Source:  value[0]&attestationSourceCorrect != 0,
+		Target:  value[0]&attestationTargetCorrect != 0,
+		Head:    value[0]&attestationHeadCorrect != 0,
+	}
+}
+
 func (bigtable *Bigtable) SaveProposals(blocks map[uint64]map[string]*types.Block) error {
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
@@ -435,6 +481,144 @@ func (bigtable *Bigtable) GetValidatorBalanceHistory(validators []uint64, startE
 	return res, nil
 }
 
+func (bigtable *Bigtable) SaveValidatorRewards(epoch uint64, rewards map[uint64]*types.ValidatorEpochRewards) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	start := time.Now()
+	ts := gcp_bigtable.Timestamp(0)
+	rowKey := fmt.Sprintf("%s:e:r:%s", bigtable.chainId, reversedPaddedEpoch(epoch))
+
+	mut := gcp_bigtable.NewMutation()
+	i := 0
+	for validator, reward := range rewards {
+		mut.Set(VALIDATOR_REWARDS_FAMILY, fmt.Sprintf("%d", validator), ts, encodeValidatorRewards(reward))
+		i++
+
+		if i%100000 == 0 {
+			err := bigtable.tableBeaconchain.Apply(ctx, rowKey, mut)
+			if err != nil {
+				return err
+			}
+			mut = gcp_bigtable.NewMutation()
+		}
+	}
+	err := bigtable.tableBeaconchain.Apply(ctx, rowKey, mut)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("exported validator rewards of epoch %v to bigtable in %v", epoch, time.Since(start))
+	return nil
+}
+
+func (bigtable *Bigtable) GetValidatorRewardsHistory(validators []uint64, startEpoch uint64, limit int64) (map[uint64][]*types.ValidatorEpochRewards, error) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	rangeStart := fmt.Sprintf("%s:e:r:%s", bigtable.chainId, reversedPaddedEpoch(startEpoch))
+	rangeEnd := fmt.Sprintf("%s:e:r:%s", bigtable.chainId, reversedPaddedEpoch(startEpoch-uint64(limit)))
+	res := make(map[uint64][]*types.ValidatorEpochRewards, len(validators))
+
+	if len(validators) == 0 {
+		return res, nil
+	}
+
+	columnFilters := make([]gcp_bigtable.Filter, 0, len(validators))
+	for _, validator := range validators {
+		columnFilters = append(columnFilters, gcp_bigtable.ColumnFilter(fmt.Sprintf("%d", validator)))
+	}
+
+	filter := gcp_bigtable.ChainFilters(
+		gcp_bigtable.FamilyFilter(VALIDATOR_REWARDS_FAMILY),
+		gcp_bigtable.InterleaveFilters(columnFilters...),
+	)
+
+	if len(columnFilters) == 1 { // special case to retrieve data for one validators
+		filter = gcp_bigtable.ChainFilters(
+			gcp_bigtable.FamilyFilter(VALIDATOR_REWARDS_FAMILY),
+			columnFilters[0],
+		)
+	}
+
+	err := bigtable.tableBeaconchain.ReadRows(ctx, gcp_bigtable.NewRange(rangeStart, rangeEnd), func(r gcp_bigtable.Row) bool {
+		for _, ri := range r[VALIDATOR_REWARDS_FAMILY] {
+			validator, err := strconv.ParseUint(strings.TrimPrefix(ri.Column, VALIDATOR_REWARDS_FAMILY+":"), 10, 64)
+			if err != nil {
+				logger.Errorf("error parsing validator from column key %v: %v", ri.Column, err)
+				return false
+			}
+
+			keySplit := strings.Split(r.Key(), ":")
+
+			epoch, err := strconv.ParseUint(keySplit[3], 10, 64)
+			if err != nil {
+				logger.Errorf("error parsing epoch from row key %v: %v", r.Key(), err)
+				return false
+			}
+
+			reward, err := decodeValidatorRewards(ri.Value)
+			if err != nil {
+				logger.Errorf("error decoding rewards of validator %v for row key %v: %v", validator, r.Key(), err)
+				return false
+			}
+			reward.ValidatorIndex = validator
+			reward.Epoch = max_epoch - epoch
+
+			if res[validator] == nil {
+				res[validator] = make([]*types.ValidatorEpochRewards, 0, limit)
+			}
+			res[validator] = append(res[validator], reward)
+		}
+		return true
+	}, gcp_bigtable.LimitRows(limit), gcp_bigtable.RowFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// encodeValidatorRewards encodes the reward components of a validator as consecutive little endian int64 values
+func encodeValidatorRewards(reward *types.ValidatorEpochRewards) []byte {
+	components := []int64{
+		reward.AttestationSource,
+		reward.AttestationTarget,
+		reward.AttestationHead,
+		reward.AttestationInclusionDelay,
+		reward.AttestationInactivity,
+		reward.ProposerAttestationInclusion,
+		reward.ProposerSyncInclusion,
+		reward.ProposerSlashingInclusion,
+		reward.SyncCommittee,
+	}
+	encoded := make([]byte, 8*len(components))
+	for i, c := range components {
+		binary.LittleEndian.PutUint64(encoded[i*8:], uint64(c))
+	}
+	return encoded
+}
+
+func decodeValidatorRewards(encoded []byte) (*types.ValidatorEpochRewards, error) {
+	if len(encoded) != 72 {
+		return nil, fmt.Errorf("invalid encoded rewards length %v", len(encoded))
+	}
+	component := func(i int) int64 {
+		return int64(binary.LittleEndian.Uint64(encoded[i*8:]))
+	}
+	return &types.ValidatorEpochRewards{
+		AttestationSource:            component(0),
+		AttestationTarget:            component(1),
+		AttestationHead:              component(2),
+		AttestationInclusionDelay:    component(3),
+		AttestationInactivity:        component(4),
+		ProposerAttestationInclusion: component(5),
+		ProposerSyncInclusion:        component(6),
+		ProposerSlashingInclusion:    component(7),
+		SyncCommittee:                component(8),
+	}, nil
+}
+
 func (bigtable *Bigtable) GetValidatorAttestationHistory(validators []uint64, startEpoch uint64, limit int64) (map[uint64][]*types.ValidatorAttestation, error) {
 	valLen := len(validators)
 
@@ -504,6 +688,7 @@ func (bigtable *Bigtable) GetValidatorAttestationHistory(validators []uint64, st
 				res[validator][len(res[validator])-1].InclusionSlot = inclusionSlot
 				res[validator][len(res[validator])-1].Status = status
 				res[validator][len(res[validator])-1].Delay = int64(inclusionSlot - attesterSlot)
+				res[validator][len(res[validator])-1].Votes = decodeAttestationVotes(ri.Value)
 			} else {
 				res[validator] = append(res[validator], &types.ValidatorAttestation{
 					Index:          validator,
@@ -513,6 +698,7 @@ func (bigtable *Bigtable) GetValidatorAttestationHistory(validators []uint64, st
 					Status:         status,
 					InclusionSlot:  inclusionSlot,
 					Delay:          int64(inclusionSlot) - int64(attesterSlot) - 1,
+					Votes:          decodeAttestationVotes(ri.Value),
 				})
 			}
 
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"database/sql"
+	"eth2-exporter/types"
+	"fmt"
+)
+
+// AttributeBlockBuilders attributes the proposed post-merge blocks between fromSlot and toSlot to their builder. The
+// builder pubkey reported by the relays takes precedence over the fee recipient and extra data patterns of the known builders.
+func AttributeBlockBuilders(fromSlot, toSlot uint64) (int64, error) {
+	res, err := WriterDb.Exec(`
+		INSERT INTO blocks_builders (slot, blockroot, builder_id, builder_pubkey, source)
+		SELECT
+			b.slot,
+			b.blockroot,
+			COALESCE(pk.builder_id, fr.builder_id, ed.builder_id),
+			rb.builder_pubkey,
+			CASE
+				WHEN rb.builder_pubkey IS NOT NULL THEN 'relay'
+				WHEN fr.builder_id IS NOT NULL THEN 'fee_recipient'
+				WHEN ed.builder_id IS NOT NULL THEN 'extra_data'
+				ELSE 'none'
+			END
+		FROM blocks b
+		LEFT JOIN LATERAL (
+			SELECT builder_pubkey FROM relays_blocks WHERE relays_blocks.block_slot = b.slot AND relays_blocks.block_root = b.blockroot LIMIT 1
+		) rb ON true
+		LEFT JOIN LATERAL (
+			SELECT builder_id FROM builder_patterns WHERE kind = 'pubkey' AND pattern = rb.builder_pubkey LIMIT 1
+		) pk ON true
+		LEFT JOIN LATERAL (
+			SELECT builder_id FROM builder_patterns WHERE kind = 'fee_recipient' AND pattern = b.exec_fee_recipient LIMIT 1
+		) fr ON true
+		LEFT JOIN LATERAL (
+			SELECT builder_id FROM builder_patterns WHERE kind = 'extra_data' AND position(pattern IN b.exec_extra_data) > 0 LIMIT 1
+		) ed ON true
+		WHERE b.slot >= $1 AND b.slot <= $2 AND b.status = '1' AND length(b.exec_block_hash) > 0
+		ON CONFLICT (slot, blockroot) DO UPDATE SET
+			builder_id = excluded.builder_id,
+			builder_pubkey = excluded.builder_pubkey,
+			source = excluded.source`, fromSlot, toSlot)
+	if err != nil {
+		return 0, fmt.Errorf("error attributing builders of slots %v-%v: %v", fromSlot, toSlot, err)
+	}
+	return res.RowsAffected()
+}
+
+// GetFirstExecutionBlockSlot returns the slot of the first block containing an execution payload, ok is false if no
+// such block has been exported yet. Blocks before the merge store an empty execution block hash.
+func GetFirstExecutionBlockSlot() (slot uint64, ok bool, err error) {
+	var first sql.NullInt64
+	err = ReaderDb.Get(&first, `SELECT MIN(slot) FROM blocks WHERE length(exec_block_hash) > 0`)
+	if err != nil {
+		return 0, false, fmt.Errorf("error retrieving first execution block slot: %v", err)
+	}
+	return uint64(first.Int64), first.Valid, nil
+}
+
+// GetLastAttributedBuilderSlot returns the slot of the most recent block that has been attributed to a builder
+func GetLastAttributedBuilderSlot() (uint64, error) {
+	var slot uint64
+	err := ReaderDb.Get(&slot, `SELECT COALESCE(MAX(slot), 0) FROM blocks_builders`)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving last attributed builder slot: %v", err)
+	}
+	return slot, nil
+}
+
+// GetBuilderLeaderboard returns the blocks per builder since fromSlot ordered by the number of blocks
+func GetBuilderLeaderboard(fromSlot uint64) ([]*types.BuilderLeaderboardEntry, error) {
+	builders := []*types.BuilderLeaderboardEntry{}
+	err := ReaderDb.Select(&builders, `
+		WITH attributed AS (
+			SELECT
+				slot,
+				blockroot,
+				builder_id,
+				builder_pubkey,
+				COALESCE(builder_id, '0x' || encode(builder_pubkey, 'hex'), 'unknown') AS builder
+			FROM blocks_builders
+			WHERE slot >= $1
+		), relayed AS (
+			SELECT block_slot, block_root, MAX(value) AS value
+			FROM relays_blocks
+			WHERE block_slot >= $1
+			GROUP BY block_slot, block_root
+		), builder_relays AS (
+			SELECT a.builder, array_agg(DISTINCT COALESCE(tags.metadata ->> 'name', rb.tag_id)) AS relays
+			FROM attributed a
+			INNER JOIN relays_blocks rb ON rb.block_slot = a.slot AND rb.block_root = a.blockroot
+			LEFT JOIN tags ON tags.id = rb.tag_id
+			GROUP BY a.builder
+		)
+		SELECT
+			a.builder,
+			COALESCE(MAX(builders.name), '') AS name,
+			COALESCE(MAX(builders.link), '') AS link,
+			COUNT(DISTINCT a.builder_pubkey) AS pubkeys,
+			COUNT(*) AS block_count,
+			COUNT(*)::float / (SELECT COUNT(*) FROM attributed) AS block_share,
+			COUNT(relayed.value) AS relay_blocks,
+			COALESCE(ROUND(AVG(relayed.value)), 0) AS avg_value,
+			COALESCE(SUM(relayed.value), 0) AS total_value,
+			COALESCE(MAX(builder_relays.relays), '{}') AS relays,
+			MAX(a.slot) AS latest_slot
+		FROM attributed a
+		LEFT JOIN builders ON builders.id = a.builder_id
+		LEFT JOIN relayed ON relayed.block_slot = a.slot AND relayed.block_root = a.blockroot
+		LEFT JOIN builder_relays ON builder_relays.builder = a.builder
+		GROUP BY a.builder
+		ORDER BY block_count DESC`, fromSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving builder leaderboard: %v", err)
+	}
+	return builders, nil
+}
@@ -540,6 +540,97 @@ func GetValidatorIndex(publicKey []byte) (uint64, error) {
 	return index, err
 }
 
+// GetValidatorWithdrawals will return a page of the withdrawals of a validator included in canonical blocks from the database
+func GetValidatorWithdrawals(validatorIndex uint64, limit uint64, offset uint64) ([]*types.Withdrawal, error) {
+	var withdrawals []*types.Withdrawal
+	err := ReaderDb.Select(&withdrawals, `
+		SELECT
+			blocks_withdrawals.block_slot AS slot,
+			blocks_withdrawals.block_root AS blockroot,
+			blocks_withdrawals.withdrawalindex AS index,
+			blocks_withdrawals.validatorindex,
+			blocks_withdrawals.address,
+			blocks_withdrawals.amount
+		FROM blocks_withdrawals
+		INNER JOIN blocks ON blocks_withdrawals.block_root = blocks.blockroot AND blocks.status = '1'
+		WHERE blocks_withdrawals.validatorindex = $1
+		ORDER BY blocks_withdrawals.withdrawalindex DESC
+		LIMIT $2 OFFSET $3`, validatorIndex, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting withdrawals of validator %v: %v", validatorIndex, err)
+	}
+	return withdrawals, nil
+}
+
+// GetValidatorWithdrawalsCount will return the number of withdrawals of a validator included in canonical blocks from the database
+func GetValidatorWithdrawalsCount(validatorIndex uint64) (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, `
+		SELECT COUNT(*)
+		FROM blocks_withdrawals
+		INNER JOIN blocks ON blocks_withdrawals.block_root = blocks.blockroot AND blocks.status = '1'
+		WHERE blocks_withdrawals.validatorindex = $1`, validatorIndex)
+	if err != nil {
+		return 0, fmt.Errorf("error getting withdrawals count of validator %v: %v", validatorIndex, err)
+	}
+	return count, nil
+}
+
+// GetValidatorBLSChange will return the bls to execution changes of a validator included in canonical blocks from the database
+func GetValidatorBLSChange(validatorIndex uint64) ([]*types.BLSChange, error) {
+	var changes []*types.BLSChange
+	err := ReaderDb.Select(&changes, `
+		SELECT
+			blocks_bls_change.block_slot AS slot,
+			blocks_bls_change.block_root,
+			blocks_bls_change.validatorindex,
+			blocks_bls_change.signature,
+			blocks_bls_change.pubkey,
+			blocks_bls_change.address
+		FROM blocks_bls_change
+		INNER JOIN blocks ON blocks_bls_change.block_root = blocks.blockroot AND blocks.status = '1'
+		WHERE blocks_bls_change.validatorindex = $1
+		ORDER BY blocks_bls_change.block_slot DESC`, validatorIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bls to execution changes of validator %v: %v", validatorIndex, err)
+	}
+	return changes, nil
+}
+
+// GetValidatorWithdrawalCredentialsHistory will return all recorded changes of the withdrawal credentials of a validator
+func GetValidatorWithdrawalCredentialsHistory(validatorIndex uint64) ([]*types.WithdrawalCredentialsChange, error) {
+	var changes []*types.WithdrawalCredentialsChange
+	err := ReaderDb.Select(&changes, `
+		SELECT validatorindex, epoch, old_withdrawalcredentials, new_withdrawalcredentials
+		FROM validator_withdrawalcredentials_history
+		WHERE validatorindex = $1
+		ORDER BY epoch DESC`, validatorIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error getting withdrawal credentials history of validator %v: %v", validatorIndex, err)
+	}
+	return changes, nil
+}
+
+// GetWithdrawalCredentialsChanged will return all withdrawal credential changes recorded after the given epoch
+func GetWithdrawalCredentialsChanged(epoch uint64) ([]*types.WithdrawalCredentialsChange, error) {
+	var changes []*types.WithdrawalCredentialsChange
+	err := ReaderDb.Select(&changes, `
+		SELECT
+			validator_withdrawalcredentials_history.validatorindex,
+			validator_withdrawalcredentials_history.epoch,
+			validator_withdrawalcredentials_history.old_withdrawalcredentials,
+			validator_withdrawalcredentials_history.new_withdrawalcredentials,
+			validators.pubkey
+		FROM validator_withdrawalcredentials_history
+		INNER JOIN validators ON validators.validatorindex = validator_withdrawalcredentials_history.validatorindex
+		WHERE validator_withdrawalcredentials_history.epoch > $1
+		ORDER BY validator_withdrawalcredentials_history.epoch`, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting withdrawal credential changes after epoch %v: %v", epoch, err)
+	}
+	return changes, nil
+}
+
 // GetValidatorDeposits will return eth1- and eth2-deposits for a public key from the database
 func GetValidatorDeposits(publicKey []byte) (*types.ValidatorDeposits, error) {
 	deposits := &types.ValidatorDeposits{}
@@ -661,6 +752,31 @@ func SaveValidatorQueue(validators *types.ValidatorQueue) error {
 	return err
 }
 
+// GetCanonicalBlockRoots returns the roots of the canonical blocks between fromSlot and toSlot
+func GetCanonicalBlockRoots(fromSlot, toSlot uint64) (*types.CanonicalBlockRoots, error) {
+	blocks := []struct {
+		Slot      uint64
+		BlockRoot []byte
+		Status    string
+	}{}
+	err := ReaderDb.Select(&blocks, `SELECT slot, blockroot, status FROM blocks WHERE slot >= $1 AND slot <= $2 AND status IN ('1', '2')`, fromSlot, toSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving canonical block roots of slots %v-%v: %v", fromSlot, toSlot, err)
+	}
+	roots := &types.CanonicalBlockRoots{
+		StartSlot: fromSlot,
+		Roots:     make(map[uint64][]byte, len(blocks)),
+		Known:     make(map[uint64]bool, len(blocks)),
+	}
+	for _, b := range blocks {
+		roots.Known[b.Slot] = true
+		if b.Status == "1" {
+			roots.Roots[b.Slot] = b.BlockRoot
+		}
+	}
+	return roots, nil
+}
+
 func SaveBlock(block *types.Block) error {
 
 	blocksMap := make(map[uint64]map[string]*types.Block)
@@ -738,8 +854,17 @@ func SaveEpoch(data *types.EpochData) error {
 		return fmt.Errorf("error saving blocks to db: %w", err)
 	}
 
+	// the validator state and the withdrawal credential history must only move forward, re-exported or backfilled
+	// epochs older than the latest exported epoch would overwrite it with outdated data
+	latestEpoch, err := GetLatestEpoch()
+	if err != nil {
+		return err
+	}
+
 	if uint64(utils.TimeToEpoch(time.Now())) > data.Epoch+10 {
 		logger.WithFields(logrus.Fields{"exportEpoch": data.Epoch, "chainEpoch": utils.TimeToEpoch(time.Now())}).Infof("skipping exporting validators because epoch is far behind head")
+	} else if data.Epoch < latestEpoch {
+		logger.WithFields(logrus.Fields{"exportEpoch": data.Epoch, "latestEpoch": latestEpoch}).Infof("skipping exporting validators because epoch is older than the latest exported epoch")
 	} else {
 		go func() {
 			logger.Infof("exporting validators for epoch %v", data.Epoch)
@@ -793,6 +918,8 @@ func SaveEpoch(data *types.EpochData) error {
 	attestationsCount := 0
 	depositCount := 0
 	voluntaryExitCount := 0
+	blobsCount := 0
+	blobGasUsed := uint64(0)
 
 	for _, slot := range data.Blocks {
 		for _, b := range slot {
@@ -801,6 +928,10 @@ func SaveEpoch(data *types.EpochData) error {
 			attestationsCount += len(b.Attestations)
 			depositCount += len(b.Deposits)
 			voluntaryExitCount += len(b.VoluntaryExits)
+			blobsCount += len(b.BlobKZGCommitments)
+			if b.ExecutionPayload != nil {
+				blobGasUsed += b.ExecutionPayload.BlobGasUsed
+			}
 		}
 	}
 
@@ -830,9 +961,11 @@ func SaveEpoch(data *types.EpochData) error {
 			finalized, 
 			eligibleether, 
 			globalparticipationrate, 
-			votedether
+			votedether,
+			blobscount,
+			blob_gas_used
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) 
 		ON CONFLICT (epoch) DO UPDATE SET 
 			blockscount             = excluded.blockscount, 
 			proposerslashingscount  = excluded.proposerslashingscount,
@@ -845,7 +978,9 @@ func SaveEpoch(data *types.EpochData) error {
 			totalvalidatorbalance   = excluded.totalvalidatorbalance,
 			eligibleether           = excluded.eligibleether,
 			globalparticipationrate = excluded.globalparticipationrate,
-			votedether              = excluded.votedether`,
+			votedether              = excluded.votedether,
+			blobscount              = excluded.blobscount,
+			blob_gas_used           = excluded.blob_gas_used`,
 		data.Epoch,
 		len(data.Blocks),
 		proposerSlashingsCount,
@@ -859,7 +994,9 @@ func SaveEpoch(data *types.EpochData) error {
 		false,
 		data.EpochParticipationStats.EligibleEther,
 		data.EpochParticipationStats.GlobalParticipationRate,
-		data.EpochParticipationStats.VotedEther)
+		data.EpochParticipationStats.VotedEther,
+		blobsCount,
+		blobGasUsed)
 
 	if err != nil {
 		return fmt.Errorf("error executing save epoch statement: %w", err)
@@ -1246,6 +1383,32 @@ func saveValidators(data *types.EpochData, tx *sqlx.Tx) error {
 			valueArgs = append(valueArgs, v.Status)
 			valueArgs = append(valueArgs, v.LastAttestationSlot)
 		}
+		// keep track of changed withdrawal credentials (bls to execution changes) before they are overwritten. A change is
+		// only recorded if it is newer than the latest recorded change of the validator and never reverts execution
+		// withdrawal credentials back to bls credentials, so re-exporting older epochs can not corrupt the history
+		batchIndices := make([]uint64, 0, end-start)
+		batchCredentials := make([][]byte, 0, end-start)
+		for _, v := range validators[start:end] {
+			batchIndices = append(batchIndices, v.Index)
+			batchCredentials = append(batchCredentials, v.WithdrawalCredentials)
+		}
+		_, err = tx.Exec(`
+			INSERT INTO validator_withdrawalcredentials_history (validatorindex, epoch, old_withdrawalcredentials, new_withdrawalcredentials)
+			SELECT validators.validatorindex, $1, validators.withdrawalcredentials, changes.withdrawalcredentials
+			FROM UNNEST($2::int[], $3::bytea[]) AS changes(validatorindex, withdrawalcredentials)
+			INNER JOIN validators ON validators.validatorindex = changes.validatorindex
+			WHERE validators.withdrawalcredentials <> changes.withdrawalcredentials
+				AND NOT (get_byte(validators.withdrawalcredentials, 0) <> 0 AND get_byte(changes.withdrawalcredentials, 0) = 0)
+				AND NOT EXISTS (
+					SELECT 1 FROM validator_withdrawalcredentials_history h
+					WHERE h.validatorindex = changes.validatorindex AND h.epoch >= $1
+				)
+			ON CONFLICT (validatorindex, epoch) DO NOTHING`,
+			data.Epoch, pq.Array(batchIndices), pq.ByteaArray(batchCredentials))
+		if err != nil {
+			return fmt.Errorf("error saving withdrawal credential changes: %w", err)
+		}
+
 		stmt := fmt.Sprintf(`
 			INSERT INTO validators (
 				validatorindex,
@@ -1268,6 +1431,11 @@ func saveValidators(data *types.EpochData, tx *sqlx.Tx) error {
 			VALUES %[3]s
 			ON CONFLICT (validatorindex) DO UPDATE SET 
 				withdrawableepoch          = EXCLUDED.withdrawableepoch,
+				withdrawalcredentials      = 
+					CASE
+					WHEN get_byte(validators.withdrawalcredentials, 0) <> 0 AND get_byte(EXCLUDED.withdrawalcredentials, 0) = 0 THEN validators.withdrawalcredentials
+					ELSE EXCLUDED.withdrawalcredentials
+					END,
 				balance                    = EXCLUDED.balance,
 				effectivebalance           = EXCLUDED.effectivebalance,
 				slashed                    = EXCLUDED.slashed,
@@ -1413,8 +1581,8 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sqlx.Tx) error {
 	}()
 
 	stmtBlock, err := tx.Prepare(`
-		INSERT INTO blocks (epoch, slot, blockroot, parentroot, stateroot, signature, randaoreveal, graffiti, graffiti_text, eth1data_depositroot, eth1data_depositcount, eth1data_blockhash, syncaggregate_bits, syncaggregate_signature, proposerslashingscount, attesterslashingscount, attestationscount, depositscount, voluntaryexitscount, syncaggregate_participation, proposer, status, exec_parent_hash, exec_fee_recipient, exec_state_root, exec_receipts_root, exec_logs_bloom, exec_random, exec_block_number, exec_gas_limit, exec_gas_used, exec_timestamp, exec_extra_data, exec_base_fee_per_gas, exec_block_hash, exec_transactions_count)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36)
+		INSERT INTO blocks (epoch, slot, blockroot, parentroot, stateroot, signature, randaoreveal, graffiti, graffiti_text, eth1data_depositroot, eth1data_depositcount, eth1data_blockhash, syncaggregate_bits, syncaggregate_signature, proposerslashingscount, attesterslashingscount, attestationscount, depositscount, voluntaryexitscount, syncaggregate_participation, proposer, status, exec_parent_hash, exec_fee_recipient, exec_state_root, exec_receipts_root, exec_logs_bloom, exec_random, exec_block_number, exec_gas_limit, exec_gas_used, exec_timestamp, exec_extra_data, exec_base_fee_per_gas, exec_block_hash, exec_transactions_count, withdrawalcount, bls_change_count, blobs_count, exec_blob_gas_used, exec_excess_blob_gas)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41)
 		ON CONFLICT (slot, blockroot) DO NOTHING`)
 	if err != nil {
 		return err
@@ -1475,6 +1643,33 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sqlx.Tx) error {
 	}
 	defer stmtVoluntaryExits.Close()
 
+	stmtWithdrawals, err := tx.Prepare(`
+		INSERT INTO blocks_withdrawals (block_slot, block_root, withdrawalindex, validatorindex, address, amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (block_slot, block_root, withdrawalindex) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+	defer stmtWithdrawals.Close()
+
+	stmtBLSChange, err := tx.Prepare(`
+		INSERT INTO blocks_bls_change (block_slot, block_root, validatorindex, signature, pubkey, address)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (block_slot, block_root, validatorindex) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+	defer stmtBLSChange.Close()
+
+	stmtBlobSidecars, err := tx.Prepare(`
+		INSERT INTO blocks_blob_sidecars (block_slot, block_root, index, kzg_commitment, kzg_proof, blob_versioned_hash)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (block_root, index) DO UPDATE SET kzg_proof = COALESCE(excluded.kzg_proof, blocks_blob_sidecars.kzg_proof)`)
+	if err != nil {
+		return err
+	}
+	defer stmtBlobSidecars.Close()
+
 	stmtProposalAssignments, err := tx.Prepare(`
 		INSERT INTO proposal_assignments (epoch, validatorindex, proposerslot, status)
 		VALUES ($1, $2, $3, $4)
@@ -1554,6 +1749,8 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sqlx.Tx) error {
 			baseFeePerGas := uint64(0)
 			blockHash := []byte{}
 			txCount := 0
+			withdrawalCount := 0
+			var blobGasUsed, excessBlobGas sql.NullInt64
 			if b.ExecutionPayload != nil {
 				parentHash = b.ExecutionPayload.ParentHash
 				feeRecipient = b.ExecutionPayload.FeeRecipient
@@ -1569,6 +1766,11 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sqlx.Tx) error {
 				baseFeePerGas = b.ExecutionPayload.BaseFeePerGas
 				blockHash = b.ExecutionPayload.BlockHash
 				txCount = len(b.ExecutionPayload.Transactions)
+				withdrawalCount = len(b.ExecutionPayload.Withdrawals)
+				if len(b.BlobKZGCommitments) > 0 || b.ExecutionPayload.ExcessBlobGas > 0 {
+					blobGasUsed = sql.NullInt64{Int64: int64(b.ExecutionPayload.BlobGasUsed), Valid: true}
+					excessBlobGas = sql.NullInt64{Int64: int64(b.ExecutionPayload.ExcessBlobGas), Valid: true}
+				}
 			}
 			_, err = stmtBlock.Exec(
 				b.Slot/utils.Config.Chain.Config.SlotsPerEpoch,
@@ -1607,6 +1809,11 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sqlx.Tx) error {
 				baseFeePerGas,
 				blockHash,
 				txCount,
+				withdrawalCount,
+				len(b.SignedBLSToExecutionChange),
+				len(b.BlobKZGCommitments),
+				blobGasUsed,
+				excessBlobGas,
 			)
 			if err != nil {
 				return fmt.Errorf("error executing stmtBlocks for block %v: %w", b.Slot, err)
@@ -1672,6 +1879,35 @@ func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sqlx.Tx) error {
 			blockLog.WithField("duration", time.Since(t)).Tracef("exits")
 			t = time.Now()
 
+			if payload := b.ExecutionPayload; payload != nil {
+				for _, w := range payload.Withdrawals {
+					_, err := stmtWithdrawals.Exec(b.Slot, b.BlockRoot, w.Index, w.ValidatorIndex, w.Address, w.Amount)
+					if err != nil {
+						return fmt.Errorf("error executing stmtWithdrawals for block %v: %w", b.Slot, err)
+					}
+				}
+			}
+			blockLog.WithField("duration", time.Since(t)).Tracef("withdrawals")
+			t = time.Now()
+
+			for _, c := range b.SignedBLSToExecutionChange {
+				_, err := stmtBLSChange.Exec(b.Slot, b.BlockRoot, c.Message.Validatorindex, c.Signature, c.Message.BlsPubkey, c.Message.Address)
+				if err != nil {
+					return fmt.Errorf("error executing stmtBLSChange for block %v: %w", b.Slot, err)
+				}
+			}
+			blockLog.WithField("duration", time.Since(t)).Tracef("bls_change")
+			t = time.Now()
+
+			for _, sidecar := range b.BlobSidecars {
+				_, err := stmtBlobSidecars.Exec(b.Slot, b.BlockRoot, sidecar.Index, sidecar.KzgCommitment, sidecar.KzgProof, sidecar.BlobVersionedHash)
+				if err != nil {
+					return fmt.Errorf("error executing stmtBlobSidecars for block %v: %w", b.Slot, err)
+				}
+			}
+			blockLog.WithField("duration", time.Since(t)).Tracef("blob_sidecars")
+			t = time.Now()
+
 			_, err = stmtProposalAssignments.Exec(b.Slot/utils.Config.Chain.Config.SlotsPerEpoch, b.Proposer, b.Slot, b.Status)
 			if err != nil {
 				return fmt.Errorf("error executing stmtProposalAssignments for block %v: %w", b.Slot, err)
@@ -1720,6 +1956,74 @@ func UpdateEpochFinalization(finality_epoch uint64) error {
 	return err
 }
 
+// GetOrphanedSlots returns the slots of the blocks on the chain ending in oldHead that are newer than the common ancestor slot
+func GetOrphanedSlots(oldHead []byte, ancestorSlot uint64) ([]int64, error) {
+	var slots []int64
+	err := ReaderDb.Select(&slots, `
+		WITH RECURSIVE old_chain AS (
+			SELECT slot, parentroot FROM blocks WHERE blockroot = $1 AND slot > $2
+			UNION ALL
+			SELECT blocks.slot, blocks.parentroot FROM blocks INNER JOIN old_chain ON blocks.blockroot = old_chain.parentroot WHERE blocks.slot > $2
+		)
+		SELECT slot FROM old_chain ORDER BY slot`, oldHead, ancestorSlot)
+	return slots, err
+}
+
+// GetUnorphanedBlockSlots returns the slots of the passed blocks that are not yet marked as orphaned
+func GetUnorphanedBlockSlots(blockRoots [][]byte) ([]int64, error) {
+	var slots []int64
+	err := ReaderDb.Select(&slots, "SELECT slot FROM blocks WHERE blockroot = ANY($1) AND status != '3' ORDER BY slot", pq.ByteaArray(blockRoots))
+	return slots, err
+}
+
+// SaveChainReorg records a chain reorganization, reorgs that have already been recorded are ignored
+func SaveChainReorg(reorg *types.ChainReorg) error {
+	_, err := WriterDb.Exec(`
+		INSERT INTO chain_reorgs (slot, depth, epoch, old_head_block, new_head_block, old_head_state, new_head_state, affected_slots, source)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (slot, old_head_block, new_head_block) DO NOTHING`,
+		reorg.Slot, reorg.Depth, reorg.Epoch, reorg.OldHeadBlock, reorg.NewHeadBlock, reorg.OldHeadState, reorg.NewHeadState, reorg.AffectedSlots, reorg.Source)
+	return err
+}
+
+// ChainReorgRecorded returns true if a recorded reorg already orphaned any of the passed slots
+func ChainReorgRecorded(slots []int64) (bool, error) {
+	var recorded bool
+	err := ReaderDb.Get(&recorded, "SELECT EXISTS (SELECT 1 FROM chain_reorgs WHERE affected_slots && $1)", pq.Int64Array(slots))
+	return recorded, err
+}
+
+// GetChainReorgs returns the recorded chain reorganizations, most recent first
+func GetChainReorgs(limit, offset uint64) ([]*types.ChainReorg, error) {
+	var reorgs []*types.ChainReorg
+	err := ReaderDb.Select(&reorgs, `
+		SELECT slot, depth, epoch, old_head_block, new_head_block, old_head_state, new_head_state, affected_slots, source, ts
+		FROM chain_reorgs
+		ORDER BY slot DESC
+		LIMIT $1 OFFSET $2`, limit, offset)
+	return reorgs, err
+}
+
+// GetChainReorgCount returns the number of recorded chain reorganizations
+func GetChainReorgCount() (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, "SELECT COUNT(*) FROM chain_reorgs")
+	return count, err
+}
+
+// GetEpochsWithoutRewards returns the most recent epochs up to the passed epoch whose validator rewards have not been exported yet
+func GetEpochsWithoutRewards(maxEpoch uint64, limit uint64) ([]uint64, error) {
+	var epochs []uint64
+	err := ReaderDb.Select(&epochs, "SELECT epoch FROM epochs WHERE NOT rewards_exported AND epoch <= $1 ORDER BY epoch DESC LIMIT $2", maxEpoch, limit)
+	return epochs, err
+}
+
+// SetEpochRewardsExported marks the validator rewards of an epoch as exported
+func SetEpochRewardsExported(epoch uint64) error {
+	_, err := WriterDb.Exec("UPDATE epochs SET rewards_exported = true WHERE epoch = $1", epoch)
+	return err
+}
+
 // GetTotalValidatorsCount will return the total-validator-count
 func GetTotalValidatorsCount() (uint64, error) {
 	var totalCount uint64
@@ -1824,6 +2128,29 @@ func GetQueueAheadOfValidator(validatorIndex uint64) (uint64, error) {
 	return res, err
 }
 
+// GetExitQueue returns the number of validators that are waiting to exit after the given epoch, the last epoch of the
+// exit queue and the number of validators exiting in that epoch
+func GetExitQueue(epoch uint64) (length, lastEpoch, lastEpochCount uint64, err error) {
+	res := struct {
+		Length         uint64 `db:"length"`
+		LastEpoch      uint64 `db:"last_epoch"`
+		LastEpochCount uint64 `db:"last_epoch_count"`
+	}{}
+	err = ReaderDb.Get(&res, `
+		WITH exiting AS (
+			SELECT exitepoch FROM validators WHERE exitepoch > $1 AND exitepoch < 9223372036854775807
+		)
+		SELECT
+			COUNT(*) AS length,
+			COALESCE(MAX(exitepoch), 0) AS last_epoch,
+			COUNT(*) FILTER (WHERE exitepoch = (SELECT MAX(exitepoch) FROM exiting)) AS last_epoch_count
+		FROM exiting`, epoch)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("error retrieving exit queue after epoch %v: %v", epoch, err)
+	}
+	return res.Length, res.LastEpoch, res.LastEpochCount, nil
+}
+
 func GetValidatorNames() (map[uint64]string, error) {
 	rows, err := ReaderDb.Query(`
 		SELECT validatorindex, validator_names.name 
@@ -2255,3 +2582,207 @@ func GetTransactions() ([]*types.Eth1Transaction, error) {
 	}
 	return txs, nil
 }
+
+// EnqueueBackfillRanges splits the epochs from startEpoch to endEpoch into ranges of rangeSize epochs and adds them to the
+// backfill work queue. Ranges overlapping a range that has not been completed yet are skipped, completed ranges are
+// queued again. The number of added ranges is returned.
+func EnqueueBackfillRanges(startEpoch, endEpoch, rangeSize uint64) (uint64, error) {
+	if rangeSize == 0 {
+		return 0, fmt.Errorf("invalid backfill range size 0")
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("error starting db transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	added := uint64(0)
+	for start := startEpoch; start <= endEpoch; start += rangeSize {
+		end := start + rangeSize - 1
+		if end > endEpoch {
+			end = endEpoch
+		}
+		res, err := tx.Exec(`
+			INSERT INTO backfill_ranges (start_epoch, end_epoch, next_epoch)
+			SELECT $1, $2, $1
+			WHERE NOT EXISTS (SELECT 1 FROM backfill_ranges WHERE status != 'done' AND start_epoch <= $2 AND end_epoch >= $1)
+			ON CONFLICT (start_epoch) DO UPDATE SET
+				end_epoch = excluded.end_epoch,
+				next_epoch = excluded.next_epoch,
+				status = 'pending',
+				worker = NULL,
+				attempts = 0,
+				last_error = NULL,
+				updated_at = now()`, start, end)
+		if err != nil {
+			return 0, fmt.Errorf("error adding backfill range %v-%v: %v", start, end, err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		added += uint64(rows)
+	}
+
+	return added, tx.Commit()
+}
+
+// ClaimBackfillRange assigns the lowest pending range of the backfill work queue to the worker. Running ranges that did not
+// report progress for longer than staleAfter are considered abandoned and are handed out again. Returns nil if the queue is empty.
+func ClaimBackfillRange(worker string, staleAfter time.Duration) (*types.BackfillRange, error) {
+	r := &types.BackfillRange{}
+	err := WriterDb.Get(r, `
+		UPDATE backfill_ranges SET status = 'running', worker = $1, attempts = attempts + 1, updated_at = now()
+		WHERE start_epoch = (
+			SELECT start_epoch FROM backfill_ranges
+			WHERE status = 'pending' OR (status = 'running' AND updated_at < now() - $2 * interval '1 second')
+			ORDER BY start_epoch
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING start_epoch, end_epoch, next_epoch, status, worker, attempts, last_error, updated_at`, worker, staleAfter.Seconds())
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error claiming backfill range: %v", err)
+	}
+	return r, nil
+}
+
+// SetBackfillCheckpoint stores that all epochs of the range before nextEpoch have been exported
+func SetBackfillCheckpoint(startEpoch, nextEpoch uint64) error {
+	_, err := WriterDb.Exec("UPDATE backfill_ranges SET next_epoch = $2, updated_at = now() WHERE start_epoch = $1", startEpoch, nextEpoch)
+	if err != nil {
+		return fmt.Errorf("error saving backfill checkpoint of range %v: %v", startEpoch, err)
+	}
+	return nil
+}
+
+// CompleteBackfillRange marks the range as done
+func CompleteBackfillRange(startEpoch uint64) error {
+	_, err := WriterDb.Exec("UPDATE backfill_ranges SET status = 'done', next_epoch = end_epoch + 1, last_error = NULL, updated_at = now() WHERE start_epoch = $1", startEpoch)
+	if err != nil {
+		return fmt.Errorf("error completing backfill range %v: %v", startEpoch, err)
+	}
+	return nil
+}
+
+// FailBackfillRange marks the range as failed, failed ranges are not handed out again until they are reset
+func FailBackfillRange(startEpoch uint64, exportErr error) error {
+	_, err := WriterDb.Exec("UPDATE backfill_ranges SET status = 'failed', last_error = $2, updated_at = now() WHERE start_epoch = $1", startEpoch, exportErr.Error())
+	if err != nil {
+		return fmt.Errorf("error marking backfill range %v as failed: %v", startEpoch, err)
+	}
+	return nil
+}
+
+// ResetFailedBackfillRanges moves all failed ranges back to the pending state, they are resumed from their checkpoint
+func ResetFailedBackfillRanges() (uint64, error) {
+	res, err := WriterDb.Exec("UPDATE backfill_ranges SET status = 'pending', updated_at = now() WHERE status = 'failed'")
+	if err != nil {
+		return 0, fmt.Errorf("error resetting failed backfill ranges: %v", err)
+	}
+	rows, err := res.RowsAffected()
+	return uint64(rows), err
+}
+
+// GetBackfillProgress returns the number of backfill ranges by status and the number of epochs that still have to be exported
+func GetBackfillProgress() (*types.BackfillProgress, error) {
+	progress := &types.BackfillProgress{}
+	err := ReaderDb.Get(progress, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+			COUNT(*) FILTER (WHERE status = 'running') AS running,
+			COUNT(*) FILTER (WHERE status = 'done') AS done,
+			COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+			COALESCE(SUM(end_epoch + 1 - next_epoch) FILTER (WHERE status != 'done'), 0) AS remaining
+		FROM backfill_ranges`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving backfill progress: %v", err)
+	}
+	return progress, nil
+}
+
+// MaxEpochExportAttempts is the number of failed export attempts after which an epoch is no longer exported automatically
+// once it is older than StuckEpochExportAge
+const MaxEpochExportAttempts = 3
+
+// StuckEpochExportAge is the age after which an epoch that failed more than MaxEpochExportAttempts times is given up on
+const StuckEpochExportAge = time.Hour * 24
+
+// IsEpochExportStuck reports whether the export retries of the epoch have been given up on
+func IsEpochExportStuck(epoch, attempts uint64) bool {
+	return attempts > MaxEpochExportAttempts && utils.EpochToTime(epoch).Before(time.Now().Add(-StuckEpochExportAge))
+}
+
+// RecordEpochExportFailure increments the failed export attempts of the epoch and stores the error
+func RecordEpochExportFailure(epoch uint64, exportErr error) error {
+	_, err := WriterDb.Exec(`
+		INSERT INTO epoch_export_failures (epoch, attempts, last_error)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (epoch) DO UPDATE SET
+			attempts = epoch_export_failures.attempts + 1,
+			last_error = excluded.last_error,
+			last_failed_at = now()`, epoch, exportErr.Error())
+	if err != nil {
+		return fmt.Errorf("error recording export failure of epoch %v: %v", epoch, err)
+	}
+	return nil
+}
+
+// ClearEpochExportFailure removes the recorded export failures of the epoch
+func ClearEpochExportFailure(epoch uint64) error {
+	_, err := WriterDb.Exec("DELETE FROM epoch_export_failures WHERE epoch = $1", epoch)
+	if err != nil {
+		return fmt.Errorf("error clearing export failure of epoch %v: %v", epoch, err)
+	}
+	return nil
+}
+
+// ClearAllEpochExportFailures removes all recorded export failures and returns the number of removed epochs
+func ClearAllEpochExportFailures() (uint64, error) {
+	res, err := WriterDb.Exec("DELETE FROM epoch_export_failures")
+	if err != nil {
+		return 0, fmt.Errorf("error clearing epoch export failures: %v", err)
+	}
+	rows, err := res.RowsAffected()
+	return uint64(rows), err
+}
+
+// RetryEpochExport resets the failed attempts of the epoch so it is exported again by the next export run
+func RetryEpochExport(epoch uint64) error {
+	_, err := WriterDb.Exec("UPDATE epoch_export_failures SET attempts = 0 WHERE epoch = $1", epoch)
+	if err != nil {
+		return fmt.Errorf("error resetting export attempts of epoch %v: %v", epoch, err)
+	}
+	return nil
+}
+
+// RetryAllEpochExports resets the failed attempts of all epochs and returns the number of affected epochs
+func RetryAllEpochExports() (uint64, error) {
+	res, err := WriterDb.Exec("UPDATE epoch_export_failures SET attempts = 0")
+	if err != nil {
+		return 0, fmt.Errorf("error resetting epoch export attempts: %v", err)
+	}
+	rows, err := res.RowsAffected()
+	return uint64(rows), err
+}
+
+// GetEpochExportFailures returns all epochs with failed export attempts ordered by epoch
+func GetEpochExportFailures() ([]*types.EpochExportFailure, error) {
+	failures := []*types.EpochExportFailure{}
+	err := ReaderDb.Select(&failures, "SELECT epoch, attempts, last_error, first_failed_at, last_failed_at FROM epoch_export_failures ORDER BY epoch")
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving epoch export failures: %v", err)
+	}
+	return failures, nil
+}
+
+// GetStuckEpochCount returns the number of epochs that are no longer exported automatically as they failed too often,
+// see IsEpochExportStuck
+func GetStuckEpochCount() (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, "SELECT COUNT(*) FROM epoch_export_failures WHERE attempts > $1 AND epoch < $2", MaxEpochExportAttempts, utils.TimeToEpoch(time.Now().Add(-StuckEpochExportAge)))
+	return count, err
+}
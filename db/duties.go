@@ -0,0 +1,173 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// SaveEpochDuties replaces the stored proposer and attester assignments of an epoch and removes the assignments of
+// epochs before the previous one
+func SaveEpochDuties(epoch uint64, assignments *types.EpochAssignments) error {
+	type committee struct {
+		slot       uint64
+		index      uint64
+		validators map[uint64]uint64
+	}
+	committees := make(map[string]*committee)
+	for key, validator := range assignments.AttestorAssignments {
+		parts := strings.Split(key, "-")
+		if len(parts) != 3 {
+			return fmt.Errorf("error parsing attestor assignment key %v of epoch %v", key, epoch)
+		}
+		slot, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing slot of attestor assignment key %v: %v", key, err)
+		}
+		index, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing committee index of attestor assignment key %v: %v", key, err)
+		}
+		member, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing member index of attestor assignment key %v: %v", key, err)
+		}
+		c := committees[parts[0]+"-"+parts[1]]
+		if c == nil {
+			c = &committee{slot: slot, index: index, validators: make(map[uint64]uint64)}
+			committees[parts[0]+"-"+parts[1]] = c
+		}
+		c.validators[member] = validator
+	}
+
+	tx, err := WriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM duties_proposers WHERE epoch = $1 OR epoch + 1 < $1", epoch)
+	if err != nil {
+		return fmt.Errorf("error deleting proposer duties of epoch %v: %v", epoch, err)
+	}
+	_, err = tx.Exec("DELETE FROM duties_committees WHERE epoch = $1 OR epoch + 1 < $1", epoch)
+	if err != nil {
+		return fmt.Errorf("error deleting committee duties of epoch %v: %v", epoch, err)
+	}
+
+	for slot, validator := range assignments.ProposerAssignments {
+		_, err = tx.Exec("INSERT INTO duties_proposers (epoch, slot, validatorindex) VALUES ($1, $2, $3) ON CONFLICT (slot) DO UPDATE SET validatorindex = excluded.validatorindex", epoch, slot, validator)
+		if err != nil {
+			return fmt.Errorf("error saving proposer duty of slot %v: %v", slot, err)
+		}
+	}
+
+	for _, c := range committees {
+		members := make([]uint64, 0, len(c.validators))
+		for member := range c.validators {
+			members = append(members, member)
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+		validators := make([]uint64, 0, len(members))
+		for _, member := range members {
+			validators = append(validators, c.validators[member])
+		}
+		_, err = tx.Exec("INSERT INTO duties_committees (epoch, slot, committeeindex, validators) VALUES ($1, $2, $3, $4) ON CONFLICT (slot, committeeindex) DO UPDATE SET validators = excluded.validators", epoch, c.slot, c.index, pq.Array(validators))
+		if err != nil {
+			return fmt.Errorf("error saving committee %v of slot %v: %v", c.index, c.slot, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SaveSyncCommitteeDuties replaces the stored members of the sync committee of a period and removes the members of
+// periods before the previous one
+func SaveSyncCommitteeDuties(period uint64, validators []uint64) error {
+	tx, err := WriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM duties_sync_committees WHERE period = $1 OR period + 1 < $1", period)
+	if err != nil {
+		return fmt.Errorf("error deleting sync committee duties of period %v: %v", period, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO duties_sync_committees (period, validatorindex)
+		SELECT $1, validatorindex FROM unnest($2::int[]) AS v(validatorindex)
+		ON CONFLICT (period, validatorindex) DO NOTHING`, period, pq.Array(validators))
+	if err != nil {
+		return fmt.Errorf("error saving sync committee duties of period %v: %v", period, err)
+	}
+
+	return tx.Commit()
+}
+
+// GetProposerDuties returns the stored proposer assignments of an epoch ordered by slot, they are final if the epoch
+// is not after currentEpoch
+func GetProposerDuties(epoch, currentEpoch uint64) ([]*types.ProposerDuty, error) {
+	duties := []*types.ProposerDuty{}
+	err := ReaderDb.Select(&duties, "SELECT epoch, slot, validatorindex FROM duties_proposers WHERE epoch = $1 ORDER BY slot", epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving proposer duties of epoch %v: %v", epoch, err)
+	}
+	for _, d := range duties {
+		d.Final = d.Epoch <= currentEpoch
+	}
+	return duties, nil
+}
+
+// GetValidatorDuties returns the proposals and attestations of the validators scheduled from fromEpoch on and the
+// sync committee periods they are a member of starting with the period of fromEpoch. Only the proposals of fromEpoch
+// are final.
+func GetValidatorDuties(validators []uint64, fromEpoch uint64) (*types.ValidatorDuties, error) {
+	duties := &types.ValidatorDuties{
+		Proposals:      []*types.ProposerDuty{},
+		Attestations:   []*types.AttesterDuty{},
+		SyncCommittees: []*types.SyncCommitteeDuty{},
+	}
+
+	err := ReaderDb.Select(&duties.Proposals, `
+		SELECT epoch, slot, validatorindex
+		FROM duties_proposers
+		WHERE validatorindex = ANY($1) AND epoch >= $2
+		ORDER BY slot`, pq.Array(validators), fromEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving proposer duties of validators %v: %v", validators, err)
+	}
+	for _, d := range duties.Proposals {
+		d.Final = d.Epoch <= fromEpoch
+	}
+
+	err = ReaderDb.Select(&duties.Attestations, `
+		SELECT dc.epoch, dc.slot, dc.committeeindex, v.validatorindex
+		FROM duties_committees dc, unnest(dc.validators) AS v(validatorindex)
+		WHERE dc.validators && $1::int[] AND v.validatorindex = ANY($1) AND dc.epoch >= $2
+		ORDER BY dc.slot, v.validatorindex`, pq.Array(validators), fromEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attester duties of validators %v: %v", validators, err)
+	}
+
+	err = ReaderDb.Select(&duties.SyncCommittees, `
+		SELECT period, validatorindex
+		FROM duties_sync_committees
+		WHERE validatorindex = ANY($1) AND period >= $2
+		ORDER BY period, validatorindex`, pq.Array(validators), utils.SyncPeriodOfEpoch(fromEpoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sync committee duties of validators %v: %v", validators, err)
+	}
+	for _, d := range duties.SyncCommittees {
+		d.StartEpoch = utils.FirstEpochOfSyncPeriod(d.Period)
+		d.EndEpoch = utils.FirstEpochOfSyncPeriod(d.Period+1) - 1
+	}
+
+	return duties, nil
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+)
+
+// LeaderElection elects a single leader among all processes competing for the same postgres advisory lock.
+// The lock is held by a dedicated connection, postgres releases it as soon as the session of the leader ends.
+type LeaderElection struct {
+	name     string
+	lockKey  int64
+	interval time.Duration
+	conn     *sql.Conn
+	leader   int32
+}
+
+// NewLeaderElection creates a leader election for the lock name, the lock is checked with the passed interval
+func NewLeaderElection(name string, interval time.Duration) *LeaderElection {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return &LeaderElection{
+		name:     name,
+		lockKey:  int64(h.Sum64()),
+		interval: interval,
+	}
+}
+
+// IsLeader reports whether this process currently holds the leader lock
+func (le *LeaderElection) IsLeader() bool {
+	return atomic.LoadInt32(&le.leader) == 1
+}
+
+// Interval returns the interval in which the leader lock is checked
+func (le *LeaderElection) Interval() time.Duration {
+	return le.interval
+}
+
+// Close releases the connection of the election and with it the leader lock if it is held
+func (le *LeaderElection) Close() error {
+	atomic.StoreInt32(&le.leader, 0)
+	if le.conn == nil {
+		return nil
+	}
+	err := le.conn.Close()
+	le.conn = nil
+	return err
+}
+
+// Campaign blocks until the leader lock has been acquired or the context is done
+func (le *LeaderElection) Campaign(ctx context.Context) error {
+	for {
+		acquired, err := le.tryAcquire(ctx)
+		if err != nil {
+			logger.Errorf("error acquiring leader lock %v: %v", le.name, err)
+		} else if acquired {
+			atomic.StoreInt32(&le.leader, 1)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(le.interval):
+		}
+	}
+}
+
+// Watch regularly verifies that the leader lock is still held and returns once it has been lost or the context is done
+func (le *LeaderElection) Watch(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			atomic.StoreInt32(&le.leader, 0)
+			return ctx.Err()
+		case <-time.After(le.interval):
+		}
+
+		var held bool
+		err := le.conn.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM pg_locks
+				WHERE locktype = 'advisory' AND pid = pg_backend_pid() AND granted AND objsubid = 1
+				AND ((classid::bigint << 32) | objid::bigint) = $1
+			)`, le.lockKey).Scan(&held)
+		if err != nil || !held {
+			atomic.StoreInt32(&le.leader, 0)
+			le.conn.Close()
+			le.conn = nil
+			if err != nil {
+				return fmt.Errorf("error checking leader lock %v: %v", le.name, err)
+			}
+			return fmt.Errorf("leader lock %v is no longer held", le.name)
+		}
+	}
+}
+
+func (le *LeaderElection) tryAcquire(ctx context.Context) (bool, error) {
+	if le.conn == nil {
+		conn, err := WriterDb.Conn(ctx)
+		if err != nil {
+			return false, err
+		}
+		le.conn = conn
+	}
+
+	var acquired bool
+	err := le.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", le.lockKey).Scan(&acquired)
+	if err != nil {
+		le.conn.Close()
+		le.conn = nil
+		return false, err
+	}
+	return acquired, nil
+}
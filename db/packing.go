@@ -0,0 +1,224 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// SaveOperationPoolSnapshot stores the operations that were pending in the operation pool before a slot
+func SaveOperationPoolSnapshot(snapshot *types.OperationPoolSnapshot) error {
+	_, err := WriterDb.Exec(`
+		INSERT INTO operation_pool_snapshots (slot, proposer_slashings, attester_slashings, voluntary_exits)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (slot) DO UPDATE SET
+			proposer_slashings = excluded.proposer_slashings,
+			attester_slashings = excluded.attester_slashings,
+			voluntary_exits = excluded.voluntary_exits`,
+		snapshot.Slot, snapshot.ProposerSlashings, snapshot.AttesterSlashings, snapshot.VoluntaryExits)
+	if err != nil {
+		return fmt.Errorf("error saving operation pool snapshot of slot %v: %v", snapshot.Slot, err)
+	}
+	return nil
+}
+
+// DeleteOperationPoolSnapshots removes the operation pool snapshots of the slots from fromSlot to toSlot
+func DeleteOperationPoolSnapshots(fromSlot, toSlot uint64) error {
+	_, err := WriterDb.Exec("DELETE FROM operation_pool_snapshots WHERE slot >= $1 AND slot <= $2", fromSlot, toSlot)
+	if err != nil {
+		return fmt.Errorf("error deleting operation pool snapshots of slots %v-%v: %v", fromSlot, toSlot, err)
+	}
+	return nil
+}
+
+// GetEpochsWithoutPacking returns the most recent epochs up to maxEpoch whose block packing has not been evaluated yet
+func GetEpochsWithoutPacking(maxEpoch uint64, limit uint64) ([]uint64, error) {
+	var epochs []uint64
+	err := ReaderDb.Select(&epochs, "SELECT epoch FROM epochs WHERE NOT packing_exported AND epoch <= $1 ORDER BY epoch DESC LIMIT $2", maxEpoch, limit)
+	return epochs, err
+}
+
+// SetEpochPackingExported marks the block packing of an epoch as evaluated
+func SetEpochPackingExported(epoch uint64) error {
+	_, err := WriterDb.Exec("UPDATE epochs SET packing_exported = true WHERE epoch = $1", epoch)
+	return err
+}
+
+// GetPackingBlocks returns the canonical blocks between fromSlot and toSlot with their included aggregates and
+// operations and the operations that were pending before their slot, ordered by slot
+func GetPackingBlocks(fromSlot, toSlot uint64) ([]*types.PackingBlock, error) {
+	blocks := []*types.PackingBlock{}
+	err := ReaderDb.Select(&blocks, `
+		SELECT
+			blocks.slot,
+			blocks.blockroot,
+			blocks.proposer,
+			blocks.syncaggregate_participation AS sync_participation,
+			blocks.syncaggregate_bits IS NOT NULL AS has_sync_aggregate,
+			blocks.proposerslashingscount + blocks.attesterslashingscount + blocks.voluntaryexitscount AS included_operations,
+			ARRAY(
+				SELECT proposerindex FROM blocks_proposerslashings
+				WHERE block_slot = blocks.slot AND block_root = blocks.blockroot
+			) AS included_proposer_slashings,
+			ARRAY(
+				SELECT validatorindex FROM blocks_voluntaryexits
+				WHERE block_slot = blocks.slot AND block_root = blocks.blockroot
+			) AS included_exits
+		FROM blocks
+		WHERE blocks.slot >= $1 AND blocks.slot <= $2 AND blocks.status = '1'
+		ORDER BY blocks.slot`, fromSlot, toSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving blocks of slots %v-%v: %v", fromSlot, toSlot, err)
+	}
+
+	attesterSlashings := []struct {
+		BlockSlot           uint64        `db:"block_slot"`
+		Attestation1Indices pq.Int64Array `db:"attestation1_indices"`
+		Attestation2Indices pq.Int64Array `db:"attestation2_indices"`
+	}{}
+	err = ReaderDb.Select(&attesterSlashings, `
+		SELECT bas.block_slot, bas.attestation1_indices, bas.attestation2_indices
+		FROM blocks_attesterslashings bas
+		INNER JOIN blocks ON blocks.slot = bas.block_slot AND blocks.blockroot = bas.block_root AND blocks.status = '1'
+		WHERE bas.block_slot >= $1 AND bas.block_slot <= $2`, fromSlot, toSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attester slashings of slots %v-%v: %v", fromSlot, toSlot, err)
+	}
+
+	snapshots := []*types.OperationPoolSnapshot{}
+	err = ReaderDb.Select(&snapshots, `
+		SELECT slot, proposer_slashings, attester_slashings, voluntary_exits
+		FROM operation_pool_snapshots
+		WHERE slot >= $1 AND slot <= $2`, fromSlot, toSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving operation pool snapshots of slots %v-%v: %v", fromSlot, toSlot, err)
+	}
+
+	aggregates := []*types.PackingAggregate{}
+	err = ReaderDb.Select(&aggregates, `
+		SELECT ba.block_slot, ba.block_root, ba.slot, ba.validators
+		FROM blocks_attestations ba
+		INNER JOIN blocks ON blocks.slot = ba.block_slot AND blocks.blockroot = ba.block_root AND blocks.status = '1'
+		WHERE ba.block_slot >= $1 AND ba.block_slot <= $2
+		ORDER BY ba.block_slot, ba.block_index`, fromSlot, toSlot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestations of slots %v-%v: %v", fromSlot, toSlot, err)
+	}
+
+	blocksBySlot := make(map[uint64]*types.PackingBlock, len(blocks))
+	for _, b := range blocks {
+		blocksBySlot[b.Slot] = b
+	}
+	for _, a := range aggregates {
+		if b := blocksBySlot[a.BlockSlot]; b != nil {
+			b.Aggregates = append(b.Aggregates, a)
+		}
+	}
+	for _, s := range attesterSlashings {
+		if b := blocksBySlot[s.BlockSlot]; b != nil {
+			b.IncludedAttesterSlashings = append(b.IncludedAttesterSlashings, utils.AttesterSlashingKey(int64sToUint64s(s.Attestation1Indices), int64sToUint64s(s.Attestation2Indices)))
+		}
+	}
+	for _, s := range snapshots {
+		if b := blocksBySlot[s.Slot]; b != nil {
+			b.PendingOperations = s
+		}
+	}
+	return blocks, nil
+}
+
+// SaveBlockPackings stores the evaluated packing of blocks
+func SaveBlockPackings(packings []*types.BlockPacking) error {
+	tx, err := WriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, p := range packings {
+		_, err = tx.Exec(`
+			INSERT INTO blocks_packing (slot, blockroot, proposer, included_votes, available_votes, aggregates, redundant_aggregates, sync_participation, included_operations, missed_slashings, missed_exits, score)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (slot, blockroot) DO UPDATE SET
+				included_votes = excluded.included_votes,
+				available_votes = excluded.available_votes,
+				aggregates = excluded.aggregates,
+				redundant_aggregates = excluded.redundant_aggregates,
+				sync_participation = excluded.sync_participation,
+				included_operations = excluded.included_operations,
+				missed_slashings = excluded.missed_slashings,
+				missed_exits = excluded.missed_exits,
+				score = excluded.score`,
+			p.Slot, p.BlockRoot, p.Proposer, p.IncludedVotes, p.AvailableVotes, p.Aggregates, p.RedundantAggregates, p.SyncParticipation, p.IncludedOperations, p.MissedSlashings, p.MissedExits, p.Score)
+		if err != nil {
+			return fmt.Errorf("error saving packing of block at slot %v: %v", p.Slot, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPackingLeaderboard returns the proposers with the best average block packing of at least minBlocks blocks since
+// fromSlot, if byPool is set the blocks are grouped by the staking pool of their proposer instead
+func GetPackingLeaderboard(fromSlot uint64, byPool bool, minBlocks, limit uint64) ([]*types.PackingLeaderboardEntry, error) {
+	group := "bp.proposer AS proposer, '' AS pool"
+	groupBy := "bp.proposer"
+	join := ""
+	if byPool {
+		group = "NULL AS proposer, validator_pool.pool AS pool"
+		groupBy = "validator_pool.pool"
+		join = `
+			INNER JOIN validators ON validators.validatorindex = bp.proposer
+			INNER JOIN validator_pool ON validator_pool.publickey = validators.pubkey`
+	}
+
+	entries := []*types.PackingLeaderboardEntry{}
+	err := ReaderDb.Select(&entries, fmt.Sprintf(`
+		SELECT
+			%s,
+			COUNT(*) AS blocks,
+			AVG(bp.score) AS avg_score,
+			AVG(CASE WHEN bp.available_votes > 0 THEN bp.included_votes::float / bp.available_votes ELSE 1 END) AS avg_vote_inclusion,
+			AVG(bp.sync_participation) AS avg_sync_participation,
+			SUM(bp.redundant_aggregates) AS redundant_aggregates,
+			SUM(bp.missed_slashings + bp.missed_exits) AS missed_operations
+		FROM blocks_packing bp
+		INNER JOIN blocks ON blocks.slot = bp.slot AND blocks.blockroot = bp.blockroot AND blocks.status = '1'%s
+		WHERE bp.slot >= $1 AND bp.score IS NOT NULL
+		GROUP BY %s
+		HAVING COUNT(*) >= $2
+		ORDER BY avg_score DESC, blocks DESC
+		LIMIT $3`, group, join, groupBy), fromSlot, minBlocks, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block packing leaderboard: %v", err)
+	}
+	return entries, nil
+}
+
+// GetValidatorBlockPackings returns the evaluated packing of the most recent canonical blocks of the proposers
+func GetValidatorBlockPackings(proposers []uint64, limit uint64) ([]*types.BlockPacking, error) {
+	packings := []*types.BlockPacking{}
+	err := ReaderDb.Select(&packings, `
+		SELECT
+			bp.slot, bp.blockroot, bp.proposer, bp.included_votes, bp.available_votes, bp.aggregates, bp.redundant_aggregates,
+			bp.sync_participation, bp.included_operations, bp.missed_slashings, bp.missed_exits, bp.score
+		FROM blocks_packing bp
+		INNER JOIN blocks ON blocks.slot = bp.slot AND blocks.blockroot = bp.blockroot AND blocks.status = '1'
+		WHERE bp.proposer = ANY($1) AND bp.score IS NOT NULL
+		ORDER BY bp.slot DESC
+		LIMIT $2`, pq.Array(proposers), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block packing of proposers %v: %v", proposers, err)
+	}
+	return packings, nil
+}
+
+func int64sToUint64s(values []int64) []uint64 {
+	res := make([]uint64, 0, len(values))
+	for _, v := range values {
+		res = append(res, uint64(v))
+	}
+	return res
+}
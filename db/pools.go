@@ -280,3 +280,74 @@ func deleteOldChartEntries() {
 		logger.Errorf("error removing old staking pool chart data: %v", err)
 	}
 }
+
+// GetPoolOperatorValidatorCounts returns the number of exported validators per operator id of the pool
+func GetPoolOperatorValidatorCounts(pool string) (map[string]uint64, error) {
+	rows := []struct {
+		OperatorID     string `db:"operator_id"`
+		ValidatorCount uint64 `db:"validator_count"`
+	}{}
+	err := WriterDb.Select(&rows, "SELECT operator_id, COUNT(*) AS validator_count FROM pool_operator_validators WHERE pool = $1 GROUP BY operator_id", pool)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator counts of pool %v: %v", pool, err)
+	}
+	counts := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		counts[row.OperatorID] = row.ValidatorCount
+	}
+	return counts, nil
+}
+
+// SavePoolOperators saves the operators of a pool and assigns their new validators to the pool and operator
+func SavePoolOperators(operators []*types.PoolOperator) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, operator := range operators {
+		_, err := tx.Exec(`
+			INSERT INTO pool_operators (pool, operator_id, name, address, active, validator_count, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, now())
+			ON CONFLICT (pool, operator_id) DO UPDATE SET
+				name = excluded.name,
+				address = excluded.address,
+				active = excluded.active,
+				validator_count = excluded.validator_count,
+				updated_at = excluded.updated_at`,
+			operator.Pool, operator.ID, operator.Name, operator.Address, operator.Active, operator.ValidatorCount)
+		if err != nil {
+			return fmt.Errorf("error saving operator %v of pool %v: %v", operator.ID, operator.Pool, err)
+		}
+
+		batchSize := 5000
+		for b := 0; b < len(operator.NewValidators); b += batchSize {
+			start := b
+			end := b + batchSize
+			if len(operator.NewValidators) < end {
+				end = len(operator.NewValidators)
+			}
+			pubkeys := pq.ByteaArray(operator.NewValidators[start:end])
+
+			_, err := tx.Exec(`
+				INSERT INTO pool_operator_validators (publickey, pool, operator_id)
+				SELECT UNNEST($1::bytea[]), $2, $3
+				ON CONFLICT (publickey) DO UPDATE SET pool = excluded.pool, operator_id = excluded.operator_id`, pubkeys, operator.Pool, operator.ID)
+			if err != nil {
+				return fmt.Errorf("error inserting into pool_operator_validators: %w", err)
+			}
+			_, err = tx.Exec(`INSERT INTO validator_tags (publickey, tag) SELECT UNNEST($1::bytea[]), $2 ON CONFLICT (publickey, tag) DO NOTHING`, pubkeys, "pool:"+operator.Pool)
+			if err != nil {
+				return fmt.Errorf("error inserting into validator_tags: %w", err)
+			}
+			// the on-chain data of the pool takes precedence over pools derived from deposit addresses
+			_, err = tx.Exec(`INSERT INTO validator_pool (publickey, pool) SELECT UNNEST($1::bytea[]), $2 ON CONFLICT (publickey) DO UPDATE SET pool = excluded.pool`, pubkeys, operator.Pool)
+			if err != nil {
+				return fmt.Errorf("error inserting into validator_pool: %w", err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"database/sql"
+	"eth2-exporter/types"
+	"fmt"
+	"math/big"
+)
+
+// SaveRelayBids stores the highest bid of every builder for a slot, bids that have already been stored are replaced
+func SaveRelayBids(bids []*types.RelayBid) error {
+	tx, err := WriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, bid := range bids {
+		receivedAt := sql.NullTime{Time: bid.ReceivedAt, Valid: !bid.ReceivedAt.IsZero()}
+		_, err = tx.Exec(`
+			INSERT INTO relays_bids (tag_id, slot, builder_pubkey, block_hash, proposer_fee_recipient, value, gas_used, num_tx, bid_count, received_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (slot, tag_id, builder_pubkey) DO UPDATE SET
+				block_hash = excluded.block_hash,
+				proposer_fee_recipient = excluded.proposer_fee_recipient,
+				value = excluded.value,
+				gas_used = excluded.gas_used,
+				num_tx = excluded.num_tx,
+				bid_count = excluded.bid_count,
+				received_at = excluded.received_at`,
+			bid.ID, bid.Slot, bid.BuilderPubkey, bid.BlockHash, bid.ProposerFeeRecipient, bid.Value, bid.GasUsed, bid.NumTx, bid.BidCount, receivedAt)
+		if err != nil {
+			return fmt.Errorf("error saving bid of builder 0x%x for slot %v of relay %v: %v", bid.BuilderPubkey, bid.Slot, bid.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetUncheckedRelayBlocks returns the relay blocks between fromSlot and toSlot whose proposer payment has not been checked yet
+func GetUncheckedRelayBlocks(fromSlot, toSlot, limit uint64) ([]*types.RelayPaymentCheck, error) {
+	blocks := []*types.RelayPaymentCheck{}
+	err := ReaderDb.Select(&blocks, `
+		SELECT DISTINCT ON (rb.block_slot, rb.block_root)
+			rb.block_slot,
+			rb.block_root,
+			rb.exec_block_hash,
+			blocks.exec_block_number,
+			rb.proposer_fee_recipient,
+			rb.value
+		FROM relays_blocks rb
+		INNER JOIN blocks ON blocks.blockroot = rb.block_root
+		WHERE rb.block_slot >= $1 AND rb.block_slot <= $2 AND rb.proposer_payment IS NULL AND blocks.exec_block_number IS NOT NULL
+		ORDER BY rb.block_slot, rb.block_root, rb.value DESC
+		LIMIT $3`, fromSlot, toSlot, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving unchecked relay blocks: %v", err)
+	}
+	return blocks, nil
+}
+
+// SetRelayBlockPayment stores the amount the fee recipient received in the execution block of a relay block
+func SetRelayBlockPayment(blockRoot []byte, payment *big.Int) error {
+	_, err := WriterDb.Exec(`UPDATE relays_blocks SET proposer_payment = $2 WHERE block_root = $1`, blockRoot, payment.String())
+	if err != nil {
+		return fmt.Errorf("error saving proposer payment of relay block 0x%x: %v", blockRoot, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// SavePotentialSlashings stores the detected slashable offences, offences that have already been recorded are skipped
+func SavePotentialSlashings(slashings []*types.PotentialSlashing) error {
+	tx, err := WriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, s := range slashings {
+		_, err = tx.Exec(`
+			INSERT INTO potential_slashings (kind, validatorindex, slot, epoch, evidence, detected_ts)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (kind, validatorindex, epoch) DO NOTHING`,
+			s.Kind, s.ValidatorIndex, s.Slot, s.Epoch, s.Evidence, s.DetectedTs)
+		if err != nil {
+			return fmt.Errorf("error saving %v of validator %v at epoch %v: %v", s.Kind, s.ValidatorIndex, s.Epoch, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPotentialSlashings returns the most recent potential slashings since fromEpoch, if validators is not empty only
+// the potential slashings of these validators are returned
+func GetPotentialSlashings(validators []uint64, fromEpoch, limit uint64) ([]*types.PotentialSlashing, error) {
+	slashings := []*types.PotentialSlashing{}
+	err := ReaderDb.Select(&slashings, `
+		SELECT
+			ps.id,
+			ps.kind,
+			ps.validatorindex,
+			COALESCE(validators.pubkey, '\x'::bytea) AS pubkey,
+			ps.slot,
+			ps.epoch,
+			ps.evidence,
+			ps.detected_ts,
+			COALESCE(validators.slashed, false) AS slashed
+		FROM potential_slashings ps
+		LEFT JOIN validators ON validators.validatorindex = ps.validatorindex
+		WHERE ps.epoch >= $1 AND (cardinality(COALESCE($2::int[], '{}')) = 0 OR ps.validatorindex = ANY($2))
+		ORDER BY ps.epoch DESC, ps.id DESC
+		LIMIT $3`, fromEpoch, pq.Array(validators), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving potential slashings since epoch %v: %v", fromEpoch, err)
+	}
+	return slashings, nil
+}
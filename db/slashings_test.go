@@ -0,0 +1,73 @@
+package db
+
+import (
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// TestGetPotentialSlashings runs against the postgres database given by TEST_DB_URL, the tables are created as
+// temporary tables so the test does not touch existing data
+func TestGetPotentialSlashings(t *testing.T) {
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		t.Skip("TEST_DB_URL not set")
+	}
+
+	conn, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test db: %v", err)
+	}
+	defer conn.Close()
+	// temporary tables are only visible to the session that created them
+	conn.SetMaxOpenConns(1)
+
+	writer, reader := WriterDb, ReaderDb
+	WriterDb, ReaderDb = conn, conn
+	defer func() { WriterDb, ReaderDb = writer, reader }()
+
+	_, err = conn.Exec(`
+		CREATE TEMPORARY TABLE validators (validatorindex int not null, pubkey bytea not null, slashed bool not null);
+		CREATE TEMPORARY TABLE potential_slashings (
+			id serial not null,
+			kind varchar(20) not null,
+			validatorindex int not null,
+			slot int not null,
+			epoch int not null,
+			evidence jsonb not null,
+			detected_ts timestamp not null,
+			primary key (id),
+			unique (kind, validatorindex, epoch)
+		);
+		INSERT INTO validators VALUES (1, '\x01', false), (2, '\x02', true);
+		INSERT INTO potential_slashings (kind, validatorindex, slot, epoch, evidence, detected_ts) VALUES
+			('double_vote', 1, 320, 10, '{}', now()),
+			('double_proposal', 2, 352, 11, '{}', now());`)
+	if err != nil {
+		t.Fatalf("error creating test tables: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		validators []uint64
+		fromEpoch  uint64
+		want       int
+	}{
+		{"nil filter", nil, 0, 2},
+		{"empty filter", []uint64{}, 0, 2},
+		{"validator filter", []uint64{2}, 0, 1},
+		{"unknown validator", []uint64{3}, 0, 0},
+		{"from epoch", nil, 11, 1},
+	}
+	for _, tt := range tests {
+		slashings, err := GetPotentialSlashings(tt.validators, tt.fromEpoch, 10)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.name, err)
+		}
+		if len(slashings) != tt.want {
+			t.Errorf("%v: got %v potential slashings, want %v", tt.name, len(slashings), tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"eth2-exporter/metrics"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+)
+
+// UpdateSSVOperatorStats aggregates the validator stats of the last 7 exported days and the 7 day performance of the
+// validators of every ssv operator. The expected attestations are derived from the days a validator had attestation duties.
+func UpdateSSVOperatorStats() error {
+	start := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("db_update_ssv_operator_stats").Observe(time.Since(start).Seconds())
+	}()
+
+	epochsPerDay := (24 * 60 * 60) / utils.Config.Chain.Config.SlotsPerEpoch / utils.Config.Chain.Config.SecondsPerSlot
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		WITH last_day AS (
+			SELECT COALESCE(MAX(day), 0) AS day FROM validator_stats_status WHERE status
+		),
+		operator_validators AS (
+			SELECT DISTINCT svo.operator_id, v.validatorindex, v.status
+			FROM ssv_validator_operators svo
+			INNER JOIN validators v ON v.pubkey = svo.publickey
+		),
+		duties AS (
+			SELECT
+				ov.operator_id,
+				COUNT(vs.missed_attestations) * $1 AS attestations_expected,
+				COALESCE(SUM(vs.missed_attestations), 0) AS attestations_missed,
+				COALESCE(SUM(vs.proposed_blocks), 0) AS proposed_blocks,
+				COALESCE(SUM(vs.missed_blocks), 0) AS missed_blocks,
+				COALESCE(SUM(vs.orphaned_blocks), 0) AS orphaned_blocks
+			FROM operator_validators ov
+			LEFT JOIN validator_stats vs ON vs.validatorindex = ov.validatorindex
+				AND vs.day > (SELECT day FROM last_day) - 7 AND vs.day <= (SELECT day FROM last_day)
+			GROUP BY ov.operator_id
+		),
+		totals AS (
+			SELECT
+				ov.operator_id,
+				COUNT(*) AS validators,
+				COUNT(*) FILTER (WHERE ov.status LIKE 'active%') AS active_validators,
+				COALESCE(SUM(vp.performance7d), 0) AS performance7d
+			FROM operator_validators ov
+			LEFT JOIN validator_performance vp ON vp.validatorindex = ov.validatorindex
+			GROUP BY ov.operator_id
+		)
+		INSERT INTO ssv_operator_stats (
+			operator_id,
+			validators,
+			active_validators,
+			attestations_expected,
+			attestations_missed,
+			attestation_participation,
+			proposed_blocks,
+			missed_blocks,
+			orphaned_blocks,
+			performance7d,
+			updated_at
+		)
+		SELECT
+			t.operator_id,
+			t.validators,
+			t.active_validators,
+			d.attestations_expected,
+			d.attestations_missed,
+			COALESCE(1 - d.attestations_missed::double precision / NULLIF(d.attestations_expected, 0), 0),
+			d.proposed_blocks,
+			d.missed_blocks,
+			d.orphaned_blocks,
+			t.performance7d,
+			now()
+		FROM totals t
+		INNER JOIN duties d ON d.operator_id = t.operator_id
+		ON CONFLICT (operator_id) DO UPDATE SET
+			validators = excluded.validators,
+			active_validators = excluded.active_validators,
+			attestations_expected = excluded.attestations_expected,
+			attestations_missed = excluded.attestations_missed,
+			attestation_participation = excluded.attestation_participation,
+			proposed_blocks = excluded.proposed_blocks,
+			missed_blocks = excluded.missed_blocks,
+			orphaned_blocks = excluded.orphaned_blocks,
+			performance7d = excluded.performance7d,
+			updated_at = excluded.updated_at`, epochsPerDay)
+	if err != nil {
+		return fmt.Errorf("error updating ssv operator stats: %v", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM ssv_operator_stats WHERE operator_id NOT IN (SELECT DISTINCT operator_id FROM ssv_validator_operators)`)
+	if err != nil {
+		return fmt.Errorf("error deleting stats of removed ssv operators: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetSSVSummary returns the number of ssv operators, validators and clusters
+func GetSSVSummary() (*types.SSVPageData, error) {
+	summary := &types.SSVPageData{}
+	err := ReaderDb.Get(summary, `
+		SELECT
+			(SELECT COUNT(*) FROM ssv_operators WHERE validator_count > 0) AS operators,
+			COUNT(DISTINCT publickey) AS validators,
+			COUNT(DISTINCT cluster) AS clusters
+		FROM ssv_validator_operators`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ssv summary: %v", err)
+	}
+	return summary, nil
+}
+
+const ssvOperatorColumns = `
+	ssv_operators.id,
+	ssv_operators.publickey,
+	COALESCE(s.validators, ssv_operators.validator_count) AS validators,
+	COALESCE(s.active_validators, 0) AS active_validators,
+	COALESCE(s.attestations_expected, 0) AS attestations_expected,
+	COALESCE(s.attestations_missed, 0) AS attestations_missed,
+	COALESCE(s.attestation_participation, 0) AS attestation_participation,
+	COALESCE(s.proposed_blocks, 0) AS proposed_blocks,
+	COALESCE(s.missed_blocks, 0) AS missed_blocks,
+	COALESCE(s.orphaned_blocks, 0) AS orphaned_blocks,
+	COALESCE(s.performance7d, 0) AS performance7d,
+	COALESCE(s.updated_at, ssv_operators.updated_at) AS updated_at`
+
+// GetSSVOperators returns the ssv operators having validators together with their stats, orderBy has to be a column of the result
+func GetSSVOperators(orderBy, orderDir string, limit, offset uint64) ([]*types.SSVOperator, error) {
+	if orderDir != "desc" && orderDir != "asc" {
+		orderDir = "desc"
+	}
+	operators := []*types.SSVOperator{}
+	err := ReaderDb.Select(&operators, fmt.Sprintf(`
+		SELECT %s, COUNT(*) OVER () AS total_count
+		FROM ssv_operators
+		LEFT JOIN ssv_operator_stats s ON s.operator_id = ssv_operators.id
+		WHERE ssv_operators.validator_count > 0
+		ORDER BY %s %s NULLS LAST, ssv_operators.id
+		LIMIT $1 OFFSET $2`, ssvOperatorColumns, orderBy, orderDir), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ssv operators: %v", err)
+	}
+	return operators, nil
+}
+
+// GetSSVOperator returns the ssv operator with the passed id or nil if it does not exist
+func GetSSVOperator(id uint64) (*types.SSVOperator, error) {
+	operators := []*types.SSVOperator{}
+	err := ReaderDb.Select(&operators, fmt.Sprintf(`
+		SELECT %s, 1 AS total_count
+		FROM ssv_operators
+		LEFT JOIN ssv_operator_stats s ON s.operator_id = ssv_operators.id
+		WHERE ssv_operators.id = $1`, ssvOperatorColumns), id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ssv operator %v: %v", id, err)
+	}
+	if len(operators) == 0 {
+		return nil, nil
+	}
+	return operators[0], nil
+}
+
+// GetSSVOperatorValidators returns the indices of the validators run by the ssv operator
+func GetSSVOperatorValidators(id uint64) ([]uint64, error) {
+	indices := []uint64{}
+	err := ReaderDb.Select(&indices, `
+		SELECT v.validatorindex
+		FROM ssv_validator_operators svo
+		INNER JOIN validators v ON v.pubkey = svo.publickey
+		WHERE svo.operator_id = $1
+		ORDER BY v.validatorindex`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators of ssv operator %v: %v", id, err)
+	}
+	return indices, nil
+}
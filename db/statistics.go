@@ -252,6 +252,26 @@ func WriteStatisticsForDay(day uint64) error {
 	}
 	logger.Infof("export completed, took %v", time.Since(start))
 
+	start = time.Now()
+	logger.Infof("exporting withdrawals and withdrawals_amount statistics")
+	_, err = tx.Exec(`
+		insert into validator_stats (validatorindex, day, withdrawals, withdrawals_amount) 
+		(
+			select validatorindex, $3, count(*), sum(amount)
+			from blocks_withdrawals
+			inner join blocks on blocks_withdrawals.block_root = blocks.blockroot
+			where block_slot >= $1 * $4 and block_slot < ($2 + 1) * $4 and blocks.status = '1'
+			group by validatorindex
+		) 
+		on conflict (validatorindex, day) do
+			update set withdrawals = excluded.withdrawals, 
+			withdrawals_amount = excluded.withdrawals_amount;`,
+		firstEpoch, lastEpoch, day, utils.Config.Chain.Config.SlotsPerEpoch)
+	if err != nil {
+		return err
+	}
+	logger.Infof("export completed, took %v", time.Since(start))
+
 	start = time.Now()
 	logger.Infof("marking day export as completed in the status table")
 	_, err = tx.Exec("insert into validator_stats_status (day, status) values ($1, true)", day)
@@ -333,11 +353,20 @@ func GetValidatorIncomeHistory(validator_indices []uint64, lowerBoundDay uint64,
 				from validators
 				where validatorindex = ANY($1))
 	),
+	current_withdrawals as (
+		select
+			coalesce(SUM(amount),0) as withdrawals_amount,
+			(select day from _today) as day
+		from blocks_withdrawals
+		where 
+			block_slot > (select (day) * 225 * 32 from _today) and
+			validatorindex = ANY($1)
+	),
 	history as (
-		select day, coalesce(lag(end_balance) over (order by day), start_balance) as start_balance, end_balance as end_balance, deposits_amount
+		select day, coalesce(lag(end_balance) over (order by day), start_balance) as start_balance, end_balance as end_balance, deposits_amount, withdrawals_amount
 		from (
 			select 
-				day, COALESCE(SUM(start_balance),0) AS start_balance, COALESCE(SUM(end_balance),0) AS end_balance, COALESCE(SUM(deposits_amount), 0) AS deposits_amount
+				day, COALESCE(SUM(start_balance),0) AS start_balance, COALESCE(SUM(end_balance),0) AS end_balance, COALESCE(SUM(deposits_amount), 0) AS deposits_amount, COALESCE(SUM(withdrawals_amount), 0) AS withdrawals_amount
 			FROM validator_stats
 			WHERE validatorindex = ANY($1) AND
 				day BETWEEN ($2 - 1) AND $3
@@ -353,15 +382,17 @@ func GetValidatorIncomeHistory(validator_indices []uint64, lowerBoundDay uint64,
 		GROUP BY day
 	)
 	select * from (
-		select day, end_balance - start_balance - deposits_amount as diff, start_balance, end_balance, deposits_amount from (
+		select day, end_balance - start_balance - deposits_amount + withdrawals_amount as diff, start_balance, end_balance, deposits_amount, withdrawals_amount from (
 			select 
 				coalesce(history.day, 0) + coalesce(current_balances.day, 0) as day,
 				coalesce(history.start_balance,0) + coalesce(today.start_balance,0) as start_balance,
 				coalesce(history.end_balance,0) + coalesce(current_balances.end_balance,0) as end_balance,
-				coalesce(history.deposits_amount,0) + coalesce(current_deposits.deposits_amount,0) as deposits_amount
+				coalesce(history.deposits_amount,0) + coalesce(current_deposits.deposits_amount,0) as deposits_amount,
+				coalesce(history.withdrawals_amount,0) + coalesce(current_withdrawals.withdrawals_amount,0) as withdrawals_amount
 			from history
 			full outer join current_balances on current_balances.day = history.day
 			left join current_deposits on current_balances.day = current_deposits.day
+			left join current_withdrawals on current_balances.day = current_withdrawals.day
 			full join today on current_balances.day = today.day
 		) as foo 
 	) as foo2 
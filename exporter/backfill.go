@@ -0,0 +1,175 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBackfillWorkers    = 4
+	defaultBackfillRangeSize  = 100
+	defaultBackfillStaleAfter = time.Minute * 30
+)
+
+// EnqueueBackfill adds the epochs from startEpoch to endEpoch to the backfill work queue
+func EnqueueBackfill(startEpoch, endEpoch uint64) error {
+	if startEpoch > endEpoch {
+		return fmt.Errorf("invalid backfill range %v-%v", startEpoch, endEpoch)
+	}
+	rangeSize := utils.Config.Indexer.Backfill.RangeSize
+	if rangeSize == 0 {
+		rangeSize = defaultBackfillRangeSize
+	}
+	added, err := db.EnqueueBackfillRanges(startEpoch, endEpoch, rangeSize)
+	if err != nil {
+		return err
+	}
+	logger.Infof("added %v backfill ranges for epochs %v-%v", added, startEpoch, endEpoch)
+	return nil
+}
+
+// Backfill exports the epochs of the backfill work queue using the configured number of workers and returns once the
+// queue is empty. Each worker stores a checkpoint after every exported epoch so an interrupted backfill resumes where it stopped.
+// With leader election enabled the caller has to hold the exporter leadership.
+func Backfill(client rpc.Client) error {
+	workers := utils.Config.Indexer.Backfill.Workers
+	if workers <= 0 {
+		workers = defaultBackfillWorkers
+	}
+	staleAfter := utils.Config.Indexer.Backfill.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultBackfillStaleAfter
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("error retrieving hostname: %v", err)
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		for {
+			updateBackfillProgress()
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Second * 30):
+			}
+		}
+	}()
+
+	start := time.Now()
+	logger.Infof("starting backfill with %v workers", workers)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			backfillWorker(client, worker, staleAfter)
+		}(fmt.Sprintf("%v-%v-%v", hostname, os.Getpid(), i))
+	}
+	wg.Wait()
+	close(stop)
+
+	progress := updateBackfillProgress()
+	if progress != nil && progress.Failed > 0 {
+		return fmt.Errorf("backfill finished after %v with %v failed ranges", time.Since(start), progress.Failed)
+	}
+	logger.Infof("backfill finished after %v", time.Since(start))
+	return nil
+}
+
+// backfillQueueExporter exports the ranges that are added to the backfill work queue while the exporter is running,
+// e.g. by a standalone backfill that found another instance holding the exporter leadership
+func backfillQueueExporter(client rpc.Client) {
+	for {
+		time.Sleep(time.Minute)
+
+		progress, err := db.GetBackfillProgress()
+		if err != nil {
+			logger.Errorf("error retrieving backfill progress: %v", err)
+			continue
+		}
+		if progress.Pending == 0 {
+			continue
+		}
+		err = Backfill(client)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// backfillWorker claims ranges from the backfill work queue and exports them until the queue is empty
+func backfillWorker(client rpc.Client, worker string, staleAfter time.Duration) {
+	for {
+		r, err := db.ClaimBackfillRange(worker, staleAfter)
+		if err != nil {
+			logger.Errorf("backfill worker %v: %v", worker, err)
+			time.Sleep(time.Second * 10)
+			continue
+		}
+		if r == nil {
+			return
+		}
+
+		logger.Infof("backfill worker %v exporting epochs %v-%v (attempt %v)", worker, r.NextEpoch, r.EndEpoch, r.Attempts)
+		err = backfillRange(client, r.StartEpoch, r.NextEpoch, r.EndEpoch)
+		if err != nil {
+			logger.Errorf("backfill worker %v failed to export range %v-%v: %v", worker, r.StartEpoch, r.EndEpoch, err)
+			err = db.FailBackfillRange(r.StartEpoch, err)
+		} else {
+			err = db.CompleteBackfillRange(r.StartEpoch)
+		}
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// backfillRange exports the epochs from nextEpoch to endEpoch and checkpoints the progress after every epoch
+func backfillRange(client rpc.Client, startEpoch, nextEpoch, endEpoch uint64) error {
+	for epoch := nextEpoch; epoch <= endEpoch; epoch++ {
+		data, err := client.GetEpochData(epoch, false)
+		if err != nil {
+			return fmt.Errorf("error retrieving data of epoch %v: %v", epoch, err)
+		}
+		if len(data.Validators) == 0 {
+			return fmt.Errorf("error retrieving data of epoch %v: no validators received for epoch", epoch)
+		}
+		err = saveEpochData(data)
+		recordEpochExportResult(epoch, err)
+		if err != nil {
+			return fmt.Errorf("error saving epoch %v: %v", epoch, err)
+		}
+		metrics.BackfillEpochsExported.Inc()
+
+		err = db.SetBackfillCheckpoint(startEpoch, epoch+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateBackfillProgress publishes the state of the backfill work queue as metrics
+func updateBackfillProgress() *types.BackfillProgress {
+	progress, err := db.GetBackfillProgress()
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+	metrics.BackfillRanges.WithLabelValues("pending").Set(float64(progress.Pending))
+	metrics.BackfillRanges.WithLabelValues("running").Set(float64(progress.Running))
+	metrics.BackfillRanges.WithLabelValues("done").Set(float64(progress.Done))
+	metrics.BackfillRanges.WithLabelValues("failed").Set(float64(progress.Failed))
+	metrics.BackfillEpochsRemaining.Set(float64(progress.Remaining))
+	logger.Infof("backfill progress: %v pending, %v running, %v done, %v failed ranges, %v epochs remaining", progress.Pending, progress.Running, progress.Done, progress.Failed, progress.Remaining)
+	return progress
+}
@@ -0,0 +1,61 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+)
+
+const builderAttributionBatchSize = 10000
+
+// buildersExporter attributes the proposed blocks to their builders. The relay data of a block is usually exported after
+// the block itself, so the blocks of the last day are attributed again in every run.
+func buildersExporter() {
+	for {
+		err := attributeBlockBuilders()
+		if err != nil {
+			logger.Errorf("error attributing blocks to builders: %v", err)
+		}
+		time.Sleep(time.Minute * 5)
+	}
+}
+
+func attributeBlockBuilders() error {
+	var headSlot uint64
+	err := db.ReaderDb.Get(&headSlot, `SELECT COALESCE(MAX(slot), 0) FROM blocks`)
+	if err != nil {
+		return fmt.Errorf("error retrieving head slot: %v", err)
+	}
+	lastSlot, err := db.GetLastAttributedBuilderSlot()
+	if err != nil {
+		return err
+	}
+
+	// the first run starts at the first block with an execution payload, blocks before the merge have no builder
+	fromSlot := uint64(0)
+	if lastSlot == 0 {
+		firstSlot, ok, err := db.GetFirstExecutionBlockSlot()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		fromSlot = firstSlot
+	} else if dayInSlots := 24 * 60 * 60 / utils.Config.Chain.Config.SecondsPerSlot; lastSlot > dayInSlots {
+		fromSlot = lastSlot - dayInSlots
+	}
+
+	start := time.Now()
+	attributed := int64(0)
+	for slot := fromSlot; slot <= headSlot; slot += builderAttributionBatchSize {
+		n, err := db.AttributeBlockBuilders(slot, slot+builderAttributionBatchSize-1)
+		if err != nil {
+			return err
+		}
+		attributed += n
+	}
+	logger.Infof("attributed %v blocks of slots %v-%v to builders, took %v", attributed, fromSlot, headSlot, time.Since(start))
+	return nil
+}
@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/rpc"
+	"eth2-exporter/utils"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func dutiesExporter(client rpc.Client) {
+	logger.Infoln("Started duties exporter")
+	lastEpoch := int64(-1)
+	for {
+		epoch, err := exportDuties(client, lastEpoch)
+		if err != nil {
+			logger.Errorf("error exporting duties: %v", err)
+		} else {
+			lastEpoch = epoch
+		}
+		time.Sleep(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot))
+	}
+}
+
+// exportDuties stores the assignments of the current and next epoch and the members of the current and next sync
+// committee once the head reaches a new epoch and returns the epoch of the head
+func exportDuties(client rpc.Client, lastEpoch int64) (int64, error) {
+	head, err := client.GetChainHead()
+	if err != nil {
+		return lastEpoch, fmt.Errorf("error retrieving chain head: %v", err)
+	}
+	if int64(head.HeadEpoch) == lastEpoch {
+		return lastEpoch, nil
+	}
+
+	start := time.Now()
+	for _, epoch := range []uint64{head.HeadEpoch, head.HeadEpoch + 1} {
+		assignments, err := client.GetEpochAssignments(epoch)
+		if err != nil {
+			return lastEpoch, fmt.Errorf("error retrieving assignments of epoch %v: %v", epoch, err)
+		}
+		err = db.SaveEpochDuties(epoch, assignments)
+		if err != nil {
+			return lastEpoch, fmt.Errorf("error saving duties of epoch %v: %v", epoch, err)
+		}
+	}
+
+	if head.HeadEpoch >= utils.Config.Chain.Config.AltairForkEpoch {
+		err = exportSyncCommitteeDuties(client, head.HeadEpoch)
+		if err != nil {
+			return lastEpoch, err
+		}
+	}
+
+	logger.Infof("exported duties of epochs %v and %v in %v", head.HeadEpoch, head.HeadEpoch+1, time.Since(start))
+	metrics.TaskDuration.WithLabelValues("export_duties").Observe(time.Since(start).Seconds())
+	return int64(head.HeadEpoch), nil
+}
+
+// exportSyncCommitteeDuties stores the members of the current and next sync committee, both are known to the head state
+func exportSyncCommitteeDuties(client rpc.Client, headEpoch uint64) error {
+	period := utils.SyncPeriodOfEpoch(headEpoch)
+	for _, epoch := range []uint64{headEpoch, utils.FirstEpochOfSyncPeriod(period + 1)} {
+		syncCommittee, err := client.GetSyncCommittee("head", epoch)
+		if err != nil {
+			return fmt.Errorf("error retrieving sync committee of epoch %v: %v", epoch, err)
+		}
+		validators := make([]uint64, 0, len(syncCommittee.Validators))
+		for _, v := range syncCommittee.Validators {
+			validator, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("error parsing sync committee member %v of epoch %v: %v", v, epoch, err)
+			}
+			validators = append(validators, validator)
+		}
+		err = db.SaveSyncCommitteeDuties(utils.SyncPeriodOfEpoch(epoch), validators)
+		if err != nil {
+			return fmt.Errorf("error saving sync committee duties of epoch %v: %v", epoch, err)
+		}
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"sync"
@@ -21,15 +22,22 @@ import (
 
 var logger = logrus.New().WithField("module", "exporter")
 
-// If exporting an epoch fails for 10 consecutive times exporting this epoch will be disabled
-// This is a workaround for a bug in the prysm archive node that causes epochs without blocks
-// to not be archived properly (see https://github.com/prysmaticlabs/prysm/issues/4165)
-var epochBlacklist = make(map[uint64]uint64)
 var saveEpochMux = &sync.Mutex{}
 var fullCheckRunning = uint64(0)
 
+// lastBeaconEventTs holds the unix timestamp of the last event received via the beacon node event stream
+var lastBeaconEventTs = int64(0)
+
+// lastFinalizedEventEpoch holds the epoch of the last finalized checkpoint received via the beacon node event stream
+var lastFinalizedEventEpoch = uint64(0)
+
 // Start will start the export of data from rpc into the database
 func Start(client rpc.Client) error {
+	if utils.Config.Indexer.LeaderElection.Enabled {
+		// only the leader exports, the other instances wait until the leader lock is released
+		campaignForLeadership()
+	}
+
 	//go performanceDataUpdater()
 	//go networkLivenessUpdater(client)
 	//go eth1DepositsExporter()
@@ -39,6 +47,7 @@ func Start(client rpc.Client) error {
 	//go syncCommitteesExporter(client)
 	if utils.Config.SSVExporter.Enabled {
 		go ssvExporter()
+		go ssvOperatorStatsUpdater()
 	}
 	if utils.Config.RocketpoolExporter.Enabled {
 		go rocketpoolExporter()
@@ -50,6 +59,29 @@ func Start(client rpc.Client) error {
 
 	if utils.Config.MevBoostRelayExporter.Enabled {
 		go mevBoostRelaysExporter()
+		go relayPaymentsChecker()
+		go buildersExporter()
+	}
+
+	if utils.Config.PoolAdapters.Enabled {
+		go poolAdaptersExporter()
+	}
+
+	if utils.Config.Indexer.RewardsExporter.Enabled {
+		go rewardsExporter(client)
+	}
+
+	if utils.Config.Indexer.PackingExporter.Enabled {
+		go packingExporter(client)
+		go operationPoolExporter(client)
+	}
+
+	if utils.Config.Indexer.DutiesExporter.Enabled {
+		go dutiesExporter(client)
+	}
+
+	if utils.Config.Indexer.Slasher.Enabled {
+		activeSlasher = newSlasher(utils.Config.Indexer.Slasher.HistoryEpochs)
 	}
 	// wait until the beacon-node is available
 	for {
@@ -68,12 +100,13 @@ func Start(client rpc.Client) error {
 			logger.Fatal(err)
 		}
 
-		for epoch := uint64(1); epoch <= head.HeadEpoch; epoch++ {
-			err := ExportEpoch(epoch, client)
-
-			if err != nil {
-				logger.Error(err)
-			}
+		err = EnqueueBackfill(1, head.HeadEpoch)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		err = Backfill(client)
+		if err != nil {
+			logger.Error(err)
 		}
 	}
 
@@ -106,29 +139,31 @@ func Start(client rpc.Client) error {
 		}
 
 		if len(epochs) > 0 && epochs[0] != 0 {
-			err := ExportEpoch(0, client)
+			err := EnqueueBackfill(0, epochs[0]-1)
 			if err != nil {
-				logger.Error(err)
+				logger.Fatal(err)
 			}
-			logger.Printf("finished export for epoch %v", 0)
-			epochs = append([]uint64{0}, epochs...)
 		}
 
 		for i := 0; i < len(epochs)-1; i++ {
 			if epochs[i] != epochs[i+1]-1 && epochs[i] != epochs[i+1] {
 				logger.Println("Epochs between", epochs[i], "and", epochs[i+1], "are missing!")
 
-				for epoch := epochs[i]; epoch <= epochs[i+1]; epoch++ {
-					err := ExportEpoch(epoch, client)
-					if err != nil {
-						logger.Error(err)
-					}
-					logger.Printf("finished export for epoch %v", epoch)
+				err := EnqueueBackfill(epochs[i]+1, epochs[i+1]-1)
+				if err != nil {
+					logger.Fatal(err)
 				}
 			}
 		}
+
+		err = Backfill(client)
+		if err != nil {
+			logger.Error(err)
+		}
 	}
 
+	go backfillQueueExporter(client)
+
 	if utils.Config.Indexer.CheckAllBlocksOnStartup {
 		// Make sure that all blocks are correct by comparing all block hashes in the database to the ones we have in the node
 		head, err := client.GetChainHead()
@@ -199,9 +234,6 @@ func Start(client rpc.Client) error {
 
 			if err != nil {
 				logger.Errorf("error exporting epoch: %v", err)
-				if utils.EpochToTime(epoch).Before(time.Now().Add(time.Hour * -24)) {
-					epochBlacklist[epoch]++
-				}
 			}
 		}
 	}
@@ -219,50 +251,141 @@ func Start(client rpc.Client) error {
 		}
 	}
 
-	newBlockChan := client.GetNewBlockChan()
-
 	lastExportedSlot := uint64(0)
 
 	// doFullCheck(client)
 
 	logger.Infof("entering monitoring mode")
-	for {
-		block := <-newBlockChan
-		// Do a full check on any epoch transition or after during the first run
-		if utils.EpochOfSlot(lastExportedSlot) != utils.EpochOfSlot(block.Slot) || utils.EpochOfSlot(block.Slot) == 0 {
-			go func() {
-				v := atomic.LoadUint64(&fullCheckRunning)
-				if v == 1 {
-					logger.Infof("skipping full check as one is already running")
-					return
-				}
-				atomic.StoreUint64(&fullCheckRunning, 1)
-				doFullCheck(client, 0)
-				atomic.StoreUint64(&fullCheckRunning, 0)
-			}()
-		}
+	for ev := range client.GetEventChan() {
+		metrics.BeaconEventsReceived.WithLabelValues(ev.Topic()).Inc()
+		atomic.StoreInt64(&lastBeaconEventTs, time.Now().Unix())
+
+		switch e := ev.(type) {
+		case *types.BlockEvent:
+			block := e.Block
+			// Do a full check on any epoch transition or after during the first run
+			if utils.EpochOfSlot(lastExportedSlot) != utils.EpochOfSlot(block.Slot) || utils.EpochOfSlot(block.Slot) == 0 {
+				go func() {
+					v := atomic.LoadUint64(&fullCheckRunning)
+					if v == 1 {
+						logger.Infof("skipping full check as one is already running")
+						return
+					}
+					atomic.StoreUint64(&fullCheckRunning, 1)
+					doFullCheck(client, 0)
+					atomic.StoreUint64(&fullCheckRunning, 0)
+				}()
+			}
 
-		blocksMap := make(map[uint64]map[string]*types.Block)
-		if blocksMap[block.Slot] == nil {
-			blocksMap[block.Slot] = make(map[string]*types.Block)
-		}
-		blocksMap[block.Slot][fmt.Sprintf("%x", block.BlockRoot)] = block
+			exportStreamedBlock(block)
+			lastExportedSlot = block.Slot
 
-		err := db.BigtableClient.SaveAttestations(blocksMap)
-		if err != nil {
-			logrus.Errorf("error exporting attestations to bigtable for block %v: %v", block.Slot, err)
-		}
-		err = db.BigtableClient.SaveSyncComitteeDuties(blocksMap)
-		if err != nil {
-			logrus.Errorf("error exporting sync committee duties to bigtable for block %v: %v", block.Slot, err)
-		}
+			err := db.SaveBlockArrival(e.Slot, e.BlockRoot, e.ReceivedAt)
+			if err != nil {
+				logger.Error(err)
+			}
+		case *types.HeadEvent:
+			if e.EpochTransition {
+				go handleEpochTransition(client, utils.EpochOfSlot(e.Slot))
+			}
 
-		err = db.SaveBlock(block)
-		if err != nil {
-			logger.Errorf("error saving block: %v", err)
+			err := db.SaveHeadArrival(e.Slot, e.Block, e.ReceivedAt)
+			if err != nil {
+				logger.Error(err)
+			}
+		case *types.FinalizedCheckpointEvent:
+			go handleFinalizedCheckpoint(client, e)
+		case *types.ChainReorgEvent:
+			go handleChainReorg(client, e)
+		case *types.VoluntaryExitEvent:
+			logger.Infof("received voluntary exit of validator %v for epoch %v", e.ValidatorIndex, e.Epoch)
+		case *types.AttesterSlashingEvent:
+			logger.Infof("received attester slashing for target epoch %v", e.Attestation1.Data.Target.Epoch)
+			err := detectAttesterSlashingEventSlashings(e)
+			if err != nil {
+				logger.Errorf("error detecting potential slashings of attester slashing event: %v", err)
+			}
 		}
-		lastExportedSlot = block.Slot
 	}
+	return fmt.Errorf("beacon node event stream closed")
+}
+
+// exportStreamedBlock saves a block received via the event stream
+func exportStreamedBlock(block *types.Block) {
+	blocksMap := make(map[uint64]map[string]*types.Block)
+	if blocksMap[block.Slot] == nil {
+		blocksMap[block.Slot] = make(map[string]*types.Block)
+	}
+	blocksMap[block.Slot][fmt.Sprintf("%x", block.BlockRoot)] = block
+
+	canonicalRoots, err := canonicalBlockRoots(blocksMap)
+	if err != nil {
+		logger.Errorf("error retrieving canonical block roots for the attestations of block %v: %v", block.Slot, err)
+	}
+	err = db.BigtableClient.SaveAttestations(blocksMap, canonicalRoots)
+	if err != nil {
+		logrus.Errorf("error exporting attestations to bigtable for block %v: %v", block.Slot, err)
+	}
+	err = db.BigtableClient.SaveSyncComitteeDuties(blocksMap)
+	if err != nil {
+		logrus.Errorf("error exporting sync committee duties to bigtable for block %v: %v", block.Slot, err)
+	}
+
+	err = db.SaveBlock(block)
+	if err != nil {
+		logger.Errorf("error saving block: %v", err)
+	}
+
+	err = detectSlashings(blocksMap)
+	if err != nil {
+		logger.Errorf("error detecting potential slashings of block %v: %v", block.Slot, err)
+	}
+}
+
+// handleEpochTransition updates the participation of the epochs whose attestation inclusion window just ended or progressed
+func handleEpochTransition(client rpc.Client, epoch uint64) {
+	if epoch == 0 {
+		return
+	}
+	startEpoch := epoch - 1
+	if startEpoch > 0 {
+		startEpoch--
+	}
+	logger.Infof("epoch transition to epoch %v, updating status of epochs %v-%v", epoch, startEpoch, epoch-1)
+	err := updateEpochStatus(client, startEpoch, epoch-1)
+	if err != nil {
+		logger.Errorf("error updating epoch status: %v", err)
+	}
+}
+
+// handleFinalizedCheckpoint updates the status of all epochs finalized since the last finalized checkpoint and marks them as finalized
+func handleFinalizedCheckpoint(client rpc.Client, e *types.FinalizedCheckpointEvent) {
+	lastFinalizedEpoch := atomic.SwapUint64(&lastFinalizedEventEpoch, e.Epoch)
+	if e.Epoch <= lastFinalizedEpoch {
+		return
+	}
+
+	// limit the update to the last 10 epochs, older epochs have already been finalized when the exporter started
+	startEpoch := lastFinalizedEpoch + 1
+	if e.Epoch > 10 && e.Epoch-10 > startEpoch {
+		startEpoch = e.Epoch - 10
+	}
+
+	logger.Infof("checkpoint of epoch %v finalized, updating status of epochs %v-%v", e.Epoch, startEpoch, e.Epoch)
+	err := updateEpochStatus(client, startEpoch, e.Epoch)
+	if err != nil {
+		logger.Errorf("error updating epoch status: %v", err)
+	}
+	err = db.UpdateEpochFinalization(e.Epoch)
+	if err != nil {
+		logger.Errorf("error updating finalization of epochs: %v", err)
+	}
+}
+
+// eventStreamActive returns true if an event has been received from the beacon node within the last epoch
+func eventStreamActive() bool {
+	lastEvent := time.Unix(atomic.LoadInt64(&lastBeaconEventTs), 0)
+	return time.Since(lastEvent) < utils.EpochToTime(1).Sub(utils.EpochToTime(0))
 }
 
 // Will ensure the db is fully in sync with the node
@@ -349,6 +472,11 @@ func doFullCheck(client rpc.Client, lookback uint64) {
 		}
 	}
 
+	err = recordDetectedReorg(head, blocksMap)
+	if err != nil {
+		logger.Errorf("error recording reorg detected by head comparison: %v", err)
+	}
+
 	// Add any missing epoch to the export set (might happen if the indexer was stopped for a long period of time)
 	epochs, err := db.GetAllEpochs()
 	if err != nil {
@@ -383,6 +511,21 @@ func doFullCheck(client rpc.Client, lookback uint64) {
 		}
 	}
 
+	// Retry the export of epochs that failed previously
+	failures, err := db.GetEpochExportFailures()
+	if err != nil {
+		logger.Errorf("error retrieving epoch export failures: %v", err)
+		return
+	}
+	failedAttempts := make(map[uint64]uint64, len(failures))
+	for _, failure := range failures {
+		failedAttempts[failure.Epoch] = failure.Attempts
+		if failure.Attempts <= db.MaxEpochExportAttempts {
+			logger.Printf("queuing epoch %v for export as it failed %v times before: %v", failure.Epoch, failure.Attempts, failure.LastError)
+			epochsToExport[failure.Epoch] = true
+		}
+	}
+
 	logger.Printf("exporting %v epochs.", len(epochsToExport))
 
 	keys := make([]uint64, 0)
@@ -394,8 +537,11 @@ func doFullCheck(client rpc.Client, lookback uint64) {
 	})
 
 	for _, epoch := range keys {
-		if epochBlacklist[epoch] > 3 {
-			logger.Printf("skipping export of epoch %v as it has errored %d times", epoch, epochBlacklist[epoch])
+		// If exporting an epoch older than a day failed too often exporting this epoch is disabled until it is retried manually
+		// This is a workaround for a bug in the prysm archive node that causes epochs without blocks
+		// to not be archived properly (see https://github.com/prysmaticlabs/prysm/issues/4165)
+		if db.IsEpochExportStuck(epoch, failedAttempts[epoch]) {
+			logger.Printf("skipping export of epoch %v as it has errored %d times", epoch, failedAttempts[epoch])
 			continue
 		}
 
@@ -405,9 +551,6 @@ func doFullCheck(client rpc.Client, lookback uint64) {
 
 		if err != nil {
 			logger.Errorf("error exporting epoch: %v", err)
-			if utils.EpochToTime(epoch).Before(time.Now().Add(time.Hour * -24)) {
-				epochBlacklist[epoch]++
-			}
 		}
 		logger.Printf("finished export for epoch %v", epoch)
 	}
@@ -418,23 +561,27 @@ func doFullCheck(client rpc.Client, lookback uint64) {
 		logger.Errorf("error marking orphaned blocks: %v", err)
 	}
 
-	// Update epoch statistics up to 10 epochs after the last finalized epoch
-	startEpoch = uint64(0)
-	if head.FinalizedEpoch > 10 {
-		startEpoch = head.FinalizedEpoch - 10
-		if head.HeadEpoch-startEpoch > 10 {
-			startEpoch = head.HeadEpoch - 10
+	// Epoch statistics and finalization are updated when receiving head and finalized checkpoint events,
+	// only poll them if the event stream of the beacon node is not active
+	if !eventStreamActive() {
+		// Update epoch statistics up to 10 epochs after the last finalized epoch
+		startEpoch = uint64(0)
+		if head.FinalizedEpoch > 10 {
+			startEpoch = head.FinalizedEpoch - 10
+			if head.HeadEpoch-startEpoch > 10 {
+				startEpoch = head.HeadEpoch - 10
+			}
+		}
+		logger.Infof("event stream inactive, updating status of epochs %v-%v", startEpoch, head.HeadEpoch)
+		err = updateEpochStatus(client, startEpoch, head.HeadEpoch)
+		if err != nil {
+			logger.Errorf("error updating epoch stratus: %v", err)
+		}
+		// set all finalized epochs to finalized
+		err = db.UpdateEpochFinalization(head.FinalizedEpoch)
+		if err != nil {
+			logger.Errorf("error updating finalization of epochs: %v", err)
 		}
-	}
-	logger.Infof("updating status of epochs %v-%v", startEpoch, head.HeadEpoch)
-	err = updateEpochStatus(client, startEpoch, head.HeadEpoch)
-	if err != nil {
-		logger.Errorf("error updating epoch stratus: %v", err)
-	}
-	// set all finalized epochs to finalized
-	err = db.UpdateEpochFinalization(head.FinalizedEpoch)
-	if err != nil {
-		logger.Errorf("error updating finalization of epochs: %v", err)
 	}
 
 	logger.Infof("exporting validation queue")
@@ -493,98 +640,193 @@ func ExportEpoch(epoch uint64, client rpc.Client) error {
 	logger.Printf("retrieving data for epoch %v", epoch)
 	data, err := client.GetEpochData(epoch, false)
 	if err != nil {
-		return fmt.Errorf("error retrieving epoch data: %v", err)
+		err = fmt.Errorf("error retrieving epoch data: %v", err)
+		recordEpochExportResult(epoch, err)
+		return err
 	}
 	metrics.TaskDuration.WithLabelValues("rpc_get_epoch_data").Observe(time.Since(startGetEpochData).Seconds())
 	logger.WithFields(logrus.Fields{"duration": time.Since(startGetEpochData), "epoch": epoch}).Info("completed getting epoch-data")
 	logger.Printf("data for epoch %v retrieved, took %v", epoch, time.Since(start))
 
 	if len(data.Validators) == 0 {
-		return fmt.Errorf("error retrieving epoch data: no validators received for epoch")
+		err = fmt.Errorf("error retrieving epoch data: no validators received for epoch")
+		recordEpochExportResult(epoch, err)
+		return err
 	}
 
 	go func() {
-		saveEpochMux.Lock()
-		defer saveEpochMux.Unlock()
-		logger.Infof("acquired saveEpochMux lock for epoch %v", data.Epoch)
-		// export epoch data to bigtable
-		g := new(errgroup.Group)
-		g.Go(func() error {
-			err = db.BigtableClient.SaveValidatorBalances(epoch, data.Validators)
-			if err != nil {
-				return fmt.Errorf("error exporting validator balances to bigtable: %v", err)
-			}
-			return nil
-		})
-		g.Go(func() error {
-			err = db.BigtableClient.SaveAttestationAssignments(epoch, data.ValidatorAssignmentes.AttestorAssignments)
-			if err != nil {
-				return fmt.Errorf("error exporting attestation assignments to bigtable: %v", err)
-			}
-			return nil
-		})
-		g.Go(func() error {
-			err = db.BigtableClient.SaveProposalAssignments(epoch, data.ValidatorAssignmentes.ProposerAssignments)
-			if err != nil {
-				return fmt.Errorf("error exporting proposal assignments to bigtable: %v", err)
-			}
-			return nil
-		})
-		g.Go(func() error {
-			err = db.BigtableClient.SaveAttestations(data.Blocks)
-			if err != nil {
-				return fmt.Errorf("error exporting attestations to bigtable: %v", err)
-			}
-			return nil
-		})
-		g.Go(func() error {
-			err = db.BigtableClient.SaveProposals(data.Blocks)
-			if err != nil {
-				return fmt.Errorf("error exporting proposals to bigtable: %v", err)
-			}
-			return nil
-		})
-		g.Go(func() error {
-			err = db.BigtableClient.SaveSyncComitteeDuties(data.Blocks)
-			if err != nil {
-				return fmt.Errorf("error exporting sync committee duties to bigtable: %v", err)
-			}
-			return nil
-		})
-		g.Go(func() error {
-			attestedSlots := make(map[uint64]uint64)
-			for _, blockkv := range data.Blocks {
-				for _, block := range blockkv {
-					for _, attestation := range block.Attestations {
-						for _, validator := range attestation.Attesters {
-							if block.Slot > attestedSlots[validator] {
-								attestedSlots[validator] = block.Slot
-							}
+		err := saveEpochData(data)
+		if err != nil {
+			logger.Error(err)
+		}
+		recordEpochExportResult(epoch, err)
+	}()
+	return nil
+}
+
+// recordEpochExportResult persists a failed export attempt of the epoch or clears the recorded failures once it succeeded
+func recordEpochExportResult(epoch uint64, exportErr error) {
+	var err error
+	if exportErr != nil {
+		err = db.RecordEpochExportFailure(epoch, exportErr)
+	} else {
+		err = db.ClearEpochExportFailure(epoch)
+	}
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+// saveEpochData writes the epoch data to bigtable and the database, saves of different epochs are serialized
+func saveEpochData(data *types.EpochData) error {
+	if !isExporterLeader() {
+		return fmt.Errorf("not saving epoch %v as this instance is not the exporter leader", data.Epoch)
+	}
+
+	saveEpochMux.Lock()
+	defer saveEpochMux.Unlock()
+	logger.Infof("acquired saveEpochMux lock for epoch %v", data.Epoch)
+	epoch := data.Epoch
+	// export epoch data to bigtable
+	g := new(errgroup.Group)
+	g.Go(func() error {
+		err := db.BigtableClient.SaveValidatorBalances(epoch, data.Validators)
+		if err != nil {
+			return fmt.Errorf("error exporting validator balances to bigtable: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		err := db.BigtableClient.SaveAttestationAssignments(epoch, data.ValidatorAssignmentes.AttestorAssignments)
+		if err != nil {
+			return fmt.Errorf("error exporting attestation assignments to bigtable: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		err := db.BigtableClient.SaveProposalAssignments(epoch, data.ValidatorAssignmentes.ProposerAssignments)
+		if err != nil {
+			return fmt.Errorf("error exporting proposal assignments to bigtable: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		canonicalRoots, err := canonicalBlockRoots(data.Blocks)
+		if err != nil {
+			return fmt.Errorf("error retrieving canonical block roots: %v", err)
+		}
+		err = db.BigtableClient.SaveAttestations(data.Blocks, canonicalRoots)
+		if err != nil {
+			return fmt.Errorf("error exporting attestations to bigtable: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		err := db.BigtableClient.SaveProposals(data.Blocks)
+		if err != nil {
+			return fmt.Errorf("error exporting proposals to bigtable: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		err := db.BigtableClient.SaveSyncComitteeDuties(data.Blocks)
+		if err != nil {
+			return fmt.Errorf("error exporting sync committee duties to bigtable: %v", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		attestedSlots := make(map[uint64]uint64)
+		for _, blockkv := range data.Blocks {
+			for _, block := range blockkv {
+				for _, attestation := range block.Attestations {
+					for _, validator := range attestation.Attesters {
+						if block.Slot > attestedSlots[validator] {
+							attestedSlots[validator] = block.Slot
 						}
 					}
 				}
 			}
+		}
 
-			err = services.SetLastAttestationSlots(attestedSlots)
-			if err != nil {
-				return fmt.Errorf("error settings last attestation slots for epoch %v: %v", data.Epoch, err)
-			}
-			return nil
-		})
-		err = g.Wait()
+		err := services.SetLastAttestationSlots(attestedSlots)
 		if err != nil {
-			logger.Errorf("error during bigtable export: %v", err)
-			return
+			return fmt.Errorf("error settings last attestation slots for epoch %v: %v", data.Epoch, err)
 		}
-		err = db.SaveEpoch(data)
+		return nil
+	})
+	g.Go(func() error {
+		err := detectSlashings(data.Blocks)
 		if err != nil {
-			logger.Errorf("error saving epoch data: %v", err)
-			return
+			return fmt.Errorf("error detecting potential slashings of epoch %v: %v", data.Epoch, err)
 		}
-	}()
+		return nil
+	})
+	err := g.Wait()
+	if err != nil {
+		return fmt.Errorf("error during bigtable export: %v", err)
+	}
+	err = db.SaveEpoch(data)
+	if err != nil {
+		return fmt.Errorf("error saving epoch data: %v", err)
+	}
 	return nil
 }
 
+// canonicalBlockRoots returns the canonical block roots the attestations of the blocks can vote for. The canonical
+// status of the blocks takes precedence over the blocks stored in the database as they might not have been saved yet.
+func canonicalBlockRoots(blocks map[uint64]map[string]*types.Block) (*types.CanonicalBlockRoots, error) {
+	fromSlot := uint64(math.MaxUint64)
+	toSlot := uint64(0)
+	for slot, blocksOfSlot := range blocks {
+		if slot > toSlot {
+			toSlot = slot
+		}
+		for _, b := range blocksOfSlot {
+			for _, a := range b.Attestations {
+				if a.Data == nil || a.Data.Source == nil {
+					continue
+				}
+				if sourceSlot := a.Data.Source.Epoch * utils.Config.Chain.Config.SlotsPerEpoch; sourceSlot < fromSlot {
+					fromSlot = sourceSlot
+				}
+			}
+		}
+	}
+	if fromSlot > toSlot {
+		return nil, nil
+	}
+	// include the epoch before the earliest source to resolve missed slots at the start of the source epoch
+	if fromSlot >= utils.Config.Chain.Config.SlotsPerEpoch {
+		fromSlot -= utils.Config.Chain.Config.SlotsPerEpoch
+	} else {
+		fromSlot = 0
+	}
+
+	roots, err := db.GetCanonicalBlockRoots(fromSlot, toSlot)
+	if err != nil {
+		return nil, err
+	}
+	for slot, blocksOfSlot := range blocks {
+		for _, b := range blocksOfSlot {
+			// scheduled slots are not known yet, missed slots are known to have no canonical block
+			if b.Status == 0 {
+				continue
+			}
+			roots.Known[slot] = true
+			if len(b.BlockRoot) != 32 {
+				continue
+			}
+			if b.Canonical {
+				roots.Roots[slot] = b.BlockRoot
+			} else if bytes.Equal(roots.Roots[slot], b.BlockRoot) {
+				delete(roots.Roots, slot)
+			}
+		}
+	}
+	return roots, nil
+}
+
 func exportValidatorQueue(client rpc.Client) error {
 	queue, err := client.GetValidatorQueue()
 	if err != nil {
@@ -0,0 +1,140 @@
+package exporter
+
+import (
+	"context"
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/services"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"cloud.google.com/go/bigtable/bttest"
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// TestReplayExportEpoch exports epoch 1 of the fixtures in rpc/testdata/replay. It runs against the postgres database
+// given by TEST_DB_URL, which has to contain the tables of tables.sql, the previously exported data of epoch 1 is
+// replaced. Bigtable is served by an in-memory emulator.
+func TestReplayExportEpoch(t *testing.T) {
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		t.Skip("TEST_DB_URL not set")
+	}
+
+	conn, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test db: %v", err)
+	}
+	defer conn.Close()
+	writer, reader := db.WriterDb, db.ReaderDb
+	db.WriterDb, db.ReaderDb = conn, conn
+	defer func() { db.WriterDb, db.ReaderDb = writer, reader }()
+
+	for _, table := range []string{"epochs", "blocks", "epoch_export_failures"} {
+		if _, err := conn.Exec("DELETE FROM " + table + " WHERE epoch = 1"); err != nil {
+			t.Fatalf("error cleaning up table %v: %v", table, err)
+		}
+	}
+
+	srv, err := bttest.NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	t.Setenv("BIGTABLE_EMULATOR_HOST", srv.Addr)
+
+	ctx := context.Background()
+	admin, err := gcp_bigtable.NewAdminClient(ctx, "test", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	if err := admin.CreateTable(ctx, "beaconchain"); err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range []string{db.DEFAULT_FAMILY, db.VALIDATOR_BALANCES_FAMILY, db.ATTESTATIONS_FAMILY, db.PROPOSALS_FAMILY, db.SYNC_COMMITTEES_FAMILY, db.VALIDATOR_REWARDS_FAMILY} {
+		if err := admin.CreateColumnFamily(ctx, "beaconchain", family); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bt, err := db.InitBigtable("test", "test", "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bt.Close()
+
+	if err := services.InitLastAttestationCache(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the chain of the fixtures has 8 validators and 4 slots per epoch, the block of slot 6 is missed
+	utils.Config = &types.Config{}
+	utils.Config.Chain.GenesisTimestamp = 1606824023
+	utils.Config.Chain.Config.SlotsPerEpoch = 4
+	utils.Config.Chain.Config.SecondsPerSlot = 12
+	utils.Config.Chain.Config.AltairForkEpoch = 1 << 60
+
+	client, stop, err := rpc.NewReplayClient("../rpc/testdata/replay", big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	err = ExportEpoch(1, client)
+	if err != nil {
+		t.Fatalf("error exporting replayed epoch: %v", err)
+	}
+
+	// the epoch data is saved in the background
+	var validatorsCount uint64
+	for deadline := time.Now().Add(time.Second * 30); ; {
+		var failure string
+		err = conn.Get(&failure, "SELECT last_error FROM epoch_export_failures WHERE epoch = 1")
+		if err == nil {
+			t.Fatalf("error saving replayed epoch: %v", failure)
+		}
+		err = conn.Get(&validatorsCount, "SELECT validatorscount FROM epochs WHERE epoch = 1")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("replayed epoch was not saved: %v", err)
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+	if validatorsCount != 8 {
+		t.Errorf("expected 8 validators for the replayed epoch, got %v", validatorsCount)
+	}
+
+	var statuses []struct {
+		Slot   uint64 `db:"slot"`
+		Status string `db:"status"`
+	}
+	err = conn.Select(&statuses, "SELECT slot, status FROM blocks WHERE epoch = 1 ORDER BY slot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[uint64]string{4: "1", 5: "1", 6: "2", 7: "1"}
+	if len(statuses) != len(expected) {
+		t.Fatalf("expected blocks for 4 slots, got %+v", statuses)
+	}
+	for _, b := range statuses {
+		if expected[b.Slot] != b.Status {
+			t.Errorf("unexpected status %v of slot %v, expected %v", b.Status, b.Slot, expected[b.Slot])
+		}
+	}
+
+	balances, err := bt.GetValidatorBalanceHistory([]uint64{3}, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balances[3]) != 1 || balances[3][0].Balance != 32000003000 {
+		t.Errorf("unexpected balance history of validator 3 in bigtable: %+v", balances[3])
+	}
+}
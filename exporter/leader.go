@@ -0,0 +1,90 @@
+package exporter
+
+import (
+	"context"
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/utils"
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultLeaderElectionInterval = time.Second * 5
+
+// leaderElection is set if the exporter runs as one of several redundant indexer instances
+var leaderElection *db.LeaderElection
+
+// campaignForLeadership blocks until this instance has become the exporter leader. Once elected the instance keeps the
+// leadership until the process ends, if the leader lock is lost the process exits so that it can not write concurrently
+// to the instance that took over. Campaigning again after the leadership has been won is a no-op, a second campaign
+// would open a new session that could never acquire the lock held by the first one.
+func campaignForLeadership() {
+	if leaderElection != nil {
+		return
+	}
+	le, instance := newExporterLeaderElection()
+
+	logger.Infof("instance %v waiting to become the exporter leader", instance)
+	err := le.Campaign(context.Background())
+	if err != nil {
+		logger.Fatalf("error campaigning for exporter leadership: %v", err)
+	}
+	becomeExporterLeader(le, instance)
+}
+
+// TryExporterLeadership acquires the exporter leadership if no other instance holds it and reports whether this
+// instance is the leader afterwards. It does not wait for a live leader to release the lock.
+func TryExporterLeadership() (bool, error) {
+	if leaderElection != nil {
+		return true, nil
+	}
+	le, instance := newExporterLeaderElection()
+
+	ctx, cancel := context.WithTimeout(context.Background(), le.Interval())
+	defer cancel()
+	err := le.Campaign(ctx)
+	if err == context.DeadlineExceeded {
+		le.Close()
+		logger.Infof("instance %v did not become the exporter leader, the leader lock is held by another instance", instance)
+		return false, nil
+	}
+	if err != nil {
+		le.Close()
+		return false, fmt.Errorf("error campaigning for exporter leadership: %v", err)
+	}
+	becomeExporterLeader(le, instance)
+	return true, nil
+}
+
+func newExporterLeaderElection() (*db.LeaderElection, string) {
+	interval := utils.Config.Indexer.LeaderElection.Interval
+	if interval <= 0 {
+		interval = defaultLeaderElectionInterval
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.Fatalf("error retrieving hostname: %v", err)
+	}
+	instance := fmt.Sprintf("%v-%v", hostname, os.Getpid())
+	metrics.ExporterLeader.WithLabelValues(instance).Set(0)
+
+	return db.NewLeaderElection(fmt.Sprintf("exporter-%v", utils.Config.Chain.Config.DepositChainID), interval), instance
+}
+
+func becomeExporterLeader(le *db.LeaderElection, instance string) {
+	leaderElection = le
+	metrics.ExporterLeader.WithLabelValues(instance).Set(1)
+	logger.Infof("instance %v is the exporter leader", instance)
+
+	go func() {
+		err := le.Watch(context.Background())
+		metrics.ExporterLeader.WithLabelValues(instance).Set(0)
+		logger.Fatalf("instance %v lost the exporter leadership: %v", instance, err)
+	}()
+}
+
+// isExporterLeader reports whether this instance may write exported data, always true if leader election is disabled
+func isExporterLeader() bool {
+	return leaderElection == nil || leaderElection.IsLeader()
+}
@@ -0,0 +1,152 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// operationPoolExporter stores a snapshot of the operation pool of the beacon node shortly before every slot, the
+// snapshots show which slashings and voluntary exits a block could have included
+func operationPoolExporter(client rpc.Client) {
+	poolClient, ok := client.(rpc.OperationPoolClient)
+	if !ok {
+		logger.Errorf("block packing exporter enabled but the beacon client does not support the operation pool api")
+		return
+	}
+
+	logger.Infoln("Started operation pool exporter")
+	slotDuration := time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot)
+	for {
+		// take the snapshot two thirds into the slot, the block of the slot has been imported by then and the
+		// block of the next slot has not been proposed yet
+		slot := utils.TimeToSlot(uint64(time.Now().Unix()))
+		snapshotTime := utils.SlotToTime(slot).Add(slotDuration * 2 / 3)
+		if time.Now().After(snapshotTime) {
+			slot++
+			snapshotTime = snapshotTime.Add(slotDuration)
+		}
+		time.Sleep(time.Until(snapshotTime))
+
+		err := exportOperationPool(poolClient, slot+1)
+		if err != nil {
+			logger.Errorf("error exporting operation pool snapshot: %v", err)
+		}
+	}
+}
+
+// exportOperationPool stores the operations of the operation pool that can be included at slot
+func exportOperationPool(client rpc.OperationPoolClient, slot uint64) error {
+	pool, err := client.GetOperationPool()
+	if err != nil {
+		return err
+	}
+
+	snapshot := &types.OperationPoolSnapshot{
+		Slot:              slot,
+		ProposerSlashings: pq.Int64Array{},
+		AttesterSlashings: pq.StringArray{},
+		VoluntaryExits:    pq.Int64Array{},
+	}
+	for _, v := range pool.ProposerSlashings {
+		snapshot.ProposerSlashings = append(snapshot.ProposerSlashings, int64(v))
+	}
+	for _, s := range pool.AttesterSlashings {
+		if s.Attestation1 == nil || s.Attestation2 == nil {
+			continue
+		}
+		key := utils.AttesterSlashingKey(s.Attestation1.AttestingIndices, s.Attestation2.AttestingIndices)
+		if key != "" {
+			snapshot.AttesterSlashings = append(snapshot.AttesterSlashings, key)
+		}
+	}
+	// exits of a future epoch can not be included yet
+	for _, e := range pool.VoluntaryExits {
+		if e.Epoch <= utils.EpochOfSlot(slot) {
+			snapshot.VoluntaryExits = append(snapshot.VoluntaryExits, int64(e.ValidatorIndex))
+		}
+	}
+	return db.SaveOperationPoolSnapshot(snapshot)
+}
+
+func packingExporter(client rpc.Client) {
+	logger.Infoln("Started block packing exporter")
+	for {
+		err := exportBlockPacking(client)
+		if err != nil {
+			logger.Errorf("error exporting block packing: %v", err)
+		}
+		time.Sleep(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot))
+	}
+}
+
+// exportBlockPacking evaluates the packing of the blocks of finalized epochs. The blocks of the following epoch have
+// to be finalized as well as they show which votes were still available.
+func exportBlockPacking(client rpc.Client) error {
+	head, err := client.GetChainHead()
+	if err != nil {
+		return fmt.Errorf("error retrieving chain head: %v", err)
+	}
+	if head.FinalizedEpoch == 0 {
+		return nil
+	}
+
+	epochs, err := db.GetEpochsWithoutPacking(head.FinalizedEpoch-1, 10)
+	if err != nil {
+		return fmt.Errorf("error retrieving epochs without block packing: %v", err)
+	}
+
+	for _, epoch := range epochs {
+		start := time.Now()
+
+		packings, err := evaluateEpochPacking(epoch)
+		if err != nil {
+			return err
+		}
+
+		err = db.SaveBlockPackings(packings)
+		if err != nil {
+			return fmt.Errorf("error saving block packing of epoch %v: %v", epoch, err)
+		}
+
+		err = db.SetEpochPackingExported(epoch)
+		if err != nil {
+			return fmt.Errorf("error marking block packing of epoch %v as exported: %v", epoch, err)
+		}
+
+		// epochs are evaluated from the most recent one, only the snapshots of this epoch may be removed as the older
+		// epochs still to be evaluated need theirs
+		err = db.DeleteOperationPoolSnapshots(epoch*utils.Config.Chain.Config.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.Config.SlotsPerEpoch-1)
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("exported packing of %v blocks for epoch %v in %v", len(packings), epoch, time.Since(start))
+		metrics.TaskDuration.WithLabelValues("export_block_packing").Observe(time.Since(start).Seconds())
+	}
+	return nil
+}
+
+// evaluateEpochPacking computes the packing of the blocks of an epoch using the blocks of the previous epoch to know
+// which votes were already included and the blocks of the following epoch to know which votes were still available
+func evaluateEpochPacking(epoch uint64) ([]*types.BlockPacking, error) {
+	slotsPerEpoch := utils.Config.Chain.Config.SlotsPerEpoch
+	fromSlot := uint64(0)
+	if epoch > 0 {
+		fromSlot = (epoch - 1) * slotsPerEpoch
+	}
+	// the inclusion window of the last slot of the epoch ends within the following epoch
+	toSlot := (epoch+2)*slotsPerEpoch - 1
+
+	blocks, err := db.GetPackingBlocks(fromSlot, toSlot)
+	if err != nil {
+		return nil, err
+	}
+	return utils.EvaluateEpochPacking(blocks, epoch), nil
+}
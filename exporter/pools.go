@@ -0,0 +1,90 @@
+package exporter
+
+import (
+	"context"
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/pools"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+func poolAdaptersExporter() {
+	adapters, err := newPoolAdapters()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if len(adapters) == 0 {
+		logger.Warn("pool adapters exporter enabled but no pool adapter is configured")
+		return
+	}
+
+	for {
+		for _, adapter := range adapters {
+			err := exportPool(adapter)
+			if err != nil {
+				logger.WithError(err).Errorf("error exporting validators of pool %v", adapter.Name())
+			}
+		}
+		time.Sleep(time.Minute * 10)
+	}
+}
+
+// newPoolAdapters creates the adapters of all configured pools
+func newPoolAdapters() ([]pools.PoolAdapter, error) {
+	client, err := ethclient.Dial(utils.Config.Eth1GethEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to eth1 node: %v", err)
+	}
+
+	adapters := []pools.PoolAdapter{}
+	if utils.Config.PoolAdapters.Lido.Enabled {
+		address := utils.Config.PoolAdapters.Lido.NodeOperatorsRegistryAddress
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid lido node operators registry address %v", address)
+		}
+		adapter, err := pools.NewLidoAdapter(client, common.HexToAddress(address))
+		if err != nil {
+			return nil, err
+		}
+		adapters = append(adapters, adapter)
+	}
+	return adapters, nil
+}
+
+// exportPool discovers the operators and new validators of the pool and saves them
+func exportPool(adapter pools.PoolAdapter) error {
+	start := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("exporter_pool_" + adapter.Name()).Observe(time.Since(start).Seconds())
+	}()
+
+	known, err := db.GetPoolOperatorValidatorCounts(adapter.Name())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*30)
+	defer cancel()
+	operators, err := adapter.Operators(ctx, known)
+	if err != nil {
+		return err
+	}
+
+	newValidators := 0
+	for _, operator := range operators {
+		newValidators += len(operator.NewValidators)
+	}
+
+	err = db.SavePoolOperators(operators)
+	if err != nil {
+		return err
+	}
+	logger.WithFields(logrus.Fields{"operators": len(operators), "newValidators": newValidators, "duration": time.Since(start)}).Infof("exported validators of pool %v", adapter.Name())
+	return nil
+}
@@ -1,12 +1,15 @@
 package exporter
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"eth2-exporter/db"
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +28,19 @@ type BidTrace struct {
 	Value                types.WeiString `json:"value"`
 }
 
+// BuilderBidTrace is a bid a relay received from a builder
+type BuilderBidTrace struct {
+	BidTrace
+	NumTx       uint64 `json:"num_tx,string"`
+	TimestampMs int64  `json:"timestamp_ms,string"`
+}
+
+// relays only keep the received bids for a limited time, so the bids are only fetched for the most recent slots
+const maxRelayBidSlots = 64
+
+// the execution blocks are indexed by a separate process, relay blocks are only checked once they are old enough to be indexed
+const relayPaymentCheckDelay = 64
+
 func mevBoostRelaysExporter() {
 	var relays []types.Relay
 	for {
@@ -57,6 +73,13 @@ func singleRelayExport(r types.Relay, wg *sync.WaitGroup) {
 		return
 	}
 	r.Logger.Infof("finished syncing payloads from relay")
+
+	err = exportRelayBids(r)
+	if err != nil {
+		r.Logger.Errorf("failed to export bids for relay: %v", err)
+		return
+	}
+	r.Logger.Infof("finished syncing bids from relay")
 }
 
 func fetchDeliveredPayloads(r types.Relay, offset uint64) ([]BidTrace, error) {
@@ -89,7 +112,7 @@ func fetchDeliveredPayloads(r types.Relay, offset uint64) ([]BidTrace, error) {
 func exportRelayBlocks(r types.Relay) error {
 	// retrieve the oldest tag usage so we know when to stop processing payloads from the head
 	var lastUsage types.RelayBlock
-	err := db.ReaderDb.Get(&lastUsage, `SELECT * FROM relays_blocks WHERE tag_id=$1 ORDER BY block_slot DESC LIMIT 1`, r.ID)
+	err := db.ReaderDb.Get(&lastUsage, `SELECT block_slot FROM relays_blocks WHERE tag_id=$1 ORDER BY block_slot DESC LIMIT 1`, r.ID)
 	if err != nil {
 		r.Logger.Errorf("failed to retrieve last relay block from db, assuming none set: %v", err)
 	}
@@ -102,7 +125,7 @@ func exportRelayBlocks(r types.Relay) error {
 
 	// to make sure we dont have an incomplete table, check if there are any payloads before our first tag usage
 	var firstUsage types.RelayBlock
-	err = db.ReaderDb.Get(&firstUsage, `SELECT * FROM relays_blocks WHERE tag_id=$1 ORDER BY block_slot ASC LIMIT 1`, r.ID)
+	err = db.ReaderDb.Get(&firstUsage, `SELECT block_slot FROM relays_blocks WHERE tag_id=$1 ORDER BY block_slot ASC LIMIT 1`, r.ID)
 	if err != nil {
 		r.Logger.Errorf("failed to retrieve first relay block from db, assuming none set: %v", err)
 	}
@@ -219,3 +242,168 @@ func retrieveAndInsertPayloadsFromRelay(r types.Relay, low_bound uint64, high_bo
 	}
 	return tx.Commit()
 }
+
+func fetchBuilderBids(r types.Relay, slot uint64) ([]BuilderBidTrace, error) {
+	var bids []BuilderBidTrace
+	url := fmt.Sprintf("%s/relay/v1/data/bidtraces/builder_blocks_received?slot=%v", r.Endpoint, slot)
+	r.Logger.Debugf("calling %v", url)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving builder bids: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error retrieving builder bids: unexpected status code %v", resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&bids)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding builder bids: %v", err)
+	}
+	return bids, nil
+}
+
+// exportRelayBids stores the highest bid of every builder for the slots since the last exported bid of the relay
+func exportRelayBids(r types.Relay) error {
+	var headSlot uint64
+	err := db.ReaderDb.Get(&headSlot, `SELECT COALESCE(MAX(slot), 0) FROM blocks`)
+	if err != nil {
+		return fmt.Errorf("error retrieving head slot: %v", err)
+	}
+	var lastSlot uint64
+	err = db.ReaderDb.Get(&lastSlot, `SELECT COALESCE(MAX(slot), 0) FROM relays_bids WHERE tag_id = $1`, r.ID)
+	if err != nil {
+		return fmt.Errorf("error retrieving last bid slot: %v", err)
+	}
+
+	startSlot := lastSlot + 1
+	if headSlot > maxRelayBidSlots && startSlot < headSlot-maxRelayBidSlots {
+		startSlot = headSlot - maxRelayBidSlots
+	}
+
+	for slot := startSlot; slot <= headSlot; slot++ {
+		resp, err := fetchBuilderBids(r, slot)
+		if err != nil {
+			return err
+		}
+
+		highest := make(map[string]*types.RelayBid)
+		for _, b := range resp {
+			// the bids are supplied by the relay, malformed ones must not stop the export
+			builderPubkey, err := hex.DecodeString(strings.TrimPrefix(b.BuilderPubkey, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping bid for slot %v with invalid builder pubkey %v: %v", slot, b.BuilderPubkey, err)
+				continue
+			}
+			blockHash, err := hex.DecodeString(strings.TrimPrefix(b.BlockHash, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping bid for slot %v with invalid block hash %v: %v", slot, b.BlockHash, err)
+				continue
+			}
+			feeRecipient, err := hex.DecodeString(strings.TrimPrefix(b.ProposerFeeRecipient, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping bid for slot %v with invalid proposer fee recipient %v: %v", slot, b.ProposerFeeRecipient, err)
+				continue
+			}
+
+			bid := highest[b.BuilderPubkey]
+			if bid == nil {
+				bid = &types.RelayBid{ID: r.ID, Slot: slot, BuilderPubkey: builderPubkey}
+				highest[b.BuilderPubkey] = bid
+			}
+			bid.BidCount++
+			if bid.BidCount > 1 && b.Value.BigInt().Cmp(bid.Value.BigInt()) <= 0 {
+				continue
+			}
+			bid.BlockHash = blockHash
+			bid.ProposerFeeRecipient = feeRecipient
+			bid.Value = b.Value
+			bid.GasUsed = b.GasUsed
+			bid.NumTx = b.NumTx
+			bid.ReceivedAt = time.Time{}
+			if b.TimestampMs > 0 {
+				bid.ReceivedAt = time.UnixMilli(b.TimestampMs)
+			}
+		}
+
+		bids := make([]*types.RelayBid, 0, len(highest))
+		for _, bid := range highest {
+			bids = append(bids, bid)
+		}
+		err = db.SaveRelayBids(bids)
+		if err != nil {
+			return err
+		}
+		r.Logger.Debugf("saved bids of %v builders for slot %v", len(bids), slot)
+
+		// sleep for a bit to not kill the relay
+		time.Sleep(time.Millisecond * 250)
+	}
+	return nil
+}
+
+// relayPaymentsChecker compares the value promised by the relays with the payment the fee recipient received in the execution block
+func relayPaymentsChecker() {
+	for {
+		err := checkRelayPayments()
+		if err != nil {
+			logger.Errorf("error checking relay payments: %v", err)
+		}
+		time.Sleep(time.Minute * 5)
+	}
+}
+
+func checkRelayPayments() error {
+	if db.BigtableClient == nil {
+		return fmt.Errorf("bigtable is not initialized")
+	}
+
+	var headSlot uint64
+	err := db.ReaderDb.Get(&headSlot, `SELECT COALESCE(MAX(slot), 0) FROM blocks`)
+	if err != nil {
+		return fmt.Errorf("error retrieving head slot: %v", err)
+	}
+	if headSlot < relayPaymentCheckDelay {
+		return nil
+	}
+	toSlot := headSlot - relayPaymentCheckDelay
+	fromSlot := uint64(0)
+	weekInSlots := 7 * 24 * 60 * 60 / utils.Config.Chain.Config.SecondsPerSlot
+	if toSlot > weekInSlots {
+		fromSlot = toSlot - weekInSlots
+	}
+
+	relayBlocks, err := db.GetUncheckedRelayBlocks(fromSlot, toSlot, 1000)
+	if err != nil {
+		return err
+	}
+
+	underpaid := 0
+	for _, rb := range relayBlocks {
+		block, err := db.BigtableClient.GetBlockFromBlocksTable(rb.ExecBlockNumber)
+		if err == db.ErrBlockNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error retrieving execution block %v: %v", rb.ExecBlockNumber, err)
+		}
+		if !bytes.Equal(block.GetHash(), rb.ExecBlockHash) {
+			logger.Warnf("execution block %v of slot %v has hash 0x%x, expected 0x%x", rb.ExecBlockNumber, rb.BlockSlot, block.GetHash(), rb.ExecBlockHash)
+			continue
+		}
+
+		payment := utils.ProposerPayment(block, rb.ProposerFeeRecipient)
+		err = db.SetRelayBlockPayment(rb.BlockRoot, payment)
+		if err != nil {
+			return err
+		}
+		if payment.Cmp(rb.Value.BigInt()) < 0 {
+			underpaid++
+			logger.Warnf("fee recipient 0x%x received %v wei in slot %v, the relay promised %v wei", rb.ProposerFeeRecipient, payment, rb.BlockSlot, rb.Value.BigInt())
+		}
+	}
+	logger.Infof("checked the proposer payment of %v relay blocks, %v were underpaid", len(relayBlocks), underpaid)
+	return nil
+}
@@ -0,0 +1,125 @@
+package exporter
+
+import (
+	"bytes"
+	"eth2-exporter/db"
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"strings"
+)
+
+const (
+	reorgSourceEvent          = "event"
+	reorgSourceHeadComparison = "head_comparison"
+)
+
+// handleChainReorg records a reorg reported by the event stream and re-exports the affected epochs
+func handleChainReorg(client rpc.Client, e *types.ChainReorgEvent) {
+	logger.Warnf("chain reorg of depth %v at slot %v, old head %#x, new head %#x", e.Depth, e.Slot, e.OldHeadBlock, e.NewHeadBlock)
+
+	ancestorSlot := uint64(0)
+	if e.Slot > e.Depth {
+		ancestorSlot = e.Slot - e.Depth
+	}
+
+	reorg := &types.ChainReorg{
+		Slot:         e.Slot,
+		Depth:        e.Depth,
+		Epoch:        e.Epoch,
+		OldHeadBlock: e.OldHeadBlock,
+		NewHeadBlock: e.NewHeadBlock,
+		OldHeadState: e.OldHeadState,
+		NewHeadState: e.NewHeadState,
+		Source:       reorgSourceEvent,
+	}
+
+	var err error
+	reorg.AffectedSlots, err = db.GetOrphanedSlots(e.OldHeadBlock, ancestorSlot)
+	if err != nil {
+		logger.Errorf("error retrieving slots orphaned by the reorg at slot %v: %v", e.Slot, err)
+	}
+
+	err = db.SaveChainReorg(reorg)
+	if err != nil {
+		logger.Errorf("error saving reorg at slot %v: %v", e.Slot, err)
+	}
+
+	reexportEpochs(client, utils.EpochOfSlot(ancestorSlot), utils.EpochOfSlot(e.Slot))
+}
+
+// recordDetectedReorg records a reorg if blocks stored as canonical in the db are no longer part of the chain of the node.
+// As the common ancestor is unknown, the depth of such reorgs is the span of the orphaned slots.
+func recordDetectedReorg(head *types.ChainHead, blocksMap map[string]*types.BlockComparisonContainer) error {
+	var candidates [][]byte
+	for key, block := range blocksMap {
+		if block.Db == nil || strings.HasSuffix(key, "-00") || strings.HasSuffix(key, "-01") {
+			continue
+		}
+		if block.Node == nil || !bytes.Equal(block.Db.BlockRoot, block.Node.BlockRoot) {
+			candidates = append(candidates, block.Db.BlockRoot)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	orphanedSlots, err := db.GetUnorphanedBlockSlots(candidates)
+	if err != nil {
+		return fmt.Errorf("error retrieving unorphaned blocks: %v", err)
+	}
+	if len(orphanedSlots) == 0 {
+		return nil
+	}
+
+	// the reorg might already have been reported by the event stream and is still being re-exported
+	recorded, err := db.ChainReorgRecorded(orphanedSlots)
+	if err != nil {
+		return fmt.Errorf("error checking for recorded reorgs: %v", err)
+	}
+	if recorded {
+		return nil
+	}
+
+	firstSlot := uint64(orphanedSlots[0])
+	lastSlot := uint64(orphanedSlots[len(orphanedSlots)-1])
+
+	var oldHead []byte
+	for _, block := range blocksMap {
+		if block.Db != nil && block.Db.Slot == lastSlot {
+			oldHead = block.Db.BlockRoot
+		}
+	}
+
+	logger.Warnf("detected reorg of slots %v-%v by head comparison", firstSlot, lastSlot)
+	return db.SaveChainReorg(&types.ChainReorg{
+		Slot:          lastSlot,
+		Depth:         lastSlot - firstSlot + 1,
+		Epoch:         utils.EpochOfSlot(lastSlot),
+		OldHeadBlock:  oldHead,
+		NewHeadBlock:  head.HeadBlockRoot,
+		AffectedSlots: orphanedSlots,
+		Source:        reorgSourceHeadComparison,
+	})
+}
+
+// reexportEpochs exports the epochs again and marks the blocks that are no longer part of the chain as orphaned
+func reexportEpochs(client rpc.Client, startEpoch, endEpoch uint64) {
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		err := ExportEpoch(epoch, client)
+		if err != nil {
+			logger.Errorf("error re-exporting epoch %v: %v", epoch, err)
+		}
+	}
+
+	nodeBlocks, err := GetLastBlocks(startEpoch, endEpoch, client)
+	if err != nil {
+		logger.Errorf("error retrieving blocks of reorged epochs %v-%v: %v", startEpoch, endEpoch, err)
+		return
+	}
+	err = MarkOrphanedBlocks(startEpoch, endEpoch, nodeBlocks)
+	if err != nil {
+		logger.Errorf("error marking orphaned blocks of reorged epochs %v-%v: %v", startEpoch, endEpoch, err)
+	}
+}
@@ -0,0 +1,65 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/metrics"
+	"eth2-exporter/rpc"
+	"eth2-exporter/utils"
+	"fmt"
+	"time"
+)
+
+func rewardsExporter(client rpc.Client) {
+	rewardsClient, ok := client.(rpc.RewardsClient)
+	if !ok {
+		logger.Errorf("validator rewards exporter enabled but the beacon client does not support the rewards api")
+		return
+	}
+
+	logger.Infoln("Started validator rewards exporter")
+	for {
+		err := exportValidatorRewards(client, rewardsClient)
+		if err != nil {
+			logger.Errorf("error exporting validator rewards: %v", err)
+		}
+		time.Sleep(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot))
+	}
+}
+
+// exportValidatorRewards exports the rewards of finalized epochs whose rewards have not been exported yet, starting
+// with the most recent epochs so that a backlog of old epochs does not delay the export of new ones
+func exportValidatorRewards(client rpc.Client, rewardsClient rpc.RewardsClient) error {
+	head, err := client.GetChainHead()
+	if err != nil {
+		return fmt.Errorf("error retrieving chain head: %v", err)
+	}
+
+	epochs, err := db.GetEpochsWithoutRewards(head.FinalizedEpoch, 10)
+	if err != nil {
+		return fmt.Errorf("error retrieving epochs without rewards: %v", err)
+	}
+
+	for _, epoch := range epochs {
+		start := time.Now()
+
+		rewards, err := rewardsClient.GetValidatorRewards(epoch)
+		if err != nil {
+			logger.Errorf("error retrieving validator rewards for epoch %v: %v", epoch, err)
+			continue
+		}
+
+		err = db.BigtableClient.SaveValidatorRewards(epoch, rewards)
+		if err != nil {
+			return fmt.Errorf("error exporting validator rewards of epoch %v to bigtable: %v", epoch, err)
+		}
+
+		err = db.SetEpochRewardsExported(epoch)
+		if err != nil {
+			return fmt.Errorf("error marking validator rewards of epoch %v as exported: %v", epoch, err)
+		}
+
+		logger.Infof("exported rewards of %v validators for epoch %v in %v", len(rewards), epoch, time.Since(start))
+		metrics.TaskDuration.WithLabelValues("export_validator_rewards").Observe(time.Since(start).Seconds())
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package exporter
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultSlasherHistoryEpochs = 16
+
+// activeSlasher is set if the slasher is enabled in the indexer config
+var activeSlasher *slasher
+
+// slasherVote is an attestation data seen by the slasher, a vote is shared between all validators that signed the same data
+type slasherVote struct {
+	data               *types.AttestationData
+	signature          []byte
+	inclusionSlot      uint64
+	inclusionBlockRoot []byte
+}
+
+// slasher keeps the votes and proposals of the most recent epochs in memory and detects double votes, surround votes
+// and double proposals. It only sees the messages included in the processed blocks and attester slashing events.
+type slasher struct {
+	mu             sync.Mutex
+	historyEpochs  uint64
+	latestEpoch    uint64
+	votes          map[string]*slasherVote
+	validatorVotes map[uint64][]*slasherVote
+	proposals      map[uint64]map[uint64]*types.SlashingEvidenceProposal
+}
+
+func newSlasher(historyEpochs uint64) *slasher {
+	if historyEpochs == 0 {
+		historyEpochs = defaultSlasherHistoryEpochs
+	}
+	return &slasher{
+		historyEpochs:  historyEpochs,
+		votes:          make(map[string]*slasherVote),
+		validatorVotes: make(map[uint64][]*slasherVote),
+		proposals:      make(map[uint64]map[uint64]*types.SlashingEvidenceProposal),
+	}
+}
+
+// detectSlashings runs the slasher on the blocks and stores the detected offences as potential slashings
+func detectSlashings(blocks map[uint64]map[string]*types.Block) error {
+	if activeSlasher == nil {
+		return nil
+	}
+	slashings := activeSlasher.processBlocks(blocks)
+	if len(slashings) == 0 {
+		return nil
+	}
+	logger.Infof("slasher detected %v potential slashings", len(slashings))
+	return db.SavePotentialSlashings(slashings)
+}
+
+// detectAttesterSlashingEventSlashings runs the slasher on the attestations of an attester slashing received via the event stream
+func detectAttesterSlashingEventSlashings(e *types.AttesterSlashingEvent) error {
+	if activeSlasher == nil {
+		return nil
+	}
+	activeSlasher.mu.Lock()
+	slashings := activeSlasher.processIndexedAttestation(e.Attestation1, 0, nil)
+	slashings = append(slashings, activeSlasher.processIndexedAttestation(e.Attestation2, 0, nil)...)
+	activeSlasher.mu.Unlock()
+	if len(slashings) == 0 {
+		return nil
+	}
+	return db.SavePotentialSlashings(dedupPotentialSlashings(slashings))
+}
+
+func (s *slasher) processBlocks(blocks map[uint64]map[string]*types.Block) []*types.PotentialSlashing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slashings := []*types.PotentialSlashing{}
+	for _, blocksOfSlot := range blocks {
+		for _, block := range blocksOfSlot {
+			slashings = append(slashings, s.processBlock(block)...)
+		}
+	}
+	s.prune()
+	return dedupPotentialSlashings(slashings)
+}
+
+func (s *slasher) processBlock(block *types.Block) []*types.PotentialSlashing {
+	// missed and scheduled slots do not hold a proposal
+	if block.Status == 0 || block.Status == 2 || len(block.BlockRoot) == 0 {
+		return nil
+	}
+
+	slashings := []*types.PotentialSlashing{}
+	if !s.outsideHistory(utils.EpochOfSlot(block.Slot)) {
+		proposal := &types.SlashingEvidenceProposal{
+			Slot:       block.Slot,
+			BlockRoot:  fmt.Sprintf("%#x", block.BlockRoot),
+			ParentRoot: fmt.Sprintf("%#x", block.ParentRoot),
+			StateRoot:  fmt.Sprintf("%#x", block.StateRoot),
+			BodyRoot:   fmt.Sprintf("%#x", block.BodyRoot),
+			Signature:  fmt.Sprintf("%#x", block.Signature),
+		}
+		if s.proposals[block.Slot] == nil {
+			s.proposals[block.Slot] = make(map[uint64]*types.SlashingEvidenceProposal)
+		}
+		other, exists := s.proposals[block.Slot][block.Proposer]
+		if !exists {
+			s.proposals[block.Slot][block.Proposer] = proposal
+		} else if other.BlockRoot != proposal.BlockRoot {
+			slashings = append(slashings, &types.PotentialSlashing{
+				Kind:           types.PotentialSlashingDoubleProposal,
+				ValidatorIndex: block.Proposer,
+				Slot:           block.Slot,
+				Epoch:          utils.EpochOfSlot(block.Slot),
+				Evidence:       types.SlashingEvidence{Proposals: []*types.SlashingEvidenceProposal{other, proposal}},
+				DetectedTs:     time.Now(),
+			})
+		}
+	}
+
+	for _, a := range block.Attestations {
+		attestation := &types.IndexedAttestation{
+			Data:             a.Data,
+			AttestingIndices: a.Attesters,
+			Signature:        a.Signature,
+		}
+		slashings = append(slashings, s.processIndexedAttestation(attestation, block.Slot, block.BlockRoot)...)
+	}
+	return slashings
+}
+
+// processIndexedAttestation compares the attestation with the votes its attesters already cast, inclusionBlockRoot is
+// nil if the attestation has not been seen in a block
+func (s *slasher) processIndexedAttestation(a *types.IndexedAttestation, inclusionSlot uint64, inclusionBlockRoot []byte) []*types.PotentialSlashing {
+	if a == nil || a.Data == nil || a.Data.Source == nil || a.Data.Target == nil || s.outsideHistory(a.Data.Target.Epoch) {
+		return nil
+	}
+
+	key := attestationDataKey(a.Data)
+	vote, exists := s.votes[key]
+	if !exists {
+		vote = &slasherVote{
+			data:               a.Data,
+			signature:          a.Signature,
+			inclusionSlot:      inclusionSlot,
+			inclusionBlockRoot: inclusionBlockRoot,
+		}
+		s.votes[key] = vote
+	} else if vote.inclusionBlockRoot == nil && inclusionBlockRoot != nil {
+		vote.inclusionSlot = inclusionSlot
+		vote.inclusionBlockRoot = inclusionBlockRoot
+	}
+	if a.Data.Target.Epoch > s.latestEpoch {
+		s.latestEpoch = a.Data.Target.Epoch
+	}
+
+	slashings := []*types.PotentialSlashing{}
+	for _, validator := range a.AttestingIndices {
+		seen := false
+		for _, other := range s.validatorVotes[validator] {
+			if other == vote {
+				seen = true
+				continue
+			}
+			kind := ""
+			if other.data.Target.Epoch == vote.data.Target.Epoch {
+				kind = types.PotentialSlashingDoubleVote
+			} else if surrounds(vote.data, other.data) || surrounds(other.data, vote.data) {
+				kind = types.PotentialSlashingSurroundVote
+			}
+			if kind == "" {
+				continue
+			}
+			latest := vote
+			if other.data.Target.Epoch > vote.data.Target.Epoch {
+				latest = other
+			}
+			slashings = append(slashings, &types.PotentialSlashing{
+				Kind:           kind,
+				ValidatorIndex: validator,
+				Slot:           latest.data.Slot,
+				Epoch:          latest.data.Target.Epoch,
+				Evidence:       types.SlashingEvidence{Attestations: []*types.SlashingEvidenceAttestation{other.evidence(), vote.evidence()}},
+				DetectedTs:     time.Now(),
+			})
+		}
+		if !seen {
+			s.validatorVotes[validator] = append(s.validatorVotes[validator], vote)
+		}
+	}
+	return slashings
+}
+
+// outsideHistory returns true if messages of the epoch are too old to be kept by the slasher
+func (s *slasher) outsideHistory(epoch uint64) bool {
+	return s.latestEpoch > s.historyEpochs && epoch < s.latestEpoch-s.historyEpochs
+}
+
+// prune removes the votes and proposals that are older than the history of the slasher
+func (s *slasher) prune() {
+	if s.latestEpoch <= s.historyEpochs {
+		return
+	}
+	minEpoch := s.latestEpoch - s.historyEpochs
+
+	for key, vote := range s.votes {
+		if vote.data.Target.Epoch < minEpoch {
+			delete(s.votes, key)
+		}
+	}
+	for validator, votes := range s.validatorVotes {
+		kept := votes[:0]
+		for _, vote := range votes {
+			if vote.data.Target.Epoch >= minEpoch {
+				kept = append(kept, vote)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.validatorVotes, validator)
+		} else {
+			s.validatorVotes[validator] = kept
+		}
+	}
+	for slot := range s.proposals {
+		if utils.EpochOfSlot(slot) < minEpoch {
+			delete(s.proposals, slot)
+		}
+	}
+}
+
+func (v *slasherVote) evidence() *types.SlashingEvidenceAttestation {
+	e := &types.SlashingEvidenceAttestation{
+		Slot:            v.data.Slot,
+		CommitteeIndex:  v.data.CommitteeIndex,
+		BeaconBlockRoot: fmt.Sprintf("%#x", v.data.BeaconBlockRoot),
+		SourceEpoch:     v.data.Source.Epoch,
+		SourceRoot:      fmt.Sprintf("%#x", v.data.Source.Root),
+		TargetEpoch:     v.data.Target.Epoch,
+		TargetRoot:      fmt.Sprintf("%#x", v.data.Target.Root),
+		Signature:       fmt.Sprintf("%#x", v.signature),
+	}
+	if v.inclusionBlockRoot != nil {
+		e.InclusionSlot = v.inclusionSlot
+		e.InclusionBlockRoot = fmt.Sprintf("%#x", v.inclusionBlockRoot)
+	}
+	return e
+}
+
+// surrounds returns true if the vote a surrounds the vote b
+func surrounds(a, b *types.AttestationData) bool {
+	return a.Source.Epoch < b.Source.Epoch && b.Target.Epoch < a.Target.Epoch
+}
+
+func attestationDataKey(d *types.AttestationData) string {
+	return fmt.Sprintf("%d:%d:%x:%d:%x:%d:%x", d.Slot, d.CommitteeIndex, d.BeaconBlockRoot, d.Source.Epoch, d.Source.Root, d.Target.Epoch, d.Target.Root)
+}
+
+// dedupPotentialSlashings keeps the first potential slashing of every kind, validator and epoch
+func dedupPotentialSlashings(slashings []*types.PotentialSlashing) []*types.PotentialSlashing {
+	seen := make(map[string]bool, len(slashings))
+	deduped := make([]*types.PotentialSlashing, 0, len(slashings))
+	for _, s := range slashings {
+		key := fmt.Sprintf("%v:%v:%v", s.Kind, s.ValidatorIndex, s.Epoch)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, s)
+	}
+	return deduped
+}
@@ -6,10 +6,14 @@ import (
 	"eth2-exporter/db"
 	"eth2-exporter/utils"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
@@ -139,6 +143,11 @@ func saveSSV(res *SSVExporterResponse) error {
 		}
 	}
 
+	err = saveSSVOperators(tx, res)
+	if err != nil {
+		return err
+	}
+
 	// currently the ssv-exporter also exports publickeys that are not actually part of the network
 	for {
 		res, err := tx.Exec(`delete from validator_tags where publickey in (select publickey from validator_tags where publickey not in (select pubkey from validators) limit 1000)`)
@@ -162,3 +171,81 @@ func saveSSV(res *SSVExporterResponse) error {
 
 	return nil
 }
+
+// saveSSVOperators replaces the operators of all validators and updates the operator list
+func saveSSVOperators(tx *sqlx.Tx, res *SSVExporterResponse) error {
+	_, err := tx.Exec(`delete from ssv_validator_operators`)
+	if err != nil {
+		return err
+	}
+
+	operators := make(map[int]string)
+	pubkeys := make(pq.ByteaArray, 0, len(res.Data)*4)
+	operatorIds := make(pq.Int64Array, 0, len(res.Data)*4)
+	clusters := make(pq.StringArray, 0, len(res.Data)*4)
+	for _, d := range res.Data {
+		pubkey, err := hex.DecodeString(strings.Replace(d.Publickey, "0x", "", -1))
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int, 0, len(d.Operators))
+		for _, operator := range d.Operators {
+			ids = append(ids, operator.Nodeid)
+			operators[operator.Nodeid] = operator.Publickey
+		}
+		sort.Ints(ids)
+		clusterIds := make([]string, len(ids))
+		for i, id := range ids {
+			clusterIds[i] = strconv.Itoa(id)
+		}
+		cluster := strings.Join(clusterIds, "-")
+
+		for _, id := range ids {
+			pubkeys = append(pubkeys, pubkey)
+			operatorIds = append(operatorIds, int64(id))
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	batchSize := 5000
+	for start := 0; start < len(pubkeys); start += batchSize {
+		end := start + batchSize
+		if len(pubkeys) < end {
+			end = len(pubkeys)
+		}
+		_, err := tx.Exec(`
+			insert into ssv_validator_operators (publickey, operator_id, cluster)
+			select * from unnest($1::bytea[], $2::int[], $3::text[])
+			on conflict (publickey, operator_id) do nothing`, pubkeys[start:end], operatorIds[start:end], clusters[start:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	for id, publickey := range operators {
+		_, err := tx.Exec(`
+			insert into ssv_operators (id, publickey, updated_at) values ($1, $2, now())
+			on conflict (id) do update set publickey = excluded.publickey, updated_at = excluded.updated_at`, id, publickey)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		update ssv_operators set validator_count = coalesce((select count(*) from ssv_validator_operators where operator_id = ssv_operators.id), 0)`)
+	return err
+}
+
+func ssvOperatorStatsUpdater() {
+	for {
+		start := time.Now()
+		err := db.UpdateSSVOperatorStats()
+		if err != nil {
+			logger.WithError(err).Error("error updating ssv operator stats")
+		} else {
+			logger.WithField("duration", time.Since(start)).Info("updated ssv operator stats")
+		}
+		time.Sleep(time.Hour)
+	}
+}
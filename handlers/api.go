@@ -83,6 +83,17 @@ func ApiHealthz(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	stuckEpochs, err := db.GetStuckEpochCount()
+	if err != nil {
+		logger.Errorf("could not retrieve the number of stuck epochs: %v", err)
+		http.Error(w, "Internal server error: could not retrieve the number of stuck epochs", http.StatusServiceUnavailable)
+		return
+	}
+	if stuckEpochs > 0 {
+		http.Error(w, fmt.Sprintf("Internal server error: export of %v epochs failed more than %v times (check epoch_export_failures)", stuckEpochs, db.MaxEpochExportAttempts), http.StatusServiceUnavailable)
+		return
+	}
+
 	// check latest eth1 indexed block
 	numberBlocksTable, err := db.BigtableClient.GetLastBlockInBlocksTable()
 	if err != nil {
@@ -278,7 +289,7 @@ func ApiEpochBlocks(w http.ResponseWriter, r *http.Request) {
 // ApiBlock godoc
 // @Summary Get block
 // @Tags Block
-// @Description Returns a block by its slot or root hash
+// @Description Returns a block by its slot or root hash. arrival_delay_ms and head_delay_ms hold the time in milliseconds after the slot start at which the block was received and became head, they are null if the arrival was not observed
 // @Produce  json
 // @Param  slotOrHash path string true "Block slot or root hash or the string latest"
 // @Success 200 {object} string
@@ -306,7 +317,11 @@ func ApiBlock(w http.ResponseWriter, r *http.Request) {
 		blockSlot = int64(services.LatestSlot())
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT * FROM blocks WHERE slot = $1 OR blockroot = $2", blockSlot, blockRootHash)
+	rows, err := db.ReaderDb.Query(`
+		SELECT blocks.*, blocks_arrival.block_delay_ms AS arrival_delay_ms, blocks_arrival.head_delay_ms
+		FROM blocks
+		LEFT JOIN blocks_arrival ON blocks_arrival.slot = blocks.slot AND blocks_arrival.blockroot = blocks.blockroot
+		WHERE blocks.slot = $1 OR blocks.blockroot = $2`, blockSlot, blockRootHash)
 	if err != nil {
 		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -492,6 +507,42 @@ func ApiBlockVoluntaryExits(w http.ResponseWriter, r *http.Request) {
 	returnQueryResults(rows, w, r)
 }
 
+// ApiBlockWithdrawals godoc
+// @Summary Get the withdrawals included in a specific block
+// @Tags Block
+// @Description Returns the withdrawals included in a specific block
+// @Produce  json
+// @Param  slot path string true "Block slot"
+// @Success 200 {object} string
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/block/{slot}/withdrawals [get]
+func ApiBlockWithdrawals(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	slot, err := strconv.ParseInt(vars["slot"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid block slot provided")
+		return
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT blocks_withdrawals.block_slot, blocks_withdrawals.block_root, blocks_withdrawals.withdrawalindex, blocks_withdrawals.validatorindex, blocks_withdrawals.address, blocks_withdrawals.amount
+		FROM blocks_withdrawals
+		INNER JOIN blocks ON blocks_withdrawals.block_root = blocks.blockroot AND blocks.status = '1'
+		WHERE blocks_withdrawals.block_slot = $1
+		ORDER BY blocks_withdrawals.withdrawalindex`, slot)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResultsAsArray(rows, w, r)
+}
+
 // ApiBlockVoluntaryExits godoc
 // @Summary Get the sync-committee for a sync-period
 // @Tags SyncCommittee
@@ -1012,6 +1063,69 @@ func ApiValidatorBalanceHistory(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ApiValidatorRewards godoc
+// @Summary Get the reward breakdown of the last 100 epochs for up to 100 validators
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ValidatorEpochRewards}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/rewards [get]
+func ApiValidatorRewards(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParam(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	history, err := db.BigtableClient.GetValidatorRewardsHistory(queryIndices, services.LatestEpoch(), 100)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	type responseType struct {
+		*types.ValidatorEpochRewards
+		Total int64 `json:"total"`
+	}
+	responseData := make([]*responseType, 0, len(history)*100)
+
+	for _, rewards := range history {
+		for _, reward := range rewards {
+			responseData = append(responseData, &responseType{
+				ValidatorEpochRewards: reward,
+				Total:                 reward.Total(),
+			})
+		}
+	}
+
+	sort.Slice(responseData, func(i, j int) bool {
+		if responseData[i].Epoch != responseData[j].Epoch {
+			return responseData[i].Epoch > responseData[j].Epoch
+		}
+		return responseData[i].ValidatorIndex < responseData[j].ValidatorIndex
+	})
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = responseData
+
+	err = j.Encode(response)
+
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
 // ApiValidatorPerformance godoc
 // @Summary Get the current consensus reward performance of up to 100 validators
 // @Tags Validator
@@ -1239,8 +1353,117 @@ func ApiValidatorDeposits(w http.ResponseWriter, r *http.Request) {
 	returnQueryResultsAsArray(rows, w, r)
 }
 
+// ApiValidatorWithdrawals godoc
+// @Summary Get the withdrawals of up to 100 validators
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} string
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/withdrawals [get]
+func ApiValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParam(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT blocks_withdrawals.block_slot, blocks_withdrawals.block_root, blocks_withdrawals.withdrawalindex, blocks_withdrawals.validatorindex, blocks_withdrawals.address, blocks_withdrawals.amount
+		FROM blocks_withdrawals
+		INNER JOIN blocks ON blocks_withdrawals.block_root = blocks.blockroot AND blocks.status = '1'
+		WHERE blocks_withdrawals.validatorindex = ANY($1)
+		ORDER BY blocks_withdrawals.withdrawalindex DESC
+		LIMIT 100`, pq.Array(queryIndices))
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResultsAsArray(rows, w, r)
+}
+
+// ApiValidatorBlsChange godoc
+// @Summary Get the bls to execution changes of up to 100 validators
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} string
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/blschange [get]
+func ApiValidatorBlsChange(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParam(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT blocks_bls_change.block_slot AS slot, blocks_bls_change.block_root, blocks_bls_change.validatorindex, blocks_bls_change.pubkey AS bls_pubkey, blocks_bls_change.address, blocks_bls_change.signature
+		FROM blocks_bls_change
+		INNER JOIN blocks ON blocks_bls_change.block_root = blocks.blockroot AND blocks.status = '1'
+		WHERE blocks_bls_change.validatorindex = ANY($1)
+		ORDER BY blocks_bls_change.block_slot DESC
+		LIMIT 100`, pq.Array(queryIndices))
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResultsAsArray(rows, w, r)
+}
+
+// ApiValidatorQueueEstimate godoc
+// @Summary Get the projected activation and exit epochs of up to 100 validators, calculated from their position in the activation queue, the length of the exit queue and the current churn limit
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ValidatorQueueEstimate}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/queue [get]
+func ApiValidatorQueueEstimate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParam(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	estimates := make([]*types.ValidatorQueueEstimate, 0, len(queryIndices))
+	for _, index := range queryIndices {
+		estimate, err := services.GetValidatorQueueEstimate(index)
+		if err != nil {
+			logger.Errorf("error estimating the queue of validator %v: %v", index, err)
+			sendErrorResponse(w, r.URL.String(), "could not estimate validator queue")
+			return
+		}
+		estimates = append(estimates, estimate)
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{estimates})
+}
+
 // ApiValidatorAttestations godoc
 // @Summary Get all attestations during the last 10 epochs for up to 100 validators
+// @Description correct_source, correct_target and correct_head tell whether the votes of the attestation matched the canonical chain, they are null if the votes have not been checked. missed_reason holds the likely cause of a missed or too late included attestation: offline, block_missing, block_late, included_too_late, wrong_head_guess or aggregate_not_picked_up_guess. The reasons ending in _guess are derived from the votes of the rest of the network as the vote of a missed attestation is unknown
 // @Tags Validator
 // @Produce  json
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
@@ -1267,20 +1490,31 @@ func ApiValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = db.ClassifyMissedAttestations(history)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
 	type responseType struct {
 		AttesterSlot   uint64 `json:"attesterslot"`
 		CommitteeIndex uint64 `json:"committeeindex"`
 		Epoch          uint64 `json:"epoch"`
 		InclusionSlot  uint64 `json:"inclusionslot"`
+		InclusionDelay *int64 `json:"inclusion_delay"`
 		Status         uint64 `json:"status"`
 		ValidatorIndex uint64 `json:"validatorindex"`
 		Week           uint64 `json:"week"`
+		CorrectSource  *bool  `json:"correct_source"`
+		CorrectTarget  *bool  `json:"correct_target"`
+		CorrectHead    *bool  `json:"correct_head"`
+		MissedReason   string `json:"missed_reason,omitempty"`
 	}
 	responseData := make([]*responseType, 0, len(history)*101)
 
 	for validatorIndex, balances := range history {
 		for _, attestation := range balances {
-			responseData = append(responseData, &responseType{
+			response := &responseType{
 				AttesterSlot:   attestation.AttesterSlot,
 				CommitteeIndex: 0,
 				Epoch:          attestation.Epoch,
@@ -1288,7 +1522,18 @@ func ApiValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 				Status:         attestation.Status,
 				ValidatorIndex: validatorIndex,
 				Week:           attestation.Epoch / 1575,
-			})
+				MissedReason:   attestation.MissedReason,
+			}
+			if attestation.InclusionSlot > 0 {
+				delay := int64(attestation.InclusionSlot) - int64(attestation.AttesterSlot)
+				response.InclusionDelay = &delay
+			}
+			if attestation.Votes.Checked {
+				response.CorrectSource = &attestation.Votes.Source
+				response.CorrectTarget = &attestation.Votes.Target
+				response.CorrectHead = &attestation.Votes.Head
+			}
+			responseData = append(responseData, response)
 		}
 	}
 
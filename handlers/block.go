@@ -39,6 +39,8 @@ func Block(w http.ResponseWriter, r *http.Request) {
 		"block/attesterSlashing.html",
 		"block/proposerSlashing.html",
 		"block/exits.html",
+		"block/withdrawals.html",
+		"block/blobs.html",
 		"block/overview.html",
 		"block/execTransactions.html",
 	)
@@ -192,6 +194,10 @@ func GetSlotPageData(blockSlot uint64) (*types.BlockPageData, error) {
 			blocks.attestationscount,
 			blocks.depositscount,
 			blocks.voluntaryexitscount,
+			blocks.withdrawalcount,
+			blocks.blobs_count,
+			blocks.exec_blob_gas_used,
+			blocks.exec_excess_blob_gas,
 			blocks.proposer,
 			blocks.status,
 			exec_block_number,
@@ -311,6 +317,21 @@ func GetSlotPageData(blockSlot uint64) (*types.BlockPageData, error) {
 		return nil, fmt.Errorf("error retrieving block deposit data: %v", err)
 	}
 
+	err = db.ReaderDb.Select(&blockPageData.Withdrawals, "SELECT withdrawalindex, validatorindex, address, amount FROM blocks_withdrawals WHERE block_slot = $1 AND block_root = $2 ORDER BY withdrawalindex", blockPageData.Slot, blockPageData.BlockRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block withdrawals data: %v", err)
+	}
+
+	if blockPageData.BlobsCount > 0 {
+		err = db.ReaderDb.Select(&blockPageData.BlobSidecars, "SELECT index, kzg_commitment, kzg_proof, blob_versioned_hash FROM blocks_blob_sidecars WHERE block_root = $1 ORDER BY index", blockPageData.BlockRoot)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving block blob sidecars data: %v", err)
+		}
+	}
+	if blockPageData.ExecExcessBlobGas.Valid {
+		blockPageData.ExecBlobGasPrice = utils.BlobBaseFee(uint64(blockPageData.ExecExcessBlobGas.Int64))
+	}
+
 	err = db.ReaderDb.Select(&blockPageData.AttesterSlashings, `
 		SELECT
 			block_slot,
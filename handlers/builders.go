@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/services"
+	"eth2-exporter/templates"
+	"eth2-exporter/types"
+	"net/http"
+	"strconv"
+)
+
+// Builders will return the builder leaderboard page using a go template
+func Builders(w http.ResponseWriter, r *http.Request) {
+	var buildersTemplate = templates.GetTemplate("layout.html", "builders.html")
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "services", "/builders", "Builder Leaderboard")
+
+	days := uint64(7)
+	if r.URL.Query().Get("days") != "" {
+		d, err := parseBuilderLeaderboardPeriod(r.URL.Query().Get("days"))
+		if err != nil {
+			http.Error(w, "Invalid period", http.StatusBadRequest)
+			return
+		}
+		days = d
+	}
+
+	builders := services.LatestBuilderLeaderboard(days)
+	if builders == nil {
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	pageData := &types.BuildersPageData{
+		Days:     days,
+		Periods:  services.BuilderLeaderboardPeriods,
+		Builders: builders,
+	}
+	for _, b := range builders {
+		pageData.TotalBlocks += b.BlockCount
+	}
+	data.Data = pageData
+
+	err := buildersTemplate.ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// ApiBuilders godoc
+// @Summary Get the block share, average block value and used relays of every builder within a period
+// @Tags Relays
+// @Produce  json
+// @Param  days query int false "Period in days, one of 1, 7, 31 or 180 (default 7)"
+// @Success 200 {object} types.ApiResponse{data=[]types.BuilderLeaderboardEntry}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/builders [get]
+func ApiBuilders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	days := uint64(7)
+	if r.URL.Query().Get("days") != "" {
+		d, err := parseBuilderLeaderboardPeriod(r.URL.Query().Get("days"))
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid period provided")
+			return
+		}
+		days = d
+	}
+
+	builders := services.LatestBuilderLeaderboard(days)
+	if builders == nil {
+		sendErrorResponse(w, r.URL.String(), "builder leaderboard is not available")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{builders})
+}
+
+func parseBuilderLeaderboardPeriod(s string) (uint64, error) {
+	days, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range services.BuilderLeaderboardPeriods {
+		if p == days {
+			return days, nil
+		}
+	}
+	return 0, strconv.ErrRange
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ApiProposerDuties godoc
+// @Summary Get the block proposers scheduled for the current or next epoch
+// @Description The proposers of the next epoch are not final, they depend on the last block of the current epoch and can change until it has been proposed
+// @Tags Epoch
+// @Produce  json
+// @Param  epoch path string true "Current or next epoch number"
+// @Success 200 {object} types.ApiResponse{data=[]types.ProposerDuty}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/duties/proposer/{epoch} [get]
+func ApiProposerDuties(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	epoch, err := strconv.ParseUint(vars["epoch"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid epoch provided")
+		return
+	}
+
+	currentEpoch := dutiesCurrentEpoch()
+	if epoch != currentEpoch && epoch != currentEpoch+1 {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("duties are only available for the current epoch %v and the next epoch %v", currentEpoch, currentEpoch+1))
+		return
+	}
+
+	duties, err := db.GetProposerDuties(epoch, currentEpoch)
+	if err != nil {
+		logger.Errorf("error retrieving proposer duties of epoch %v: %v", epoch, err)
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{duties})
+}
+
+// ApiValidatorDuties godoc
+// @Summary Get the upcoming block proposals and attestations of up to 100 validators in the current and next epoch and their current and next sync committee periods
+// @Description Proposals of the next epoch are not final, they depend on the last block of the current epoch and can change until it has been proposed
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=types.ValidatorDuties}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/duties [get]
+func ApiValidatorDuties(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParam(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	duties, err := db.GetValidatorDuties(queryIndices, dutiesCurrentEpoch())
+	if err != nil {
+		logger.Errorf("error retrieving duties of validators %v: %v", queryIndices, err)
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{duties})
+}
+
+// DashboardDataDuties returns the upcoming duties of the dashboard validators
+func DashboardDataDuties(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	validatorLimit := getUserPremium(r).MaxValidators
+	filterArr, err := parseValidatorsFromQueryString(q.Get("validators"), validatorLimit)
+	if err != nil {
+		http.Error(w, "Invalid query", 400)
+		return
+	}
+
+	duties, err := db.GetValidatorDuties(filterArr, dutiesCurrentEpoch())
+	if err != nil {
+		logger.Errorf("error retrieving duties of validators %v: %v", filterArr, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(duties)
+	if err != nil {
+		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// dutiesCurrentEpoch returns the epoch of the wall clock, the duties exporter stores the assignments of this and the
+// next epoch
+func dutiesCurrentEpoch() uint64 {
+	epoch := utils.TimeToEpoch(time.Now())
+	if epoch < 0 {
+		return 0
+	}
+	return uint64(epoch)
+}
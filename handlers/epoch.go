@@ -8,6 +8,7 @@ import (
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
+	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
@@ -66,7 +67,9 @@ func Epoch(w http.ResponseWriter, r *http.Request) {
 			finalized,
 			eligibleether,
 			globalparticipationrate,
-			votedether
+			votedether,
+			blobscount,
+			blob_gas_used
 		FROM epochs 
 		WHERE epoch = $1`, epoch)
 	if err != nil {
@@ -160,6 +163,22 @@ func Epoch(w http.ResponseWriter, r *http.Request) {
 	}
 	epochPageData.SyncParticipationRate /= float64(epochPageData.ProposedCount)
 
+	var excessBlobGas []uint64
+	err = db.ReaderDb.Select(&excessBlobGas, "SELECT exec_excess_blob_gas FROM blocks WHERE epoch = $1 AND status = '1' AND exec_excess_blob_gas IS NOT NULL", epoch)
+	if err != nil {
+		logger.Errorf("error retrieving excess blob gas of epoch %v: %v", epoch, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(excessBlobGas) > 0 {
+		// average price per unit of blob gas of the proposed blocks
+		blobGasPriceSum := new(big.Int)
+		for _, excess := range excessBlobGas {
+			blobGasPriceSum.Add(blobGasPriceSum, utils.BlobBaseFee(excess))
+		}
+		epochPageData.BlobGasPrice = blobGasPriceSum.Div(blobGasPriceSum, big.NewInt(int64(len(excessBlobGas))))
+	}
+
 	epochPageData.Ts = utils.EpochToTime(epochPageData.Epoch)
 
 	err = db.ReaderDb.Get(&epochPageData.NextEpoch, "SELECT epoch FROM epochs WHERE epoch > $1 ORDER BY epoch LIMIT 1", epochPageData.Epoch)
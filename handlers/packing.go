@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/services"
+	"eth2-exporter/templates"
+	"eth2-exporter/types"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// BlockPacking will return the block packing leaderboard page using a go template
+func BlockPacking(w http.ResponseWriter, r *http.Request) {
+	var packingTemplate = templates.GetTemplate("layout.html", "validators_packing.html")
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "validators", "/validators/packing", "Block Packing Leaderboard")
+
+	days := uint64(31)
+	if r.URL.Query().Get("days") != "" {
+		d, err := parsePackingLeaderboardPeriod(r.URL.Query().Get("days"))
+		if err != nil {
+			http.Error(w, "Invalid period", http.StatusBadRequest)
+			return
+		}
+		days = d
+	}
+
+	proposers := services.LatestPackingLeaderboard(days, false)
+	pools := services.LatestPackingLeaderboard(days, true)
+	if proposers == nil || pools == nil {
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	data.Data = &types.PackingPageData{
+		Days:      days,
+		Periods:   services.PackingLeaderboardPeriods,
+		Proposers: proposers,
+		Pools:     pools,
+	}
+
+	err := packingTemplate.ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// ApiPackingLeaderboard godoc
+// @Summary Get the proposers or staking pools with the best average block packing score within a period
+// @Description The packing score rates a block from 0 to 100 by the share of available votes it included, its redundant aggregates, its sync aggregate participation and the pending slashings and exits it did not include, blocks without an observed operation pool are scored without the operations. Proposers need at least 3 and staking pools at least 50 evaluated blocks within the period to be ranked
+// @Tags Validator
+// @Produce  json
+// @Param  days query int false "Period in days, one of 7, 31 or 180 (default 31)"
+// @Param  by query string false "Group the blocks by proposer or pool (default proposer)"
+// @Success 200 {object} types.ApiResponse{data=[]types.PackingLeaderboardEntry}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/packing/leaderboard [get]
+func ApiPackingLeaderboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	days := uint64(31)
+	if q.Get("days") != "" {
+		d, err := parsePackingLeaderboardPeriod(q.Get("days"))
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid period provided")
+			return
+		}
+		days = d
+	}
+	byPool := false
+	switch q.Get("by") {
+	case "", "proposer":
+	case "pool":
+		byPool = true
+	default:
+		sendErrorResponse(w, r.URL.String(), "invalid grouping provided, must be proposer or pool")
+		return
+	}
+
+	entries := services.LatestPackingLeaderboard(days, byPool)
+	if entries == nil {
+		sendErrorResponse(w, r.URL.String(), "packing leaderboard is not available")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{entries})
+}
+
+// ApiValidatorBlockPacking godoc
+// @Summary Get the packing of the most recent blocks proposed by up to 100 validators
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.BlockPacking}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/packing [get]
+func ApiValidatorBlockPacking(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParam(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	packings, err := db.GetValidatorBlockPackings(queryIndices, 100)
+	if err != nil {
+		logger.Errorf("error retrieving block packing of validators %v: %v", queryIndices, err)
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{packings})
+}
+
+func parsePackingLeaderboardPeriod(s string) (uint64, error) {
+	days, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range services.PackingLeaderboardPeriods {
+		if p == days {
+			return days, nil
+		}
+	}
+	return 0, strconv.ErrRange
+}
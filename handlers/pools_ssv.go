@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/templates"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// PoolsSSV returns the ssv pool page with the operator leaderboard using a go template
+func PoolsSSV(w http.ResponseWriter, r *http.Request) {
+	var poolsSSVTemplate = templates.GetTemplate("layout.html", "pools_ssv.html")
+
+	w.Header().Set("Content-Type", "text/html")
+	data := InitPageData(w, r, "pools/ssv", "/pools/ssv", "SSV Network")
+
+	summary, err := db.GetSSVSummary()
+	if err != nil {
+		logger.Errorf("error retrieving ssv summary: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	data.Data = summary
+
+	err = poolsSSVTemplate.ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// PoolsSSVDataOperators returns the ssv operators ranked by the performance of their validators as json
+func PoolsSSVDataOperators(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	currency := GetCurrency(r)
+	q := r.URL.Query()
+	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables data parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables start parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables length parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	if length > 100 {
+		length = 100
+	}
+
+	orderColumn := q.Get("order[0][column]")
+	orderByMap := map[string]string{
+		"0": "ssv_operators.id",
+		"2": "validators",
+		"3": "active_validators",
+		"4": "attestation_participation",
+		"5": "proposed_blocks",
+		"6": "missed_blocks",
+		"7": "performance7d",
+	}
+	orderBy, exists := orderByMap[orderColumn]
+	if !exists {
+		orderBy = "attestation_participation"
+	}
+
+	operators, err := db.GetSSVOperators(orderBy, q.Get("order[0][dir]"), length, start)
+	if err != nil {
+		logger.Errorf("error retrieving ssv operators: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	totalCount := uint64(0)
+	if len(operators) > 0 {
+		totalCount = operators[0].TotalCount
+	}
+
+	tableData := make([][]interface{}, 0, len(operators))
+	for _, operator := range operators {
+		// the operator public key is reported by the ssv exporter and has to be escaped
+		publicKey := template.HTMLEscapeString(operator.PublicKey)
+		shortPublicKey := publicKey
+		if len(operator.PublicKey) > 16 {
+			shortPublicKey = template.HTMLEscapeString(operator.PublicKey[:16]) + "…"
+		}
+		tableData = append(tableData, []interface{}{
+			operator.ID,
+			fmt.Sprintf(`<span class="text-monospace" data-toggle="tooltip" title="%v">%v</span>`, publicKey, shortPublicKey),
+			operator.Validators,
+			operator.ActiveValidators,
+			fmt.Sprintf("%.2f%%", operator.AttestationParticipation*100),
+			operator.ProposedBlocks,
+			operator.MissedBlocks,
+			utils.FormatIncome(operator.Performance7d, currency),
+		})
+	}
+
+	data := &types.DataTableResponse{
+		Draw:            draw,
+		RecordsTotal:    totalCount,
+		RecordsFiltered: totalCount,
+		Data:            tableData,
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// ApiSSVOperators godoc
+// @Summary Get the ssv operators with the aggregated performance of their validators over the last 7 days
+// @Tags SSV
+// @Produce  json
+// @Param  limit query int false "Limit the number of results (maximum 100)"
+// @Param  offset query int false "Offset the results"
+// @Success 200 {object} types.ApiResponse{data=[]types.SSVOperator}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/ssv/operators [get]
+func ApiSSVOperators(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+
+	limit := uint64(100)
+	if q.Get("limit") != "" {
+		l, err := strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid limit provided")
+			return
+		}
+		if l < limit {
+			limit = l
+		}
+	}
+	offset := uint64(0)
+	if q.Get("offset") != "" {
+		o, err := strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid offset provided")
+			return
+		}
+		offset = o
+	}
+
+	operators, err := db.GetSSVOperators("ssv_operators.id", "asc", limit, offset)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{operators})
+}
+
+// ApiSSVOperator godoc
+// @Summary Get an ssv operator with the aggregated performance and the indices of its validators
+// @Tags SSV
+// @Produce  json
+// @Param  id path int true "Operator id"
+// @Success 200 {object} types.ApiResponse{data=types.SSVOperator}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/ssv/operator/{id} [get]
+func ApiSSVOperator(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid operator id provided")
+		return
+	}
+
+	operator, err := db.GetSSVOperator(id)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if operator == nil {
+		sendErrorResponse(w, r.URL.String(), "operator not found")
+		return
+	}
+
+	validators, err := db.GetSSVOperatorValidators(id)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	response := struct {
+		*types.SSVOperator
+		ValidatorIndices []uint64 `json:"validator_indices"`
+	}{operator, validators}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
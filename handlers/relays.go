@@ -1,9 +1,15 @@
 package handlers
 
 import (
+	"encoding/hex"
+	"eth2-exporter/db"
 	"eth2-exporter/services"
 	"eth2-exporter/templates"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
 )
 
 func Relays(w http.ResponseWriter, r *http.Request) {
@@ -23,3 +29,118 @@ func Relays(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// ApiRelayUnderpayments godoc
+// @Summary Get the latest relay blocks for which the proposer fee recipient received less than the value promised by the relay
+// @Tags Relays
+// @Produce  json
+// @Param  relay query string false "Only return blocks of this relay"
+// @Param  builder query string false "Only return blocks of this builder public key"
+// @Param  limit query int false "Limit the number of results (maximum 100)"
+// @Param  offset query int false "Offset the results"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/relays/underpayments [get]
+func ApiRelayUnderpayments(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+
+	limit := uint64(100)
+	if q.Get("limit") != "" {
+		l, err := strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid limit provided")
+			return
+		}
+		if l < limit {
+			limit = l
+		}
+	}
+	offset := uint64(0)
+	if q.Get("offset") != "" {
+		o, err := strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid offset provided")
+			return
+		}
+		offset = o
+	}
+	var builder []byte
+	if q.Get("builder") != "" {
+		b, err := hex.DecodeString(strings.TrimPrefix(q.Get("builder"), "0x"))
+		if err != nil || len(b) != 48 {
+			sendErrorResponse(w, r.URL.String(), "invalid builder provided")
+			return
+		}
+		builder = b
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT
+			tag_id AS relay,
+			block_slot AS slot,
+			block_root,
+			exec_block_hash,
+			builder_pubkey,
+			proposer_pubkey,
+			proposer_fee_recipient,
+			value,
+			proposer_payment,
+			value - proposer_payment AS shortfall
+		FROM relays_blocks
+		WHERE proposer_payment < value AND ($1 = '' OR tag_id = $1) AND ($2::bytea IS NULL OR builder_pubkey = $2)
+		ORDER BY block_slot DESC, tag_id
+		LIMIT $3 OFFSET $4`, q.Get("relay"), builder, limit, offset)
+	if err != nil {
+		logger.Errorf("error retrieving relay underpayments: %v", err)
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResults(rows, w, r)
+}
+
+// ApiRelayBids godoc
+// @Summary Get the highest bid of every builder received by the relays for a slot
+// @Tags Relays
+// @Produce  json
+// @Param  slot path int true "Slot"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/relays/bids/{slot} [get]
+func ApiRelayBids(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	slot, err := strconv.ParseUint(vars["slot"], 10, 64)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "invalid slot provided")
+		return
+	}
+
+	rows, err := db.ReaderDb.Query(`
+		SELECT
+			tag_id AS relay,
+			slot,
+			builder_pubkey,
+			block_hash,
+			proposer_fee_recipient,
+			value,
+			gas_used,
+			num_tx,
+			bid_count,
+			received_at
+		FROM relays_bids
+		WHERE slot = $1
+		ORDER BY value DESC, tag_id`, slot)
+	if err != nil {
+		logger.Errorf("error retrieving relay bids of slot %v: %v", slot, err)
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	defer rows.Close()
+
+	returnQueryResults(rows, w, r)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"eth2-exporter/db"
+	"eth2-exporter/templates"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Reorgs will return the chain reorgs page using a go template
+func Reorgs(w http.ResponseWriter, r *http.Request) {
+	var reorgsTemplate = templates.GetTemplate("layout.html", "reorgs.html")
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "blockchain", "/reorgs", "Chain Reorgs")
+
+	pageData := &types.ReorgsPageData{}
+	err := db.ReaderDb.Get(pageData, `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE depth = 1) AS single_slot,
+			COUNT(*) FILTER (WHERE ts > NOW() - INTERVAL '1 day') AS last_day,
+			COUNT(*) FILTER (WHERE ts > NOW() - INTERVAL '7 days') AS last_week
+		FROM chain_reorgs`)
+	if err != nil {
+		logger.Errorf("error retrieving chain reorg summary: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	data.Data = pageData
+
+	err = reorgsTemplate.ExecuteTemplate(w, "layout", data)
+	if err != nil {
+		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// ReorgsData will return the recorded chain reorgs as json
+func ReorgsData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+
+	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables data parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables start parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables length parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+	if length > 100 {
+		length = 100
+	}
+
+	reorgs, err := db.GetChainReorgs(length, start)
+	if err != nil {
+		logger.Errorf("error retrieving chain reorgs: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	count, err := db.GetChainReorgCount()
+	if err != nil {
+		logger.Errorf("error retrieving chain reorg count: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	tableData := make([][]interface{}, 0, len(reorgs))
+	for _, reorg := range reorgs {
+		affectedSlots := make([]string, 0, len(reorg.AffectedSlots))
+		for _, slot := range reorg.AffectedSlots {
+			affectedSlots = append(affectedSlots, string(utils.FormatBlockSlot(uint64(slot))))
+		}
+		source := "Event"
+		if reorg.Source == "head_comparison" {
+			source = "Head comparison"
+		}
+
+		tableData = append(tableData, []interface{}{
+			utils.FormatBlockSlot(reorg.Slot),
+			utils.FormatEpoch(reorg.Epoch),
+			utils.FormatTimestamp(reorg.Ts.Unix()),
+			reorg.Depth,
+			template.HTML(strings.Join(affectedSlots, ", ")),
+			utils.FormatBlockRoot(reorg.OldHeadBlock),
+			utils.FormatBlockRoot(reorg.NewHeadBlock),
+			source,
+		})
+	}
+
+	data := &types.DataTableResponse{
+		Draw:            draw,
+		RecordsTotal:    count,
+		RecordsFiltered: count,
+		Data:            tableData,
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+}
+
+// ApiReorgs godoc
+// @Summary Get the most recent chain reorgs, the affected slots are the slots of the orphaned blocks
+// @Tags Blocks
+// @Produce  json
+// @Param  limit query int false "Limit the number of results (maximum 100)"
+// @Param  offset query int false "Offset the results"
+// @Success 200 {object} types.ApiResponse{data=[]types.ChainReorg}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/reorgs [get]
+func ApiReorgs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+
+	limit := uint64(100)
+	if q.Get("limit") != "" {
+		l, err := strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid limit provided")
+			return
+		}
+		if l < limit {
+			limit = l
+		}
+	}
+	offset := uint64(0)
+	if q.Get("offset") != "" {
+		o, err := strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid offset provided")
+			return
+		}
+		offset = o
+	}
+
+	reorgs, err := db.GetChainReorgs(limit, offset)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+	response.Data = reorgs
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), fmt.Sprintf("could not serialize data results: %v", err))
+		return
+	}
+}
@@ -336,26 +336,44 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	validatorPageData.WithdrawalCount, err = db.GetValidatorWithdrawalsCount(validatorPageData.Index)
+	if err != nil {
+		logger.Errorf("error getting validator withdrawals count from db: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	validatorPageData.BLSChange, err = db.GetValidatorBLSChange(validatorPageData.Index)
+	if err != nil {
+		logger.Errorf("error getting validator bls to execution changes from db: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	validatorPageData.WithdrawalCredentialsHistory, err = db.GetValidatorWithdrawalCredentialsHistory(validatorPageData.Index)
+	if err != nil {
+		logger.Errorf("error getting validator withdrawal credentials history from db: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
 	validatorPageData.ActivationEligibilityTs = utils.EpochToTime(validatorPageData.ActivationEligibilityEpoch)
 	validatorPageData.ActivationTs = utils.EpochToTime(validatorPageData.ActivationEpoch)
 	validatorPageData.ExitTs = utils.EpochToTime(validatorPageData.ExitEpoch)
 	validatorPageData.WithdrawableTs = utils.EpochToTime(validatorPageData.WithdrawableEpoch)
 
-	if validatorPageData.ActivationEpoch > 100_000_000 {
-		queueAhead, err := db.GetQueueAheadOfValidator(validatorPageData.Index)
+	if validatorPageData.ExitEpoch > 100_000_000 {
+		queueEstimate, err := services.GetValidatorQueueEstimate(validatorPageData.Index)
 		if err != nil {
-			logger.WithError(err).Warnf("failed to retrieve queue ahead of validator %v: %v", validatorPageData.ValidatorIndex, err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			logger.WithError(err).Warnf("failed to estimate the queue of validator %v", validatorPageData.ValidatorIndex)
+		} else {
+			validatorPageData.QueueEstimate = queueEstimate
+			if queueEstimate.ActivationEstimated {
+				validatorPageData.QueuePosition = queueEstimate.ActivationQueuePosition
+				validatorPageData.EstimatedActivationEpoch = queueEstimate.ActivationEpoch
+				validatorPageData.EstimatedActivationTs = utils.EpochToTime(queueEstimate.ActivationEpoch)
+			}
 		}
-		validatorPageData.QueuePosition = queueAhead + 1
-		epochsToWait := queueAhead / *churnRate
-		// calculate dequeue epoch
-		estimatedActivationEpoch := validatorPageData.Epoch + epochsToWait + 1
-		// add activation offset
-		estimatedActivationEpoch += utils.Config.Chain.Config.MaxSeedLookahead + 1
-		validatorPageData.EstimatedActivationEpoch = estimatedActivationEpoch
-		estimatedDequeueTs := utils.EpochToTime(estimatedActivationEpoch)
-		validatorPageData.EstimatedActivationTs = estimatedDequeueTs
 	}
 
 	proposals := []struct {
@@ -394,6 +412,18 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 		validatorPageData.UnmissedBlocksPercentage = 1.0
 	}
 
+	if validatorPageData.ProposedBlocksCount > 0 || validatorPageData.OrphanedBlocksCount > 0 {
+		blockArrival, err := db.GetProposerBlockArrival(index)
+		if err != nil {
+			logger.Errorf("error retrieving block arrival of validator %v: %v", index, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if blockArrival.Blocks > 0 {
+			validatorPageData.BlockArrival = blockArrival
+		}
+	}
+
 	// logger.Infof("propoals data retrieved, elapsed: %v", time.Since(start))
 	// start = time.Now()
 
@@ -870,6 +900,11 @@ func ValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		err = db.ClassifyMissedAttestations(attestationData)
+		if err != nil {
+			logger.Errorf("error classifying missed attestations of validator %v: %v", index, err)
+		}
+
 		tableData = make([][]interface{}, len(attestationData[index]))
 
 		for i, history := range attestationData[index] {
@@ -880,10 +915,11 @@ func ValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 			tableData[i] = []interface{}{
 				utils.FormatEpoch(history.Epoch),
 				utils.FormatBlockSlot(history.AttesterSlot),
-				utils.FormatAttestationStatus(history.Status),
+				utils.FormatMissedAttestationReason(utils.FormatAttestationStatus(history.Status), history.MissedReason),
 				utils.FormatTimestamp(utils.SlotToTime(history.AttesterSlot).Unix()),
 				utils.FormatAttestationInclusionSlot(history.InclusionSlot),
 				utils.FormatInclusionDelay(history.InclusionSlot, history.Delay),
+				utils.FormatAttestationVotes(history.Votes),
 			}
 		}
 	}
@@ -1024,6 +1060,78 @@ func ValidatorSlashings(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ValidatorWithdrawals returns a validators withdrawals in json
+func ValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	currency := GetCurrency(r)
+
+	vars := mux.Vars(r)
+	index, err := strconv.ParseUint(vars["index"], 10, 64)
+	if err != nil {
+		logger.Errorf("error parsing validator index: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+
+	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables data parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
+	if err != nil {
+		logger.Errorf("error converting datatables start parameter from string to int: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	length := uint64(10)
+
+	totalCount, err := db.GetValidatorWithdrawalsCount(index)
+	if err != nil {
+		logger.Errorf("error retrieving totalCount of validator-withdrawals: %v", err)
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+		return
+	}
+
+	withdrawals, err := db.GetValidatorWithdrawals(index, length, start)
+	if err != nil {
+		logger.Errorf("error retrieving validator withdrawals data: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	tableData := make([][]interface{}, 0, len(withdrawals))
+	for _, withdrawal := range withdrawals {
+		tableData = append(tableData, []interface{}{
+			utils.FormatEpoch(utils.EpochOfSlot(withdrawal.Slot)),
+			utils.FormatBlockSlot(withdrawal.Slot),
+			withdrawal.Index,
+			utils.FormatSlotToTimestamp(withdrawal.Slot),
+			utils.FormatEth1Address(withdrawal.Address),
+			utils.FormatBalance(withdrawal.Amount, currency),
+		})
+	}
+
+	data := &types.DataTableResponse{
+		Draw:            draw,
+		RecordsTotal:    totalCount,
+		RecordsFiltered: totalCount,
+		Data:            tableData,
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
 /*
 Function checks if the generated ECDSA signature has correct lentgth and if needed sets recovery byte to 0 or 1
 */
@@ -1277,6 +1385,17 @@ func ValidatorHistory(w http.ResponseWriter, r *http.Request) {
 		}
 		return nil
 	})
+
+	var rewardsHistory map[uint64][]*types.ValidatorEpochRewards
+	g.Go(func() error {
+		var err error
+		rewardsHistory, err = db.BigtableClient.GetValidatorRewardsHistory([]uint64{index}, currentEpoch-start, 12)
+		if err != nil {
+			logger.Errorf("error retrieving validator rewards history from bigtable: %v", err)
+			return err
+		}
+		return nil
+	})
 	err = g.Wait()
 
 	if err != nil {
@@ -1284,6 +1403,11 @@ func ValidatorHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rewardsMap := make(map[uint64]*types.ValidatorEpochRewards)
+	for _, rewards := range rewardsHistory[index] {
+		rewardsMap[rewards.Epoch] = rewards
+	}
+
 	proposalMap := make(map[uint64]*types.ValidatorProposal)
 	for _, proposal := range proposalHistory[index] {
 		proposalMap[proposal.Slot/32] = &types.ValidatorProposal{
@@ -1360,7 +1484,7 @@ func ValidatorHistory(w http.ResponseWriter, r *http.Request) {
 			tableData = append(tableData, []interface{}{
 				utils.FormatEpoch(b.Epoch),
 				utils.FormatBalanceChangeFormated(&b.BalanceChange.Int64, currency),
-				template.HTML(""),
+				utils.FormatRewardsBreakdown(rewardsMap[b.Epoch]),
 				template.HTML(events),
 			})
 		}
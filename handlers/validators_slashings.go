@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/gorilla/mux"
 	"github.com/juliangruber/go-intersect"
 )
 
@@ -21,7 +22,17 @@ func ValidatorsSlashings(w http.ResponseWriter, r *http.Request) {
 	data := InitPageData(w, r, "validators", "/validators/slashings", "Validator Slashings")
 	data.HeaderAd = true
 
-	err := validatorsSlashingsTemplate.ExecuteTemplate(w, "layout", data)
+	potentialSlashings, err := db.GetPotentialSlashings(nil, 0, 100)
+	if err != nil {
+		logger.Errorf("error retrieving potential slashings: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	data.Data = &types.ValidatorsSlashingsPageData{
+		PotentialSlashings: potentialSlashings,
+	}
+
+	err = validatorsSlashingsTemplate.ExecuteTemplate(w, "layout", data)
 
 	if err != nil {
 		logger.Errorf("error executing template for %v route: %v", r.URL.String(), err)
@@ -159,3 +170,78 @@ func ValidatorsSlashingsData(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// ApiPotentialSlashings godoc
+// @Summary Get the most recent double votes, surround votes and double proposals detected by the explorer, including the conflicting messages as evidence
+// @Tags Validator
+// @Produce  json
+// @Param  epoch query int false "Only return potential slashings since this epoch"
+// @Param  limit query int false "Limit the number of results (maximum 100)"
+// @Success 200 {object} types.ApiResponse{data=[]types.PotentialSlashing}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/slashings/potential [get]
+func ApiPotentialSlashings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+
+	limit := uint64(100)
+	if q.Get("limit") != "" {
+		l, err := strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid limit provided")
+			return
+		}
+		if l < limit {
+			limit = l
+		}
+	}
+	epoch := uint64(0)
+	if q.Get("epoch") != "" {
+		e, err := strconv.ParseUint(q.Get("epoch"), 10, 64)
+		if err != nil {
+			sendErrorResponse(w, r.URL.String(), "invalid epoch provided")
+			return
+		}
+		epoch = e
+	}
+
+	slashings, err := db.GetPotentialSlashings(nil, epoch, limit)
+	if err != nil {
+		logger.Errorf("error retrieving potential slashings: %v", err)
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{slashings})
+}
+
+// ApiValidatorPotentialSlashings godoc
+// @Summary Get the double votes, surround votes and double proposals detected by the explorer for up to 100 validators
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.PotentialSlashing}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/potentialslashings [get]
+func ApiValidatorPotentialSlashings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParam(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		sendErrorResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	slashings, err := db.GetPotentialSlashings(queryIndices, 0, 100)
+	if err != nil {
+		logger.Errorf("error retrieving potential slashings of validators %v: %v", queryIndices, err)
+		sendErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	sendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{slashings})
+}
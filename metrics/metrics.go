@@ -55,6 +55,35 @@ var (
 		Name: "notifications_queued",
 		Help: "Counter of notification channel and event type that gets queued",
 	}, []string{"channel", "event_type"})
+	BeaconNodeRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "beacon_node_request_duration",
+		Help:    "Duration of requests to the beacon node in seconds by api route, method and status_code.",
+		Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"route", "method", "status_code"})
+	BeaconNodeRequestRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_node_request_retries",
+		Help: "Counter of retried requests to the beacon node by api route.",
+	}, []string{"route"})
+	BeaconEventsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_events_received",
+		Help: "Counter of events received via the beacon node event stream with the topic in the label",
+	}, []string{"topic"})
+	BackfillEpochsExported = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "backfill_epochs_exported",
+		Help: "Counter of epochs exported by the backfill workers",
+	})
+	BackfillRanges = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backfill_ranges",
+		Help: "Number of backfill epoch ranges by status",
+	}, []string{"status"})
+	BackfillEpochsRemaining = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "backfill_epochs_remaining",
+		Help: "Number of epochs in the backfill queue that still have to be exported",
+	})
+	ExporterLeader = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "exporter_leader",
+		Help: "Set to 1 if the indexer instance in the label is the exporter leader",
+	}, []string{"instance"})
 	NotificationsSent = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "notifications_sent",
 		Help: "Counter of notifications sent with the channel and notification type in the label",
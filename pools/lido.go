@@ -0,0 +1,114 @@
+package pools
+
+import (
+	"context"
+	"eth2-exporter/types"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	lidoPoolName = "Lido"
+	// lidoSigningKeysBatchSize is the number of signing keys requested per call to the node operators registry
+	lidoSigningKeysBatchSize = 100
+	blsPubkeyLength          = 48
+)
+
+// lidoNodeOperatorsRegistryABI contains the view functions of the Lido node operators registry used by the adapter
+const lidoNodeOperatorsRegistryABI = `[
+	{"inputs":[],"name":"getNodeOperatorsCount","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"_nodeOperatorId","type":"uint256"},{"name":"_fullInfo","type":"bool"}],"name":"getNodeOperator","outputs":[{"name":"active","type":"bool"},{"name":"name","type":"string"},{"name":"rewardAddress","type":"address"},{"name":"totalVettedValidators","type":"uint64"},{"name":"totalExitedValidators","type":"uint64"},{"name":"totalAddedValidators","type":"uint64"},{"name":"totalDepositedValidators","type":"uint64"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"_nodeOperatorId","type":"uint256"},{"name":"_offset","type":"uint256"},{"name":"_limit","type":"uint256"}],"name":"getSigningKeys","outputs":[{"name":"pubkeys","type":"bytes"},{"name":"signatures","type":"bytes"},{"name":"used","type":"bool[]"}],"stateMutability":"view","type":"function"}
+]`
+
+// LidoAdapter discovers the validators of the Lido node operators from the node operators registry contract.
+// Only keys of deposited validators are returned, keys that have been added but not yet deposited can still be removed.
+type LidoAdapter struct {
+	registry *bind.BoundContract
+}
+
+// NewLidoAdapter creates an adapter reading the node operators registry at registryAddress via the passed caller
+func NewLidoAdapter(caller bind.ContractCaller, registryAddress common.Address) (*LidoAdapter, error) {
+	parsed, err := abi.JSON(strings.NewReader(lidoNodeOperatorsRegistryABI))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing lido node operators registry abi: %v", err)
+	}
+	return &LidoAdapter{
+		registry: bind.NewBoundContract(registryAddress, parsed, caller, nil, nil),
+	}, nil
+}
+
+// Name returns the name of the pool
+func (l *LidoAdapter) Name() string {
+	return lidoPoolName
+}
+
+// Operators returns the node operators of the registry together with the pubkeys of their newly deposited validators
+func (l *LidoAdapter) Operators(ctx context.Context, knownValidators map[string]uint64) ([]*types.PoolOperator, error) {
+	out, err := l.call(ctx, "getNodeOperatorsCount")
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving lido node operator count: %v", err)
+	}
+	count := out[0].(*big.Int).Uint64()
+
+	operators := make([]*types.PoolOperator, 0, count)
+	for id := uint64(0); id < count; id++ {
+		out, err := l.call(ctx, "getNodeOperator", new(big.Int).SetUint64(id), true)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving lido node operator %v: %v", id, err)
+		}
+		rewardAddress := out[2].(common.Address)
+		operator := &types.PoolOperator{
+			Pool:           lidoPoolName,
+			ID:             fmt.Sprintf("%d", id),
+			Name:           out[1].(string),
+			Address:        rewardAddress.Bytes(),
+			Active:         out[0].(bool),
+			ValidatorCount: out[6].(uint64),
+		}
+
+		for offset := knownValidators[operator.ID]; offset < operator.ValidatorCount; offset += lidoSigningKeysBatchSize {
+			limit := operator.ValidatorCount - offset
+			if limit > lidoSigningKeysBatchSize {
+				limit = lidoSigningKeysBatchSize
+			}
+			out, err := l.call(ctx, "getSigningKeys", new(big.Int).SetUint64(id), new(big.Int).SetUint64(offset), new(big.Int).SetUint64(limit))
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving signing keys %v-%v of lido node operator %v: %v", offset, offset+limit, id, err)
+			}
+			pubkeys, err := splitPubkeys(out[0].([]byte))
+			if err != nil {
+				return nil, fmt.Errorf("error parsing signing keys of lido node operator %v: %v", id, err)
+			}
+			operator.NewValidators = append(operator.NewValidators, pubkeys...)
+		}
+
+		operators = append(operators, operator)
+	}
+
+	logger.Infof("discovered %v lido node operators", len(operators))
+	return operators, nil
+}
+
+func (l *LidoAdapter) call(ctx context.Context, method string, params ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	err := l.registry.Call(&bind.CallOpts{Context: ctx}, &out, method, params...)
+	return out, err
+}
+
+// splitPubkeys splits the concatenated bls pubkeys returned by the registry
+func splitPubkeys(data []byte) ([][]byte, error) {
+	if len(data)%blsPubkeyLength != 0 {
+		return nil, fmt.Errorf("invalid length %v of concatenated pubkeys", len(data))
+	}
+	pubkeys := make([][]byte, 0, len(data)/blsPubkeyLength)
+	for i := 0; i < len(data); i += blsPubkeyLength {
+		pubkeys = append(pubkeys, data[i:i+blsPubkeyLength])
+	}
+	return pubkeys, nil
+}
@@ -0,0 +1,128 @@
+package pools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type testLidoOperator struct {
+	name      string
+	active    bool
+	deposited uint64
+}
+
+// testLidoRegistry answers calls to the node operators registry like the deployed contract would
+type testLidoRegistry struct {
+	abi       abi.ABI
+	operators []testLidoOperator
+	keyCalls  int
+}
+
+func testPubkey(operator, index uint64) []byte {
+	return bytes.Repeat([]byte{byte(operator<<4 | index%16)}, blsPubkeyLength)
+}
+
+func (r *testLidoRegistry) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (r *testLidoRegistry) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := r.abi.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "getNodeOperatorsCount":
+		return method.Outputs.Pack(big.NewInt(int64(len(r.operators))))
+	case "getNodeOperator":
+		op := r.operators[args[0].(*big.Int).Uint64()]
+		return method.Outputs.Pack(op.active, op.name, common.HexToAddress("0x01"), op.deposited, uint64(0), op.deposited, op.deposited)
+	case "getSigningKeys":
+		r.keyCalls++
+		id, offset, limit := args[0].(*big.Int).Uint64(), args[1].(*big.Int).Uint64(), args[2].(*big.Int).Uint64()
+		pubkeys := []byte{}
+		used := []bool{}
+		for i := offset; i < offset+limit; i++ {
+			pubkeys = append(pubkeys, testPubkey(id, i)...)
+			used = append(used, true)
+		}
+		return method.Outputs.Pack(pubkeys, make([]byte, 96*len(used)), used)
+	}
+	return nil, fmt.Errorf("unexpected call of %v", method.Name)
+}
+
+func TestLidoAdapterOperators(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(lidoNodeOperatorsRegistryABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := &testLidoRegistry{
+		abi: parsed,
+		operators: []testLidoOperator{
+			{name: "Operator A", active: true, deposited: 250},
+			{name: "Operator B", active: false, deposited: 3},
+		},
+	}
+
+	adapter, err := NewLidoAdapter(registry, common.HexToAddress("0x55032650b14df07b85bF18A3a3eC8E0Af2e028d5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operators, err := adapter.Operators(context.Background(), map[string]uint64{"0": 120})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(operators) != 2 {
+		t.Fatalf("expected 2 operators, got %v", len(operators))
+	}
+
+	a := operators[0]
+	if a.Pool != "Lido" || a.ID != "0" || a.Name != "Operator A" || !a.Active || a.ValidatorCount != 250 {
+		t.Errorf("unexpected operator %+v", a)
+	}
+	if len(a.NewValidators) != 130 {
+		t.Fatalf("expected 130 new validators of operator 0, got %v", len(a.NewValidators))
+	}
+	if !bytes.Equal(a.NewValidators[0], testPubkey(0, 120)) || !bytes.Equal(a.NewValidators[129], testPubkey(0, 249)) {
+		t.Errorf("unexpected pubkeys of operator 0")
+	}
+
+	b := operators[1]
+	if b.Active || len(b.NewValidators) != 3 {
+		t.Errorf("unexpected operator %+v", b)
+	}
+
+	// 130 keys of operator 0 in batches of 100 and 3 keys of operator 1
+	if registry.keyCalls != 3 {
+		t.Errorf("expected 3 signing key requests, got %v", registry.keyCalls)
+	}
+}
+
+func TestSplitPubkeys(t *testing.T) {
+	pubkeys, err := splitPubkeys(append(testPubkey(1, 1), testPubkey(1, 2)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubkeys) != 2 || !bytes.Equal(pubkeys[1], testPubkey(1, 2)) {
+		t.Errorf("unexpected pubkeys %x", pubkeys)
+	}
+
+	_, err = splitPubkeys(make([]byte, blsPubkeyLength+1))
+	if err == nil {
+		t.Errorf("expected error for truncated pubkeys")
+	}
+}
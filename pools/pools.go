@@ -0,0 +1,19 @@
+package pools
+
+import (
+	"context"
+	"eth2-exporter/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+var logger = logrus.New().WithField("module", "pools")
+
+// PoolAdapter discovers the node operators and validators of a staking pool from its on-chain contracts or registry events
+type PoolAdapter interface {
+	// Name returns the name of the pool, it is used for the validator_pool entry and the pool:<name> validator tag
+	Name() string
+	// Operators returns all operators of the pool. knownValidators holds the number of validators per operator id that
+	// have already been exported, only the pubkeys of validators beyond that count are returned as NewValidators.
+	Operators(ctx context.Context, knownValidators map[string]uint64) ([]*types.PoolOperator, error)
+}
@@ -0,0 +1,258 @@
+package rpc
+
+import (
+	"encoding/json"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/donovanhide/eventsource"
+)
+
+// EventTopics are the beacon node event stream topics the client subscribes to
+var EventTopics = []string{"head", "block", "finalized_checkpoint", "chain_reorg", "voluntary_exit", "attester_slashing"}
+
+const (
+	eventStreamMinBackoff = time.Second
+	eventStreamMaxBackoff = time.Minute
+
+	// block events are enriched with the block data by a worker, the queue buffers block events while the worker
+	// retries blocks the node does not serve yet
+	blockEventQueueSize = 100
+	blockEventRetries   = 5
+)
+
+type StreamedHeadEventData struct {
+	Slot                      uint64Str `json:"slot"`
+	Block                     string    `json:"block"`
+	State                     string    `json:"state"`
+	EpochTransition           bool      `json:"epoch_transition"`
+	PreviousDutyDependentRoot string    `json:"previous_duty_dependent_root"`
+	CurrentDutyDependentRoot  string    `json:"current_duty_dependent_root"`
+	ExecutionOptimistic       bool      `json:"execution_optimistic"`
+}
+
+type StreamedBlockEventData struct {
+	Slot                uint64Str `json:"slot"`
+	Block               string    `json:"block"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+type StreamedFinalizedCheckpointEventData struct {
+	Block               string    `json:"block"`
+	State               string    `json:"state"`
+	Epoch               uint64Str `json:"epoch"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+type StreamedChainReorgEventData struct {
+	Slot                uint64Str `json:"slot"`
+	Depth               uint64Str `json:"depth"`
+	OldHeadBlock        string    `json:"old_head_block"`
+	NewHeadBlock        string    `json:"new_head_block"`
+	OldHeadState        string    `json:"old_head_state"`
+	NewHeadState        string    `json:"new_head_state"`
+	Epoch               uint64Str `json:"epoch"`
+	ExecutionOptimistic bool      `json:"execution_optimistic"`
+}
+
+// GetEventChan subscribes to the event stream of the node and returns a channel emitting the typed events.
+// The subscription is re-established with an exponential backoff whenever the stream fails or stalls.
+func (lc *LighthouseClient) GetEventChan() chan types.BeaconEvent {
+	evCh := make(chan types.BeaconEvent, 100)
+	blockEvCh := make(chan *types.BlockEvent, blockEventQueueSize)
+	go lc.blockEventWorker(blockEvCh, evCh)
+	go func() {
+		backoff := eventStreamMinBackoff
+		for {
+			start := time.Now()
+			err := lc.streamEvents(evCh, blockEvCh)
+			if time.Since(start) > eventStreamMaxBackoff {
+				// the stream was healthy for a while, start over with the minimum backoff
+				backoff = eventStreamMinBackoff
+			}
+			logger.Warnf("event stream of %v interrupted, reconnecting in %v: %v", lc.endpoint, backoff, err)
+			time.Sleep(backoff)
+
+			backoff *= 2
+			if backoff > eventStreamMaxBackoff {
+				backoff = eventStreamMaxBackoff
+			}
+		}
+	}()
+	return evCh
+}
+
+// streamEvents forwards the events of a single event stream subscription until the stream fails or stalls. Block events
+// are handed to the block event worker so that retrieving the block never blocks the stream.
+func (lc *LighthouseClient) streamEvents(evCh chan types.BeaconEvent, blockEvCh chan *types.BlockEvent) error {
+	stream, err := eventsource.Subscribe(fmt.Sprintf("%s/eth/v1/events?topics=%s", lc.endpoint, strings.Join(EventTopics, ",")), "")
+	if err != nil {
+		return fmt.Errorf("error subscribing to event stream: %v", err)
+	}
+	defer stream.Close()
+	logger.Infof("subscribed to event stream of %v", lc.endpoint)
+
+	// a head event is expected every slot, consider the stream stalled if nothing arrived for an entire epoch
+	stallTimeout := time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot*utils.Config.Chain.Config.SlotsPerEpoch)
+	stallTimer := time.NewTimer(stallTimeout)
+	defer stallTimer.Stop()
+
+	for {
+		select {
+		case e, ok := <-stream.Events:
+			if !ok {
+				return fmt.Errorf("event stream closed")
+			}
+			if !stallTimer.Stop() {
+				<-stallTimer.C
+			}
+			stallTimer.Reset(stallTimeout)
+
+			ev, err := lc.parseEvent(e.Event(), []byte(e.Data()), time.Now())
+			if err != nil {
+				logger.Warnf("failed to decode %v event: %v", e.Event(), err)
+				continue
+			}
+			if blockEv, ok := ev.(*types.BlockEvent); ok {
+				select {
+				case blockEvCh <- blockEv:
+				default:
+					logger.Errorf("block event queue of %v is full, dropping block %#x of slot %v", lc.endpoint, blockEv.BlockRoot, blockEv.Slot)
+				}
+				continue
+			}
+			if ev != nil {
+				evCh <- ev
+			}
+		case err := <-stream.Errors:
+			return err
+		case <-stallTimer.C:
+			return fmt.Errorf("no event received within %v", stallTimeout)
+		}
+	}
+}
+
+// parseEvent converts the data of a streamed event into a typed event, the block data of block events is retrieved by the
+// block event worker. Head and block events are stamped with receivedAt so that the arrival of blocks can be related to the slot start.
+func (lc *LighthouseClient) parseEvent(topic string, data []byte, receivedAt time.Time) (types.BeaconEvent, error) {
+	switch topic {
+	case "head":
+		var parsed StreamedHeadEventData
+		err := json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.HeadEvent{
+			Slot:                      uint64(parsed.Slot),
+			Block:                     utils.MustParseHex(parsed.Block),
+			State:                     utils.MustParseHex(parsed.State),
+			EpochTransition:           parsed.EpochTransition,
+			PreviousDutyDependentRoot: utils.MustParseHex(parsed.PreviousDutyDependentRoot),
+			CurrentDutyDependentRoot:  utils.MustParseHex(parsed.CurrentDutyDependentRoot),
+			ExecutionOptimistic:       parsed.ExecutionOptimistic,
+			ReceivedAt:                receivedAt,
+		}, nil
+	case "block":
+		var parsed StreamedBlockEventData
+		err := json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.BlockEvent{
+			Slot:                uint64(parsed.Slot),
+			BlockRoot:           utils.MustParseHex(parsed.Block),
+			ExecutionOptimistic: parsed.ExecutionOptimistic,
+			ReceivedAt:          receivedAt,
+		}, nil
+	case "finalized_checkpoint":
+		var parsed StreamedFinalizedCheckpointEventData
+		err := json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.FinalizedCheckpointEvent{
+			Epoch:               uint64(parsed.Epoch),
+			Block:               utils.MustParseHex(parsed.Block),
+			State:               utils.MustParseHex(parsed.State),
+			ExecutionOptimistic: parsed.ExecutionOptimistic,
+		}, nil
+	case "chain_reorg":
+		var parsed StreamedChainReorgEventData
+		err := json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.ChainReorgEvent{
+			Slot:                uint64(parsed.Slot),
+			Depth:               uint64(parsed.Depth),
+			Epoch:               uint64(parsed.Epoch),
+			OldHeadBlock:        utils.MustParseHex(parsed.OldHeadBlock),
+			NewHeadBlock:        utils.MustParseHex(parsed.NewHeadBlock),
+			OldHeadState:        utils.MustParseHex(parsed.OldHeadState),
+			NewHeadState:        utils.MustParseHex(parsed.NewHeadState),
+			ExecutionOptimistic: parsed.ExecutionOptimistic,
+		}, nil
+	case "voluntary_exit":
+		var parsed VoluntaryExit
+		err := json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.VoluntaryExitEvent{
+			VoluntaryExit: types.VoluntaryExit{
+				Epoch:          uint64(parsed.Message.Epoch),
+				ValidatorIndex: uint64(parsed.Message.ValidatorIndex),
+				Signature:      utils.MustParseHex(parsed.Signature),
+			},
+		}, nil
+	case "attester_slashing":
+		var parsed AttesterSlashing
+		err := json.Unmarshal(data, &parsed)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttesterSlashingEvent{
+			AttesterSlashing: *attesterSlashingFromResponse(&parsed),
+		}, nil
+	default:
+		logger.Debugf("ignoring event of unknown topic %v", topic)
+		return nil, nil
+	}
+}
+
+// blockEventWorker retrieves the block data of queued block events and forwards the enriched events
+func (lc *LighthouseClient) blockEventWorker(blockEvCh chan *types.BlockEvent, evCh chan types.BeaconEvent) {
+	for ev := range blockEvCh {
+		block, err := lc.getEventBlock(ev)
+		if err != nil {
+			logger.Errorf("error retrieving block of block event: %v", err)
+			continue
+		}
+		ev.Block = block
+		evCh <- ev
+	}
+}
+
+// getEventBlock retrieves the block of a block event, retrying with an exponential backoff as the node may not serve a
+// block it just announced yet
+func (lc *LighthouseClient) getEventBlock(ev *types.BlockEvent) (*types.Block, error) {
+	backoff := eventStreamMinBackoff
+	for attempt := 1; ; attempt++ {
+		block, err := lc.GetBlockByBlockroot(ev.BlockRoot)
+		if err == nil && block.BlockRoot == nil {
+			err = fmt.Errorf("block not found")
+		}
+		if err == nil {
+			return block, nil
+		}
+		if attempt == blockEventRetries {
+			return nil, fmt.Errorf("error retrieving block %#x of slot %v after %v attempts: %v", ev.BlockRoot, ev.Slot, attempt, err)
+		}
+		logger.Warnf("error retrieving block %#x of slot %v, retrying in %v: %v", ev.BlockRoot, ev.Slot, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,188 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"eth2-exporter/metrics"
+	"eth2-exporter/utils"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRequestTimeout        = time.Second * 120
+	defaultStateRequestTimeout   = time.Second * 300
+	defaultRewardsRequestTimeout = time.Second * 300
+	defaultRequestRetries        = 2
+	requestRetryBaseDelay        = time.Millisecond * 250
+)
+
+// routeParamRE matches path segments holding a block id, state id, slot, epoch or root
+var routeParamRE = regexp.MustCompile(`^(\d+|0x[0-9a-fA-F]*|head|genesis|finalized|justified)$`)
+
+// beaconResponse holds the result of a request to the beacon node
+type beaconResponse struct {
+	status int
+	header http.Header
+	data   []byte
+}
+
+// apiRoute returns the beacon api route of the url with all ids replaced by placeholders, it is used as metrics label
+func apiRoute(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "unknown"
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if routeParamRE.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// requestTimeout returns the configured timeout for requests to the route
+func requestTimeout(route string) time.Duration {
+	timeouts := utils.Config.Indexer.Node.Timeouts
+	switch {
+	case strings.Contains(route, "/rewards/"):
+		if timeouts.Rewards > 0 {
+			return timeouts.Rewards
+		}
+		return defaultRewardsRequestTimeout
+	case strings.Contains(route, "/states/"):
+		if timeouts.State > 0 {
+			return timeouts.State
+		}
+		return defaultStateRequestTimeout
+	default:
+		if timeouts.Default > 0 {
+			return timeouts.Default
+		}
+		return defaultRequestTimeout
+	}
+}
+
+// requestRetries returns the number of times a failed request is retried
+func requestRetries() int {
+	if utils.Config.Indexer.Node.MaxRetries > 0 {
+		return utils.Config.Indexer.Node.MaxRetries
+	}
+	return defaultRequestRetries
+}
+
+// retryable reports whether a request with the given response status or error should be retried
+func retryable(status int, err error) bool {
+	if err != nil {
+		return true
+	}
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// request sends a request to the beacon node. Requests failing with a network error, a server error or because of
+// rate limiting are retried with an exponential backoff and jitter until the context is done.
+func (lc *LighthouseClient) request(ctx context.Context, method, rawURL string, body []byte, header http.Header) (*beaconResponse, error) {
+	route := apiRoute(rawURL)
+	retries := requestRetries()
+
+	var res *beaconResponse
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			delay := requestRetryBaseDelay * time.Duration(1<<(attempt-1))
+			delay += time.Duration(rand.Int63n(int64(delay)))
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			metrics.BeaconNodeRequestRetries.WithLabelValues(route).Inc()
+		}
+
+		res, err = lc.doRequest(ctx, method, rawURL, route, body, header)
+		if !retryable(statusOf(res), err) || ctx.Err() != nil {
+			break
+		}
+		logger.Warnf("request %v %v failed (attempt %v of %v): %v", method, route, attempt+1, retries+1, requestError(res, err))
+	}
+	return res, err
+}
+
+func (lc *LighthouseClient) doRequest(ctx context.Context, method, rawURL, route string, body []byte, header http.Header) (*beaconResponse, error) {
+	start := time.Now()
+	status := "error"
+	defer func() {
+		metrics.BeaconNodeRequestDuration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout(route))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := lc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	status = strconv.Itoa(resp.StatusCode)
+
+	return &beaconResponse{status: resp.StatusCode, header: resp.Header, data: data}, nil
+}
+
+func statusOf(res *beaconResponse) int {
+	if res == nil {
+		return 0
+	}
+	return res.status
+}
+
+// requestError returns the error of a failed request
+func requestError(res *beaconResponse, err error) error {
+	if err != nil {
+		return err
+	}
+	if res.status == http.StatusNotFound {
+		return notFoundErr
+	}
+	return fmt.Errorf("error-response: %s", res.data)
+}
+
+// getWithContext requests the url and returns the response data
+func (lc *LighthouseClient) getWithContext(ctx context.Context, url string) ([]byte, error) {
+	res, err := lc.request(ctx, http.MethodGet, url, nil, nil)
+	if err != nil || res.status != http.StatusOK {
+		return nil, requestError(res, err)
+	}
+	return res.data, nil
+}
+
+// postWithContext sends the json encoded body to the url and returns the response data
+func (lc *LighthouseClient) postWithContext(ctx context.Context, url string, body []byte) ([]byte, error) {
+	res, err := lc.request(ctx, http.MethodPost, url, body, http.Header{"Content-Type": []string{"application/json"}})
+	if err != nil || res.status != http.StatusOK {
+		return nil, requestError(res, err)
+	}
+	return res.data, nil
+}
@@ -0,0 +1,194 @@
+package rpc
+
+import (
+	"bytes"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAPIRoute(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:5052/eth/v1/beacon/headers/head":                         "/eth/v1/beacon/headers/{id}",
+		"http://localhost:5052/eth/v2/beacon/blocks/0xabcdef":                      "/eth/v2/beacon/blocks/{id}",
+		"http://localhost:5052/eth/v1/beacon/states/3200/validators?status=active": "/eth/v1/beacon/states/{id}/validators",
+		"http://localhost:5052/lighthouse/validator_inclusion/100/global":          "/lighthouse/validator_inclusion/{id}/global",
+	}
+	for rawURL, expected := range tests {
+		if route := apiRoute(rawURL); route != expected {
+			t.Errorf("unexpected route for %v: got %v, expected %v", rawURL, route, expected)
+		}
+	}
+}
+
+func TestRequestRetries(t *testing.T) {
+	utils.Config = &types.Config{}
+	utils.Config.Indexer.Node.MaxRetries = 2
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			atomic.AddInt32(&calls, 1)
+			http.NotFound(w, r)
+		case atomic.AddInt32(&calls, 1) < 3:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewLighthouseClient(server.URL, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	data, err := client.get(server.URL + "/flaky")
+	if err != nil {
+		t.Fatalf("error retrieving flaky endpoint: %v", err)
+	}
+	if string(data) != "ok" || calls != 3 {
+		t.Errorf("unexpected response %q after %v calls", data, calls)
+	}
+
+	calls = 0
+	_, err = client.get(server.URL + "/missing")
+	if err != notFoundErr || calls != 1 {
+		t.Errorf("expected a single not found request, got %v after %v calls", err, calls)
+	}
+}
+
+func TestGetOperationPool(t *testing.T) {
+	utils.Config = &types.Config{}
+
+	responses := map[string]string{
+		"/eth/v1/beacon/pool/proposer_slashings": `{"data":[{"signed_header_1":{"message":{"slot":"10","proposer_index":"7","parent_root":"0x","state_root":"0x","body_root":"0x"},"signature":"0x"},"signed_header_2":{"message":{"slot":"10","proposer_index":"7","parent_root":"0x","state_root":"0x","body_root":"0x"},"signature":"0x"}}]}`,
+		"/eth/v1/beacon/pool/attester_slashings": `{"data":[{"attestation_1":{"attesting_indices":["3","1","2"],"signature":"0x","data":{"slot":"1","index":"0","beacon_block_root":"0x","source":{"epoch":"0","root":"0x"},"target":{"epoch":"1","root":"0x"}}},"attestation_2":{"attesting_indices":["2","3","4"],"signature":"0x","data":{"slot":"1","index":"0","beacon_block_root":"0x","source":{"epoch":"0","root":"0x"},"target":{"epoch":"1","root":"0x"}}}}]}`,
+		"/eth/v1/beacon/pool/voluntary_exits":    `{"data":[{"message":{"epoch":"5","validator_index":"9"},"signature":"0x"}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(resp))
+	}))
+	defer server.Close()
+
+	client, err := NewLighthouseClient(server.URL, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	pool, err := client.GetOperationPool()
+	if err != nil {
+		t.Fatalf("error retrieving operation pool: %v", err)
+	}
+	if len(pool.ProposerSlashings) != 1 || pool.ProposerSlashings[0] != 7 {
+		t.Errorf("unexpected proposer slashings %v", pool.ProposerSlashings)
+	}
+	if len(pool.AttesterSlashings) != 1 {
+		t.Fatalf("unexpected number of attester slashings %v", len(pool.AttesterSlashings))
+	}
+	if key := utils.AttesterSlashingKey(pool.AttesterSlashings[0].Attestation1.AttestingIndices, pool.AttesterSlashings[0].Attestation2.AttestingIndices); key != "2,3" {
+		t.Errorf("unexpected attester slashing key %v", key)
+	}
+	if len(pool.VoluntaryExits) != 1 || pool.VoluntaryExits[0].ValidatorIndex != 9 || pool.VoluntaryExits[0].Epoch != 5 {
+		t.Errorf("unexpected voluntary exits %v", pool.VoluntaryExits)
+	}
+}
+
+func TestEpochAssignmentsLookaheadNotCached(t *testing.T) {
+	utils.Config = &types.Config{}
+	utils.Config.Chain.Config.SlotsPerEpoch = 32
+	utils.Config.Chain.Config.SecondsPerSlot = 12
+	utils.Config.Chain.Config.AltairForkEpoch = math.MaxUint64
+	utils.Config.Chain.GenesisTimestamp = uint64(time.Now().Unix())
+
+	var proposerDutyCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/validator/duties/proposer/"):
+			atomic.AddInt32(&proposerDutyCalls, 1)
+			w.Write([]byte(`{"dependent_root":"0x01","data":[{"pubkey":"0x","validator_index":"1","slot":"1"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/eth/v1/beacon/headers/"):
+			w.Write([]byte(`{"data":{"root":"0x01","canonical":true,"header":{"message":{"slot":"0","proposer_index":"0","parent_root":"0x","state_root":"0x02","body_root":"0x"},"signature":"0x"}}}`))
+		case strings.HasSuffix(r.URL.Path, "/committees"):
+			w.Write([]byte(`{"data":[{"index":"0","slot":"1","validators":["1","2"]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewLighthouseClient(server.URL, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, epoch := range []uint64{0, 0, 1, 1} {
+		_, err := client.GetEpochAssignments(epoch)
+		if err != nil {
+			t.Fatalf("error retrieving assignments of epoch %v: %v", epoch, err)
+		}
+	}
+	// the current epoch is served from the cache on the second call, the next epoch is retrieved every time
+	if proposerDutyCalls != 3 {
+		t.Errorf("got %v proposer duty requests, want 3", proposerDutyCalls)
+	}
+}
+
+func TestGetBlobSidecars(t *testing.T) {
+	utils.Config = &types.Config{}
+
+	pruned := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pruned || r.URL.Path != "/eth/v1/beacon/blob_sidecars/0xaa" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data":[{"index":"1","kzg_commitment":"0x02","kzg_proof":"0x12"},{"index":"0","kzg_commitment":"0x01","kzg_proof":"0x11"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewLighthouseClient(server.URL, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	commitments := [][]byte{{0x01}, {0x02}}
+	sidecars, err := client.getBlobSidecars("0xaa", commitments)
+	if err != nil {
+		t.Fatalf("error retrieving blob sidecars: %v", err)
+	}
+	if len(sidecars) != 2 || !bytes.Equal(sidecars[0].KzgProof, []byte{0x11}) || !bytes.Equal(sidecars[1].KzgProof, []byte{0x12}) {
+		t.Errorf("unexpected kzg proofs of blob sidecars %+v", sidecars)
+	}
+
+	_, err = client.getBlobSidecars("0xaa", [][]byte{{0x01}, {0x03}})
+	if err == nil {
+		t.Errorf("expected an error for a blob sidecar not matching the commitment of the block")
+	}
+
+	pruned = true
+	sidecars, err = client.getBlobSidecars("0xaa", commitments)
+	if err != nil {
+		t.Fatalf("error retrieving pruned blob sidecars: %v", err)
+	}
+	if len(sidecars) != 2 || sidecars[0].KzgProof != nil || !bytes.Equal(sidecars[1].KzgCommitment, []byte{0x02}) {
+		t.Errorf("expected blob sidecars derived from the commitments after pruning, got %+v", sidecars)
+	}
+}
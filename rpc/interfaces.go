@@ -14,12 +14,33 @@ type Client interface {
 	GetEpochAssignments(epoch uint64) (*types.EpochAssignments, error)
 	GetBlocksBySlot(slot uint64) ([]*types.Block, error)
 	GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error)
-	GetNewBlockChan() chan *types.Block
+	GetEventChan() chan types.BeaconEvent
 	GetBlockStatusByEpoch(slot uint64) ([]*types.CanonBlock, error)
 	GetFinalityCheckpoints(epoch uint64) (*types.FinalityCheckpoints, error)
 	GetSyncCommittee(stateID string, epoch uint64) (*StandardSyncCommittee, error)
 }
 
+// SyncStatusClient is implemented by clients that can report the sync status of their node
+type SyncStatusClient interface {
+	GetSyncStatus() (*types.NodeSyncStatus, error)
+}
+
+// BlockRootClient is implemented by clients that can look up the canonical block root of a slot
+type BlockRootClient interface {
+	GetBlockRootBySlot(slot uint64) ([]byte, error)
+}
+
+// OperationPoolClient is implemented by clients that can retrieve the slashings and voluntary exits pending in the
+// operation pool of their node
+type OperationPoolClient interface {
+	GetOperationPool() (*types.OperationPool, error)
+}
+
+// RewardsClient is implemented by clients that can retrieve the reward breakdown of validators via the standard rewards api
+type RewardsClient interface {
+	GetValidatorRewards(epoch uint64) (map[uint64]*types.ValidatorEpochRewards, error)
+}
+
 type Eth1Client interface {
 	GetBlock(number uint64) (*types.Eth1Block, *types.GetBlockTimings, error)
 	GetLatestEth1BlockNumber() (uint64, error)
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
@@ -9,14 +10,12 @@ import (
 	"eth2-exporter/types"
 	"eth2-exporter/utils"
 	"fmt"
-	"io/ioutil"
 	"math/big"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
-	"github.com/donovanhide/eventsource"
 	gtypes "github.com/ethereum/go-ethereum/core/types"
 
 	lru "github.com/hashicorp/golang-lru"
@@ -33,94 +32,43 @@ type LighthouseClient struct {
 	assignmentsCache    *lru.Cache
 	assignmentsCacheMux *sync.Mutex
 	signer              gtypes.Signer
+
+	// participationFunc retrieves the participation statistics used by GetEpochData,
+	// clients embedding the LighthouseClient can replace it with their own implementation
+	participationFunc func(epoch uint64) (*types.ValidatorParticipation, error)
+
+	// sszUnsupported is set to 1 once the node responded to a ssz request with a different encoding
+	sszUnsupported uint32
+	// sszUnsupportedFromSlot is the first slot known to be of a fork the ssz decoding does not support, 0 if none was seen
+	sszUnsupportedFromSlot uint64
+	// balancesCache holds the balances decoded from the ssz states of past days by slot
+	balancesCache *lru.Cache
+
+	httpClient *http.Client
+	// ctx is canceled when the client is closed, aborting all pending requests
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // NewLighthouseClient is used to create a new Lighthouse client
 func NewLighthouseClient(endpoint string, chainID *big.Int) (*LighthouseClient, error) {
 	signer := gtypes.NewLondonSigner(chainID)
+	ctx, cancel := context.WithCancel(context.Background())
 	client := &LighthouseClient{
 		endpoint:            endpoint,
 		assignmentsCacheMux: &sync.Mutex{},
 		signer:              signer,
+		httpClient:          &http.Client{},
+		ctx:                 ctx,
+		cancel:              cancel,
 	}
 	client.assignmentsCache, _ = lru.New(10)
+	client.balancesCache, _ = lru.New(8)
+	client.participationFunc = client.GetValidatorParticipation
 
 	return client, nil
 }
 
-func (lc *LighthouseClient) GetNewBlockChan() chan *types.Block {
-	blkCh := make(chan *types.Block, 10)
-	go func() {
-		stream, err := eventsource.Subscribe(fmt.Sprintf("%s/eth/v1/events?topics=head", lc.endpoint), "")
-
-		if err != nil {
-			logrus.Fatal(err)
-		}
-		defer stream.Close()
-
-		// for {
-		// 	select {
-		// 	case e := <-stream.Events:
-		// 		var parsed StreamedBlockEventData
-		// 		err = json.Unmarshal([]byte(e.Data()), &parsed)
-		// 		if err != nil {
-		// 			logger.Warnf("failed to decode block event: %v", err)
-		// 		} else {
-		// 			slot := uint64(parsed.Slot)
-		// 			blks, err := lc.GetBlocksBySlot(parsed.Slot)
-		// 			if err != nil {
-		// 				logger.Warnf("failed to fetch block(s) for slot %d: %v", slot, err)
-		// 				continue
-		// 			}
-		// 			for _, blk := range blks {
-		// 				blkCh <- blk
-		// 			}
-		// 		}
-		// 	}
-		// }
-		// poll sync status 2 times per slot
-		// t := time.NewTicker(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot) / 2)
-
-		// lastHeadSlot := uint64(0)
-		// headResp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/head", lc.endpoint))
-		// if err == nil {
-		// 	var parsedHead StandardBeaconHeaderResponse
-		// 	err = json.Unmarshal(headResp, &parsedHead)
-		// 	if err != nil {
-		// 		logger.Warnf("failed to decode head, starting blocks channel at slot 0")
-		// 	} else {
-		// 		lastHeadSlot = uint64(parsedHead.Data.Header.Message.Slot)
-		// 	}
-		// } else {
-		// 	logger.Warnf("failed to fetch head, starting blocks channel at slot 0")
-		// }
-
-		for {
-			e := <-stream.Events
-			// logger.Infof("retrieved %v via event stream", e.Data())
-			var parsed StreamedBlockEventData
-			err = json.Unmarshal([]byte(e.Data()), &parsed)
-			if err != nil {
-				logger.Warnf("failed to decode block event: %v", err)
-				continue
-			}
-
-			logger.Infof("retrieving data for slot %v", parsed.Slot)
-			blks, err := lc.GetBlocksBySlot(uint64(parsed.Slot))
-			if err != nil {
-				logger.Warnf("failed to fetch block(s) for slot %d: %v", uint64(parsed.Slot), err)
-				continue
-			}
-			logger.Infof("retrieved %v blocks for slot %v", len(blks), parsed.Slot)
-			for _, blk := range blks {
-				// logger.Infof("pushing block %v", blk.Slot)
-				blkCh <- blk
-			}
-		}
-	}()
-	return blkCh
-}
-
 // GetChainHead gets the chain head from Lighthouse
 func (lc *LighthouseClient) GetChainHead() (*types.ChainHead, error) {
 	headResp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/head", lc.endpoint))
@@ -293,7 +241,9 @@ func (lc *LighthouseClient) GetEpochAssignments(epoch uint64) (*types.EpochAssig
 		}
 	}
 
-	if len(assignments.AttestorAssignments) > 0 && len(assignments.ProposerAssignments) > 0 {
+	// the proposers of future epochs depend on blocks that have not been proposed yet, so the cache, which is keyed by
+	// epoch only, must not hold their assignments
+	if len(assignments.AttestorAssignments) > 0 && len(assignments.ProposerAssignments) > 0 && int64(epoch) <= utils.TimeToEpoch(time.Now()) {
 		lc.assignmentsCache.Add(epoch, assignments)
 	}
 
@@ -309,18 +259,11 @@ func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool
 	data := &types.EpochData{}
 	data.Epoch = epoch
 
-	validatorsResp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators", lc.endpoint, epoch*utils.Config.Chain.Config.SlotsPerEpoch))
+	validators, err := lc.getValidators(epoch * utils.Config.Chain.Config.SlotsPerEpoch)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving validators for epoch %v: %v", epoch, err)
 	}
 
-	var parsedValidators StandardValidatorsResponse
-	err = json.Unmarshal(validatorsResp, &parsedValidators)
-
-	if err != nil {
-		return nil, fmt.Errorf("error parsing epoch validators: %v", err)
-	}
-
 	epoch1d := int64(epoch) - 225
 	epoch7d := int64(epoch) - 225*7
 	epoch31d := int64(epoch) - 225*31
@@ -340,7 +283,7 @@ func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool
 				logrus.Errorf("error retrieving validator balances for epoch %v (1d): %v", epoch1d, err)
 				return
 			}
-			logger.Printf("retrieved data for %v validator balances for epoch %v (1d) took %v", len(parsedValidators.Data), epoch1d, time.Since(start))
+			logger.Printf("retrieved data for %v validator balances for epoch %v (1d) took %v", len(validators), epoch1d, time.Since(start))
 		}()
 		wg.Add(1)
 		go func() {
@@ -352,7 +295,7 @@ func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool
 				logrus.Errorf("error retrieving validator balances for epoch %v (7d): %v", epoch7d, err)
 				return
 			}
-			logger.Printf("retrieved data for %v validator balances for epoch %v (7d) took %v", len(parsedValidators.Data), epoch7d, time.Since(start))
+			logger.Printf("retrieved data for %v validator balances for epoch %v (7d) took %v", len(validators), epoch7d, time.Since(start))
 		}()
 		wg.Add(1)
 		go func() {
@@ -364,28 +307,16 @@ func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool
 				logrus.Errorf("error retrieving validator balances for epoch %v (31d): %v", epoch31d, err)
 				return
 			}
-			logger.Printf("retrieved data for %v validator balances for epoch %v (31d) took %v", len(parsedValidators.Data), epoch31d, time.Since(start))
+			logger.Printf("retrieved data for %v validator balances for epoch %v (31d) took %v", len(validators), epoch31d, time.Since(start))
 		}()
 		wg.Wait()
 	}
-	for _, validator := range parsedValidators.Data {
-		data.Validators = append(data.Validators, &types.Validator{
-			Index:                      uint64(validator.Index),
-			PublicKey:                  utils.MustParseHex(validator.Validator.Pubkey),
-			WithdrawalCredentials:      utils.MustParseHex(validator.Validator.WithdrawalCredentials),
-			Balance:                    uint64(validator.Balance),
-			EffectiveBalance:           uint64(validator.Validator.EffectiveBalance),
-			Slashed:                    validator.Validator.Slashed,
-			ActivationEligibilityEpoch: uint64(validator.Validator.ActivationEligibilityEpoch),
-			ActivationEpoch:            uint64(validator.Validator.ActivationEpoch),
-			ExitEpoch:                  uint64(validator.Validator.ExitEpoch),
-			WithdrawableEpoch:          uint64(validator.Validator.WithdrawableEpoch),
-			Balance1d:                  sql.NullInt64{Int64: int64(validatorBalances1d[uint64(validator.Index)]), Valid: true},
-			Balance7d:                  sql.NullInt64{Int64: int64(validatorBalances7d[uint64(validator.Index)]), Valid: true},
-			Balance31d:                 sql.NullInt64{Int64: int64(validatorBalances31d[uint64(validator.Index)]), Valid: true},
-			Status:                     validator.Status,
-		})
+	for _, validator := range validators {
+		validator.Balance1d = sql.NullInt64{Int64: int64(validatorBalances1d[validator.Index]), Valid: true}
+		validator.Balance7d = sql.NullInt64{Int64: int64(validatorBalances7d[validator.Index]), Valid: true}
+		validator.Balance31d = sql.NullInt64{Int64: int64(validatorBalances31d[validator.Index]), Valid: true}
 	}
+	data.Validators = validators
 
 	logger.Printf("retrieved data for %v validators for epoch %v", len(data.Validators), epoch)
 
@@ -404,7 +335,7 @@ func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		data.EpochParticipationStats, err = lc.GetValidatorParticipation(epoch)
+		data.EpochParticipationStats, err = lc.participationFunc(epoch)
 		if err != nil {
 			logger.Errorf("error retrieving epoch participation statistics for epoch %v: %v", epoch, err)
 			data.EpochParticipationStats = &types.ValidatorParticipation{
@@ -488,6 +419,69 @@ func (lc *LighthouseClient) GetEpochData(epoch uint64, skipHistoricBalances bool
 	return data, nil
 }
 
+// getValidators retrieves all validators of the state at slot, using ssz encoding if supported by the node
+func (lc *LighthouseClient) getValidators(slot uint64) ([]*types.Validator, error) {
+	validators, err := lc.getValidatorsSSZ(slot)
+	if err == nil {
+		return validators, nil
+	}
+	if err != errSSZUnsupported {
+		logger.Warnf("error retrieving ssz validators for state %v, falling back to json: %v", slot, err)
+	}
+
+	validatorsResp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators", lc.endpoint, slot))
+	if err != nil {
+		return nil, err
+	}
+
+	var parsedValidators StandardValidatorsResponse
+	err = json.Unmarshal(validatorsResp, &parsedValidators)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing validators: %v", err)
+	}
+
+	validators = make([]*types.Validator, 0, len(parsedValidators.Data))
+	for _, validator := range parsedValidators.Data {
+		validators = append(validators, &types.Validator{
+			Index:                      uint64(validator.Index),
+			PublicKey:                  utils.MustParseHex(validator.Validator.Pubkey),
+			WithdrawalCredentials:      utils.MustParseHex(validator.Validator.WithdrawalCredentials),
+			Balance:                    uint64(validator.Balance),
+			EffectiveBalance:           uint64(validator.Validator.EffectiveBalance),
+			Slashed:                    validator.Validator.Slashed,
+			ActivationEligibilityEpoch: uint64(validator.Validator.ActivationEligibilityEpoch),
+			ActivationEpoch:            uint64(validator.Validator.ActivationEpoch),
+			ExitEpoch:                  uint64(validator.Validator.ExitEpoch),
+			WithdrawableEpoch:          uint64(validator.Validator.WithdrawableEpoch),
+			Status:                     validator.Status,
+		})
+	}
+	return validators, nil
+}
+
+// getBlock retrieves the block with the given block id at slot, using ssz encoding if supported by the node
+func (lc *LighthouseClient) getBlock(blockID string, slot uint64) (*StandardV2BlockResponse, error) {
+	parsedResponse, err := lc.getBlockSSZ(blockID, slot)
+	if err == nil {
+		return parsedResponse, nil
+	}
+	if err != errSSZUnsupported {
+		logger.Warnf("error retrieving ssz block %v, falling back to json: %v", blockID, err)
+	}
+
+	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/blocks/%s", lc.endpoint, blockID))
+	if err != nil {
+		return nil, err
+	}
+
+	parsedResponse = &StandardV2BlockResponse{}
+	err = json.Unmarshal(resp, parsedResponse)
+	if err != nil {
+		return nil, err
+	}
+	return parsedResponse, nil
+}
+
 func uint64List(li []uint64Str) []uint64 {
 	out := make([]uint64, len(li), len(li))
 	for i, v := range li {
@@ -504,9 +498,19 @@ func (lc *LighthouseClient) getBalancesForEpoch(epoch int64) (map[uint64]uint64,
 
 	var err error
 
-	validatorBalances := make(map[uint64]uint64)
+	slot := uint64(epoch) * utils.Config.Chain.Config.SlotsPerEpoch
+	stateID := fmt.Sprintf("%d", slot)
+	validatorBalances, err := lc.getBalancesSSZ(slot)
+	if err == nil {
+		return validatorBalances, nil
+	}
+	if err != errSSZUnsupported {
+		logger.Warnf("error retrieving ssz balances for state %v, falling back to json: %v", stateID, err)
+	}
+
+	validatorBalances = make(map[uint64]uint64)
 
-	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validator_balances", lc.endpoint, epoch*int64(utils.Config.Chain.Config.SlotsPerEpoch)))
+	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%s/validator_balances", lc.endpoint, stateID))
 	if err != nil {
 		return validatorBalances, err
 	}
@@ -541,19 +545,13 @@ func (lc *LighthouseClient) GetBlockByBlockroot(blockroot []byte) (*types.Block,
 
 	slot := uint64(parsedHeaders.Data.Header.Message.Slot)
 
-	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/blocks/%s", lc.endpoint, parsedHeaders.Data.Root))
-	if err != nil {
-		return nil, fmt.Errorf("error retrieving block data at slot %v: %v", slot, err)
-	}
-
-	var parsedResponse StandardV2BlockResponse
-	err = json.Unmarshal(resp, &parsedResponse)
+	parsedResponse, err := lc.getBlock(parsedHeaders.Data.Root, uint64(parsedHeaders.Data.Header.Message.Slot))
 	if err != nil {
 		logger.Errorf("error parsing block data at slot %v: %v", parsedHeaders.Data.Header.Message.Slot, err)
 		return nil, fmt.Errorf("error parsing block-response at slot %v: %v", slot, err)
 	}
 
-	return lc.blockFromResponse(&parsedHeaders, &parsedResponse)
+	return lc.blockFromResponse(&parsedHeaders, parsedResponse)
 }
 
 // GetBlocksBySlot will get the blocks by slot from Lighthouse RPC api
@@ -572,25 +570,58 @@ func (lc *LighthouseClient) GetBlocksBySlot(slot uint64) ([]*types.Block, error)
 		return nil, fmt.Errorf("error parsing header-response at slot %v: %v", slot, err)
 	}
 
-	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/blocks/%s", lc.endpoint, parsedHeaders.Data.Root))
-	if err != nil {
-		return nil, fmt.Errorf("error retrieving block data at slot %v: %v", slot, err)
-	}
-
-	var parsedResponse StandardV2BlockResponse
-	err = json.Unmarshal(resp, &parsedResponse)
+	parsedResponse, err := lc.getBlock(parsedHeaders.Data.Root, uint64(parsedHeaders.Data.Header.Message.Slot))
 	if err != nil {
 		logger.Errorf("error parsing block data at slot %v: %v", slot, err)
 		return nil, fmt.Errorf("error parsing block-response at slot %v: %v", slot, err)
 	}
 
-	block, err := lc.blockFromResponse(&parsedHeaders, &parsedResponse)
+	block, err := lc.blockFromResponse(&parsedHeaders, parsedResponse)
 	if err != nil {
 		return nil, err
 	}
 	return []*types.Block{block}, nil
 }
 
+// getBlobSidecars retrieves the blob sidecar metadata of a block. Sidecars are pruned by the beacon node after the
+// retention period, in which case only the commitments of the block are returned.
+func (lc *LighthouseClient) getBlobSidecars(blockID string, commitments [][]byte) ([]*types.BlobSidecar, error) {
+	sidecars := make([]*types.BlobSidecar, len(commitments))
+	for i, commitment := range commitments {
+		sidecars[i] = &types.BlobSidecar{
+			Index:             uint64(i),
+			KzgCommitment:     commitment,
+			BlobVersionedHash: utils.KzgCommitmentToVersionedHash(commitment),
+		}
+	}
+
+	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/blob_sidecars/%s", lc.endpoint, blockID))
+	if err != nil {
+		if err == notFoundErr {
+			logger.Warnf("no blob sidecars available for block %v", blockID)
+			return sidecars, nil
+		}
+		return nil, fmt.Errorf("error retrieving blob sidecars of block %v: %v", blockID, err)
+	}
+
+	var parsedResp StandardBlobSidecarsResponse
+	err = json.Unmarshal(resp, &parsedResp)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing blob sidecars of block %v: %v", blockID, err)
+	}
+
+	for _, sidecar := range parsedResp.Data {
+		if uint64(sidecar.Index) >= uint64(len(sidecars)) {
+			return nil, fmt.Errorf("blob sidecar index %v of block %v is out of range", sidecar.Index, blockID)
+		}
+		if !bytes.Equal(sidecar.KzgCommitment, sidecars[sidecar.Index].KzgCommitment) {
+			return nil, fmt.Errorf("kzg commitment of blob sidecar %v does not match commitment of block %v", sidecar.Index, blockID)
+		}
+		sidecars[sidecar.Index].KzgProof = sidecar.KzgProof
+	}
+	return sidecars, nil
+}
+
 func (lc *LighthouseClient) blockFromResponse(parsedHeaders *StandardBeaconHeaderResponse, parsedResponse *StandardV2BlockResponse) (*types.Block, error) {
 	parsedBlock := parsedResponse.Data
 	slot := uint64(parsedHeaders.Data.Header.Message.Slot)
@@ -610,11 +641,12 @@ func (lc *LighthouseClient) blockFromResponse(parsedHeaders *StandardBeaconHeade
 			DepositCount: uint64(parsedBlock.Message.Body.Eth1Data.DepositCount),
 			BlockHash:    utils.MustParseHex(parsedBlock.Message.Body.Eth1Data.BlockHash),
 		},
-		ProposerSlashings: make([]*types.ProposerSlashing, len(parsedBlock.Message.Body.ProposerSlashings)),
-		AttesterSlashings: make([]*types.AttesterSlashing, len(parsedBlock.Message.Body.AttesterSlashings)),
-		Attestations:      make([]*types.Attestation, len(parsedBlock.Message.Body.Attestations)),
-		Deposits:          make([]*types.Deposit, len(parsedBlock.Message.Body.Deposits)),
-		VoluntaryExits:    make([]*types.VoluntaryExit, len(parsedBlock.Message.Body.VoluntaryExits)),
+		ProposerSlashings:          make([]*types.ProposerSlashing, len(parsedBlock.Message.Body.ProposerSlashings)),
+		AttesterSlashings:          make([]*types.AttesterSlashing, len(parsedBlock.Message.Body.AttesterSlashings)),
+		Attestations:               make([]*types.Attestation, len(parsedBlock.Message.Body.Attestations)),
+		Deposits:                   make([]*types.Deposit, len(parsedBlock.Message.Body.Deposits)),
+		VoluntaryExits:             make([]*types.VoluntaryExit, len(parsedBlock.Message.Body.VoluntaryExits)),
+		SignedBLSToExecutionChange: make([]*types.SignedBLSToExecutionChange, len(parsedBlock.Message.Body.SignedBLSToExecutionChange)),
 	}
 
 	epochAssignments, err := lc.GetEpochAssignments(slot / utils.Config.Chain.Config.SlotsPerEpoch)
@@ -668,6 +700,17 @@ func (lc *LighthouseClient) blockFromResponse(parsedHeaders *StandardBeaconHeade
 			}
 			txs = append(txs, tx)
 		}
+		withdrawals := make([]*types.Withdrawal, 0, len(payload.Withdrawals))
+		for _, w := range payload.Withdrawals {
+			withdrawals = append(withdrawals, &types.Withdrawal{
+				Slot:           slot,
+				BlockRoot:      block.BlockRoot,
+				Index:          uint64(w.Index),
+				ValidatorIndex: uint64(w.ValidatorIndex),
+				Address:        w.Address,
+				Amount:         uint64(w.Amount),
+			})
+		}
 		block.ExecutionPayload = &types.ExecutionPayload{
 			ParentHash:    payload.ParentHash,
 			FeeRecipient:  payload.FeeRecipient,
@@ -683,6 +726,9 @@ func (lc *LighthouseClient) blockFromResponse(parsedHeaders *StandardBeaconHeade
 			BaseFeePerGas: uint64(payload.BaseFeePerGas),
 			BlockHash:     payload.BlockHash,
 			Transactions:  txs,
+			Withdrawals:   withdrawals,
+			BlobGasUsed:   uint64(payload.BlobGasUsed),
+			ExcessBlobGas: uint64(payload.ExcessBlobGas),
 		}
 	}
 
@@ -691,6 +737,28 @@ func (lc *LighthouseClient) blockFromResponse(parsedHeaders *StandardBeaconHeade
 		block.Eth1Data.DepositCount = 0
 	}
 
+	if len(parsedBlock.Message.Body.BlobKZGCommitments) > 0 {
+		block.BlobKZGCommitments = make([][]byte, len(parsedBlock.Message.Body.BlobKZGCommitments))
+		for i, commitment := range parsedBlock.Message.Body.BlobKZGCommitments {
+			block.BlobKZGCommitments[i] = commitment
+		}
+		block.BlobSidecars, err = lc.getBlobSidecars(parsedHeaders.Data.Root, block.BlobKZGCommitments)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, blsChange := range parsedBlock.Message.Body.SignedBLSToExecutionChange {
+		block.SignedBLSToExecutionChange[i] = &types.SignedBLSToExecutionChange{
+			Message: types.BLSToExecutionChange{
+				Validatorindex: uint64(blsChange.Message.ValidatorIndex),
+				BlsPubkey:      blsChange.Message.FromBlsPubkey,
+				Address:        blsChange.Message.ToExecutionAddress,
+			},
+			Signature: blsChange.Signature,
+		}
+	}
+
 	for i, proposerSlashing := range parsedBlock.Message.Body.ProposerSlashings {
 		block.ProposerSlashings[i] = &types.ProposerSlashing{
 			ProposerIndex: uint64(proposerSlashing.SignedHeader1.Message.ProposerIndex),
@@ -712,42 +780,7 @@ func (lc *LighthouseClient) blockFromResponse(parsedHeaders *StandardBeaconHeade
 	}
 
 	for i, attesterSlashing := range parsedBlock.Message.Body.AttesterSlashings {
-		block.AttesterSlashings[i] = &types.AttesterSlashing{
-			Attestation1: &types.IndexedAttestation{
-				Data: &types.AttestationData{
-					Slot:            uint64(attesterSlashing.Attestation1.Data.Slot),
-					CommitteeIndex:  uint64(attesterSlashing.Attestation1.Data.Index),
-					BeaconBlockRoot: utils.MustParseHex(attesterSlashing.Attestation1.Data.BeaconBlockRoot),
-					Source: &types.Checkpoint{
-						Epoch: uint64(attesterSlashing.Attestation1.Data.Source.Epoch),
-						Root:  utils.MustParseHex(attesterSlashing.Attestation1.Data.Source.Root),
-					},
-					Target: &types.Checkpoint{
-						Epoch: uint64(attesterSlashing.Attestation1.Data.Target.Epoch),
-						Root:  utils.MustParseHex(attesterSlashing.Attestation1.Data.Target.Root),
-					},
-				},
-				Signature:        utils.MustParseHex(attesterSlashing.Attestation1.Signature),
-				AttestingIndices: uint64List(attesterSlashing.Attestation1.AttestingIndices),
-			},
-			Attestation2: &types.IndexedAttestation{
-				Data: &types.AttestationData{
-					Slot:            uint64(attesterSlashing.Attestation2.Data.Slot),
-					CommitteeIndex:  uint64(attesterSlashing.Attestation2.Data.Index),
-					BeaconBlockRoot: utils.MustParseHex(attesterSlashing.Attestation2.Data.BeaconBlockRoot),
-					Source: &types.Checkpoint{
-						Epoch: uint64(attesterSlashing.Attestation2.Data.Source.Epoch),
-						Root:  utils.MustParseHex(attesterSlashing.Attestation2.Data.Source.Root),
-					},
-					Target: &types.Checkpoint{
-						Epoch: uint64(attesterSlashing.Attestation2.Data.Target.Epoch),
-						Root:  utils.MustParseHex(attesterSlashing.Attestation2.Data.Target.Root),
-					},
-				},
-				Signature:        utils.MustParseHex(attesterSlashing.Attestation2.Signature),
-				AttestingIndices: uint64List(attesterSlashing.Attestation2.AttestingIndices),
-			},
-		}
+		block.AttesterSlashings[i] = attesterSlashingFromResponse(&attesterSlashing)
 	}
 
 	for i, attestation := range parsedBlock.Message.Body.Attestations {
@@ -813,6 +846,45 @@ func (lc *LighthouseClient) blockFromResponse(parsedHeaders *StandardBeaconHeade
 	return block, nil
 }
 
+func attesterSlashingFromResponse(attesterSlashing *AttesterSlashing) *types.AttesterSlashing {
+	return &types.AttesterSlashing{
+		Attestation1: &types.IndexedAttestation{
+			Data: &types.AttestationData{
+				Slot:            uint64(attesterSlashing.Attestation1.Data.Slot),
+				CommitteeIndex:  uint64(attesterSlashing.Attestation1.Data.Index),
+				BeaconBlockRoot: utils.MustParseHex(attesterSlashing.Attestation1.Data.BeaconBlockRoot),
+				Source: &types.Checkpoint{
+					Epoch: uint64(attesterSlashing.Attestation1.Data.Source.Epoch),
+					Root:  utils.MustParseHex(attesterSlashing.Attestation1.Data.Source.Root),
+				},
+				Target: &types.Checkpoint{
+					Epoch: uint64(attesterSlashing.Attestation1.Data.Target.Epoch),
+					Root:  utils.MustParseHex(attesterSlashing.Attestation1.Data.Target.Root),
+				},
+			},
+			Signature:        utils.MustParseHex(attesterSlashing.Attestation1.Signature),
+			AttestingIndices: uint64List(attesterSlashing.Attestation1.AttestingIndices),
+		},
+		Attestation2: &types.IndexedAttestation{
+			Data: &types.AttestationData{
+				Slot:            uint64(attesterSlashing.Attestation2.Data.Slot),
+				CommitteeIndex:  uint64(attesterSlashing.Attestation2.Data.Index),
+				BeaconBlockRoot: utils.MustParseHex(attesterSlashing.Attestation2.Data.BeaconBlockRoot),
+				Source: &types.Checkpoint{
+					Epoch: uint64(attesterSlashing.Attestation2.Data.Source.Epoch),
+					Root:  utils.MustParseHex(attesterSlashing.Attestation2.Data.Source.Root),
+				},
+				Target: &types.Checkpoint{
+					Epoch: uint64(attesterSlashing.Attestation2.Data.Target.Epoch),
+					Root:  utils.MustParseHex(attesterSlashing.Attestation2.Data.Target.Root),
+				},
+			},
+			Signature:        utils.MustParseHex(attesterSlashing.Attestation2.Signature),
+			AttestingIndices: uint64List(attesterSlashing.Attestation2.AttestingIndices),
+		},
+	}
+}
+
 func syncCommitteeParticipation(bits []byte) float64 {
 	participating := 0
 	for i := 0; i < int(utils.Config.Chain.Config.SyncCommitteeSize); i++ {
@@ -907,29 +979,109 @@ func (lc *LighthouseClient) GetSyncCommittee(stateID string, epoch uint64) (*Sta
 	return &parsedSyncCommittees.Data, nil
 }
 
-var notFoundErr = errors.New("not found 404")
+// GetSyncStatus retrieves the sync status of the node
+func (lc *LighthouseClient) GetSyncStatus() (*types.NodeSyncStatus, error) {
+	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/node/syncing", lc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sync status: %v", err)
+	}
+	var parsedResponse StandardSyncingResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sync status: %v", err)
+	}
+	return &types.NodeSyncStatus{
+		IsSyncing:    parsedResponse.Data.IsSyncing,
+		HeadSlot:     uint64(parsedResponse.Data.HeadSlot),
+		SyncDistance: uint64(parsedResponse.Data.SyncDistance),
+	}, nil
+}
 
-func (lc *LighthouseClient) get(url string) ([]byte, error) {
-	// t0 := time.Now()
-	// defer func() { fmt.Println(url, time.Since(t0)) }()
-	client := &http.Client{Timeout: time.Second * 120}
-	resp, err := client.Get(url)
+// GetBlockRootBySlot retrieves the root of the canonical block at the given slot, returning nil if the slot is empty
+func (lc *LighthouseClient) GetBlockRootBySlot(slot uint64) ([]byte, error) {
+	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/blocks/%d/root", lc.endpoint, slot))
 	if err != nil {
-		return nil, err
+		if err == notFoundErr {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving block root at slot %v: %v", slot, err)
 	}
+	var parsedResponse StandardV1BlockRootResponse
+	err = json.Unmarshal(resp, &parsedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing block root at slot %v: %v", slot, err)
+	}
+	return utils.MustParseHex(parsedResponse.Data.Root), nil
+}
 
-	defer resp.Body.Close()
+// GetOperationPool retrieves the slashings and voluntary exits pending in the operation pool of the node
+func (lc *LighthouseClient) GetOperationPool() (*types.OperationPool, error) {
+	pool := &types.OperationPool{}
 
-	data, err := ioutil.ReadAll(resp.Body)
+	resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/pool/proposer_slashings", lc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending proposer slashings: %v", err)
+	}
+	var parsedProposerSlashings StandardProposerSlashingsPoolResponse
+	err = json.Unmarshal(resp, &parsedProposerSlashings)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing pending proposer slashings: %v", err)
+	}
+	for _, s := range parsedProposerSlashings.Data {
+		pool.ProposerSlashings = append(pool.ProposerSlashings, uint64(s.SignedHeader1.Message.ProposerIndex))
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusNotFound {
-			return nil, notFoundErr
-		}
-		return nil, fmt.Errorf("error-response: %s", data)
+	resp, err = lc.get(fmt.Sprintf("%s/eth/v1/beacon/pool/attester_slashings", lc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending attester slashings: %v", err)
+	}
+	var parsedAttesterSlashings StandardAttesterSlashingsPoolResponse
+	err = json.Unmarshal(resp, &parsedAttesterSlashings)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing pending attester slashings: %v", err)
 	}
+	for i := range parsedAttesterSlashings.Data {
+		pool.AttesterSlashings = append(pool.AttesterSlashings, attesterSlashingFromResponse(&parsedAttesterSlashings.Data[i]))
+	}
+
+	resp, err = lc.get(fmt.Sprintf("%s/eth/v1/beacon/pool/voluntary_exits", lc.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending voluntary exits: %v", err)
+	}
+	var parsedVoluntaryExits StandardVoluntaryExitsPoolResponse
+	err = json.Unmarshal(resp, &parsedVoluntaryExits)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing pending voluntary exits: %v", err)
+	}
+	for _, e := range parsedVoluntaryExits.Data {
+		pool.VoluntaryExits = append(pool.VoluntaryExits, &types.VoluntaryExit{
+			Epoch:          uint64(e.Message.Epoch),
+			ValidatorIndex: uint64(e.Message.ValidatorIndex),
+			Signature:      utils.MustParseHex(e.Signature),
+		})
+	}
+
+	return pool, nil
+}
+
+var notFoundErr = errors.New("not found 404")
+
+func (lc *LighthouseClient) get(url string) ([]byte, error) {
+	return lc.getWithContext(lc.ctx, url)
+}
+
+// post sends a json encoded body to the url and returns the response data
+func (lc *LighthouseClient) post(url string, body interface{}) ([]byte, error) {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return lc.postWithContext(lc.ctx, url, reqBody)
+}
 
-	return data, err
+// Close cancels all pending requests of the client
+func (lc *LighthouseClient) Close() {
+	lc.cancel()
 }
 
 type bytesHexStr []byte
@@ -953,6 +1105,20 @@ func (s *uint64Str) UnmarshalJSON(b []byte) error {
 	return Uint64Unmarshal((*uint64)(s), b)
 }
 
+type int64Str int64
+
+func (s *int64Str) UnmarshalJSON(b []byte) error {
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		b = b[1 : len(b)-1]
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return err
+	}
+	*s = int64Str(n)
+	return nil
+}
+
 // Parse a uint64, with or without quotes, in any base, with common prefixes accepted to change base.
 func Uint64Unmarshal(v *uint64, b []byte) error {
 	if v == nil {
@@ -1009,12 +1175,6 @@ type StandardFinalityCheckpointsResponse struct {
 	} `json:"data"`
 }
 
-type StreamedBlockEventData struct {
-	Slot                uint64Str `json:"slot"`
-	Block               string    `json:"block"`
-	ExecutionOptimistic bool      `json:"execution_optimistic"`
-}
-
 type StandardProposerDuty struct {
 	Pubkey         string    `json:"pubkey"`
 	ValidatorIndex uint64Str `json:"validator_index"`
@@ -1179,6 +1339,31 @@ type ExecutionPayload struct {
 	BaseFeePerGas uint64Str     `json:"base_fee_per_gas"`
 	BlockHash     bytesHexStr   `json:"block_hash"`
 	Transactions  []bytesHexStr `json:"transactions"`
+
+	// present only after capella
+	Withdrawals []WithdrawalPayload `json:"withdrawals,omitempty"`
+
+	// present only after deneb
+	BlobGasUsed   uint64Str `json:"blob_gas_used"`
+	ExcessBlobGas uint64Str `json:"excess_blob_gas"`
+}
+
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/capella/beacon-chain.md#withdrawal
+type WithdrawalPayload struct {
+	Index          uint64Str   `json:"index"`
+	ValidatorIndex uint64Str   `json:"validator_index"`
+	Address        bytesHexStr `json:"address"`
+	Amount         uint64Str   `json:"amount"`
+}
+
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/capella/beacon-chain.md#signedblstoexecutionchange
+type SignedBLSToExecutionChange struct {
+	Message struct {
+		ValidatorIndex     uint64Str   `json:"validator_index"`
+		FromBlsPubkey      bytesHexStr `json:"from_bls_pubkey"`
+		ToExecutionAddress bytesHexStr `json:"to_execution_address"`
+	} `json:"message"`
+	Signature bytesHexStr `json:"signature"`
 }
 
 type AnySignedBlock struct {
@@ -1202,6 +1387,12 @@ type AnySignedBlock struct {
 
 			// not present in phase0/altair blocks
 			ExecutionPayload *ExecutionPayload `json:"execution_payload"`
+
+			// present only after capella
+			SignedBLSToExecutionChange []*SignedBLSToExecutionChange `json:"bls_to_execution_changes"`
+
+			// present only after deneb
+			BlobKZGCommitments []bytesHexStr `json:"blob_kzg_commitments"`
 		} `json:"body"`
 	} `json:"message"`
 	Signature bytesHexStr `json:"signature"`
@@ -1212,6 +1403,15 @@ type StandardV2BlockResponse struct {
 	Data    AnySignedBlock `json:"data"`
 }
 
+// https://ethereum.github.io/beacon-APIs/#/Beacon/getBlobSidecars
+type StandardBlobSidecarsResponse struct {
+	Data []struct {
+		Index         uint64Str   `json:"index"`
+		KzgCommitment bytesHexStr `json:"kzg_commitment"`
+		KzgProof      bytesHexStr `json:"kzg_proof"`
+	} `json:"data"`
+}
+
 type StandardV1BlockRootResponse struct {
 	Data struct {
 		Root string `json:"root"`
@@ -1243,6 +1443,18 @@ func (pc *LighthouseClient) GetBlockStatusByEpoch(epoch uint64) ([]*types.CanonB
 	return blocks, nil
 }
 
+type StandardProposerSlashingsPoolResponse struct {
+	Data []ProposerSlashing `json:"data"`
+}
+
+type StandardAttesterSlashingsPoolResponse struct {
+	Data []AttesterSlashing `json:"data"`
+}
+
+type StandardVoluntaryExitsPoolResponse struct {
+	Data []VoluntaryExit `json:"data"`
+}
+
 type StandardSyncingResponse struct {
 	Data struct {
 		IsSyncing    bool      `json:"is_syncing"`
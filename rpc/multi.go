@@ -0,0 +1,389 @@
+package rpc
+
+import (
+	"bytes"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// MultiClientMaxHeadLag is the number of slots a node may lag behind the best known head before it is considered unhealthy
+var MultiClientMaxHeadLag uint64 = 4
+
+// MultiClient wraps several beacon nodes, automatically failing over to the next healthy node if the active node is
+// unavailable. If a quorum greater than one is configured, the block roots of an epoch are cross-checked between
+// the nodes before the epoch data is returned.
+type MultiClient struct {
+	nodes  []*multiClientNode
+	quorum int
+
+	activeMux *sync.RWMutex
+	active    int
+
+	eventsMux  *sync.Mutex
+	events     chan types.BeaconEvent
+	seenEvents *lru.Cache
+}
+
+type multiClientNode struct {
+	endpoint string
+	client   Client
+
+	healthy    bool
+	headSlot   uint64
+	subscribed bool
+}
+
+// NewMultiClient is used to create a new client wrapping the clients of the given endpoints, the order of the
+// endpoints determines their priority
+func NewMultiClient(endpoints []string, clients []Client, quorum int) (*MultiClient, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("at least one client is required")
+	}
+	if len(endpoints) != len(clients) {
+		return nil, fmt.Errorf("number of endpoints (%v) does not match number of clients (%v)", len(endpoints), len(clients))
+	}
+	if quorum > len(clients) {
+		return nil, fmt.Errorf("quorum of %v can not be reached with %v clients", quorum, len(clients))
+	}
+
+	mc := &MultiClient{
+		nodes:     make([]*multiClientNode, len(clients)),
+		quorum:    quorum,
+		activeMux: &sync.RWMutex{},
+		eventsMux: &sync.Mutex{},
+	}
+	for i, client := range clients {
+		// assume all nodes to be healthy until the first health check is done
+		mc.nodes[i] = &multiClientNode{endpoint: endpoints[i], client: client, healthy: true}
+	}
+
+	mc.checkHealth()
+	go mc.healthChecker()
+
+	return mc, nil
+}
+
+func (mc *MultiClient) healthChecker() {
+	for {
+		time.Sleep(time.Second * time.Duration(utils.Config.Chain.Config.SecondsPerSlot))
+		mc.checkHealth()
+		mc.subscribeEvents()
+	}
+}
+
+// checkHealth updates the health of all nodes and selects the healthy node with the highest priority as active node
+func (mc *MultiClient) checkHealth() {
+	type nodeStatus struct {
+		healthy  bool
+		headSlot uint64
+	}
+	statuses := make([]nodeStatus, len(mc.nodes))
+
+	wg := &sync.WaitGroup{}
+	for i, node := range mc.nodes {
+		wg.Add(1)
+		go func(i int, node *multiClientNode) {
+			defer wg.Done()
+			head, err := node.client.GetChainHead()
+			if err != nil {
+				logger.Warnf("beacon-node %v seems to be unavailable: %v", node.endpoint, err)
+				return
+			}
+			if syncClient, ok := node.client.(SyncStatusClient); ok {
+				status, err := syncClient.GetSyncStatus()
+				if err != nil {
+					logger.Warnf("error retrieving sync status of beacon-node %v: %v", node.endpoint, err)
+					return
+				}
+				if status.IsSyncing {
+					logger.Warnf("beacon-node %v is syncing, sync distance: %v", node.endpoint, status.SyncDistance)
+					return
+				}
+			}
+			statuses[i] = nodeStatus{healthy: true, headSlot: head.HeadSlot}
+		}(i, node)
+	}
+	wg.Wait()
+
+	bestHeadSlot := uint64(0)
+	for _, status := range statuses {
+		if status.healthy && status.headSlot > bestHeadSlot {
+			bestHeadSlot = status.headSlot
+		}
+	}
+
+	mc.activeMux.Lock()
+	defer mc.activeMux.Unlock()
+
+	active := -1
+	for i, node := range mc.nodes {
+		node.healthy = statuses[i].healthy && statuses[i].headSlot+MultiClientMaxHeadLag >= bestHeadSlot
+		node.headSlot = statuses[i].headSlot
+		if node.healthy && active == -1 {
+			active = i
+		}
+	}
+
+	if active == -1 {
+		logger.Errorf("no healthy beacon-node available, keeping %v as active node", mc.nodes[mc.active].endpoint)
+		return
+	}
+	if active != mc.active {
+		logger.Infof("switching active beacon-node from %v to %v", mc.nodes[mc.active].endpoint, mc.nodes[active].endpoint)
+		mc.active = active
+	}
+}
+
+// orderedNodes returns the active node followed by all other healthy nodes and finally the unhealthy nodes
+func (mc *MultiClient) orderedNodes() []*multiClientNode {
+	mc.activeMux.RLock()
+	defer mc.activeMux.RUnlock()
+
+	nodes := make([]*multiClientNode, 0, len(mc.nodes))
+	nodes = append(nodes, mc.nodes[mc.active])
+	for i, node := range mc.nodes {
+		if i != mc.active && node.healthy {
+			nodes = append(nodes, node)
+		}
+	}
+	for i, node := range mc.nodes {
+		if i != mc.active && !node.healthy {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (mc *MultiClient) healthyNodes() []*multiClientNode {
+	mc.activeMux.RLock()
+	defer mc.activeMux.RUnlock()
+
+	nodes := make([]*multiClientNode, 0, len(mc.nodes))
+	for _, node := range mc.nodes {
+		if node.healthy {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// do executes f with the active node, failing over to the other nodes if the call fails
+func (mc *MultiClient) do(name string, f func(client Client) error) error {
+	var err error
+	for _, node := range mc.orderedNodes() {
+		err = f(node.client)
+		if err == nil {
+			return nil
+		}
+		logger.Warnf("error calling %v on beacon-node %v: %v", name, node.endpoint, err)
+	}
+	return err
+}
+
+func (mc *MultiClient) GetChainHead() (*types.ChainHead, error) {
+	var res *types.ChainHead
+	err := mc.do("GetChainHead", func(client Client) (err error) {
+		res, err = client.GetChainHead()
+		return err
+	})
+	return res, err
+}
+
+func (mc *MultiClient) GetEpochData(epoch uint64, skipHistoricBalances bool) (*types.EpochData, error) {
+	var res *types.EpochData
+	err := mc.do("GetEpochData", func(client Client) (err error) {
+		res, err = client.GetEpochData(epoch, skipHistoricBalances)
+		if err != nil {
+			return err
+		}
+		return mc.verifyEpochData(client, res)
+	})
+	return res, err
+}
+
+// verifyEpochData checks that the last canonical block root of the epoch data is confirmed by enough nodes to
+// reach the configured quorum. As every block commits to its parent, agreement on the last block of the epoch
+// implies agreement on all previous blocks.
+func (mc *MultiClient) verifyEpochData(source Client, data *types.EpochData) error {
+	if mc.quorum <= 1 {
+		return nil
+	}
+
+	lastSlot := uint64(0)
+	var lastRoot []byte
+	for slot, blocks := range data.Blocks {
+		for _, block := range blocks {
+			if block.Status == 1 && block.Canonical && (lastRoot == nil || slot > lastSlot) {
+				lastSlot = slot
+				lastRoot = block.BlockRoot
+			}
+		}
+	}
+	if lastRoot == nil {
+		return nil
+	}
+
+	confirmations := 1
+	for _, node := range mc.orderedNodes() {
+		if confirmations >= mc.quorum {
+			break
+		}
+		if node.client == source {
+			continue
+		}
+		root, err := getBlockRootBySlot(node.client, lastSlot)
+		if err != nil {
+			logger.Warnf("error retrieving block root at slot %v from beacon-node %v: %v", lastSlot, node.endpoint, err)
+			continue
+		}
+		if !bytes.Equal(root, lastRoot) {
+			logger.Warnf("block root mismatch at slot %v: beacon-node %v reports %#x, expected %#x", lastSlot, node.endpoint, root, lastRoot)
+			continue
+		}
+		confirmations++
+	}
+
+	if confirmations < mc.quorum {
+		return fmt.Errorf("block root %#x of epoch %v at slot %v was confirmed by %v nodes, quorum is %v", lastRoot, data.Epoch, lastSlot, confirmations, mc.quorum)
+	}
+	return nil
+}
+
+// GetValidatorRewards retrieves the validator rewards of an epoch from the first node supporting the rewards api
+func (mc *MultiClient) GetValidatorRewards(epoch uint64) (map[uint64]*types.ValidatorEpochRewards, error) {
+	var res map[uint64]*types.ValidatorEpochRewards
+	err := mc.do("GetValidatorRewards", func(client Client) (err error) {
+		rewardsClient, ok := client.(RewardsClient)
+		if !ok {
+			return fmt.Errorf("client does not support the rewards api")
+		}
+		res, err = rewardsClient.GetValidatorRewards(epoch)
+		return err
+	})
+	return res, err
+}
+
+func getBlockRootBySlot(client Client, slot uint64) ([]byte, error) {
+	if rootClient, ok := client.(BlockRootClient); ok {
+		return rootClient.GetBlockRootBySlot(slot)
+	}
+	blocks, err := client.GetBlocksBySlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		if block.Canonical {
+			return block.BlockRoot, nil
+		}
+	}
+	return nil, nil
+}
+
+func (mc *MultiClient) GetValidatorQueue() (*types.ValidatorQueue, error) {
+	var res *types.ValidatorQueue
+	err := mc.do("GetValidatorQueue", func(client Client) (err error) {
+		res, err = client.GetValidatorQueue()
+		return err
+	})
+	return res, err
+}
+
+func (mc *MultiClient) GetEpochAssignments(epoch uint64) (*types.EpochAssignments, error) {
+	var res *types.EpochAssignments
+	err := mc.do("GetEpochAssignments", func(client Client) (err error) {
+		res, err = client.GetEpochAssignments(epoch)
+		return err
+	})
+	return res, err
+}
+
+func (mc *MultiClient) GetBlocksBySlot(slot uint64) ([]*types.Block, error) {
+	var res []*types.Block
+	err := mc.do("GetBlocksBySlot", func(client Client) (err error) {
+		res, err = client.GetBlocksBySlot(slot)
+		return err
+	})
+	return res, err
+}
+
+func (mc *MultiClient) GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error) {
+	var res *types.ValidatorParticipation
+	err := mc.do("GetValidatorParticipation", func(client Client) (err error) {
+		res, err = client.GetValidatorParticipation(epoch)
+		return err
+	})
+	return res, err
+}
+
+// GetEventChan subscribes to the event streams of all healthy nodes and forwards every event exactly once. Nodes that
+// become healthy later are subscribed by the health checker.
+func (mc *MultiClient) GetEventChan() chan types.BeaconEvent {
+	mc.eventsMux.Lock()
+	if mc.events == nil {
+		mc.events = make(chan types.BeaconEvent, 100)
+		mc.seenEvents, _ = lru.New(1000)
+	}
+	evCh := mc.events
+	mc.eventsMux.Unlock()
+
+	mc.subscribeEvents()
+	return evCh
+}
+
+// subscribeEvents forwards the event stream of every healthy node that has not been subscribed yet. A subscribed node
+// reconnects its stream on its own and is never subscribed twice.
+func (mc *MultiClient) subscribeEvents() {
+	mc.eventsMux.Lock()
+	defer mc.eventsMux.Unlock()
+
+	if mc.events == nil {
+		return
+	}
+	for _, node := range mc.healthyNodes() {
+		if node.subscribed {
+			continue
+		}
+		node.subscribed = true
+		logger.Infof("subscribing to event stream of beacon-node %v", node.endpoint)
+		go func(nodeCh chan types.BeaconEvent) {
+			for ev := range nodeCh {
+				found, _ := mc.seenEvents.ContainsOrAdd(ev.Key(), true)
+				if !found {
+					mc.events <- ev
+				}
+			}
+		}(node.client.GetEventChan())
+	}
+}
+
+func (mc *MultiClient) GetBlockStatusByEpoch(epoch uint64) ([]*types.CanonBlock, error) {
+	var res []*types.CanonBlock
+	err := mc.do("GetBlockStatusByEpoch", func(client Client) (err error) {
+		res, err = client.GetBlockStatusByEpoch(epoch)
+		return err
+	})
+	return res, err
+}
+
+func (mc *MultiClient) GetFinalityCheckpoints(epoch uint64) (*types.FinalityCheckpoints, error) {
+	var res *types.FinalityCheckpoints
+	err := mc.do("GetFinalityCheckpoints", func(client Client) (err error) {
+		res, err = client.GetFinalityCheckpoints(epoch)
+		return err
+	})
+	return res, err
+}
+
+func (mc *MultiClient) GetSyncCommittee(stateID string, epoch uint64) (*StandardSyncCommittee, error) {
+	var res *StandardSyncCommittee
+	err := mc.do("GetSyncCommittee", func(client Client) (err error) {
+		res, err = client.GetSyncCommittee(stateID, epoch)
+		return err
+	})
+	return res, err
+}
@@ -0,0 +1,343 @@
+package rpc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Fixture holds a single recorded response of a beacon node or execution client
+type Fixture struct {
+	Key    string            `json:"key"`
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   []byte            `json:"body"`
+}
+
+// recordedHeaders lists the response headers that are stored with a fixture
+var recordedHeaders = []string{"Content-Type", "Eth-Consensus-Version"}
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// FixtureRecorder is a http proxy that forwards all requests to the upstream beacon node or execution client and
+// stores the responses as fixtures, which can be served by a FixtureReplayer later on
+type FixtureRecorder struct {
+	upstream string
+	dir      string
+	client   *http.Client
+}
+
+// NewFixtureRecorder is used to create a new recorder storing the responses of upstream in dir
+func NewFixtureRecorder(upstream, dir string) (*FixtureRecorder, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating fixture directory %v: %v", dir, err)
+	}
+	return &FixtureRecorder{
+		upstream: strings.TrimSuffix(upstream, "/"),
+		dir:      dir,
+		client:   &http.Client{Timeout: time.Second * 120},
+	}, nil
+}
+
+func (fr *FixtureRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req, err := http.NewRequest(r.Method, fr.upstream+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req.Header = r.Header.Clone()
+
+	resp, err := fr.client.Do(req)
+	if err != nil {
+		logger.Errorf("error forwarding request %v to upstream: %v", r.URL.RequestURI(), err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		err = fr.recordJSONRPC(body, respBody)
+	} else {
+		fixture := &Fixture{
+			Key:    fixtureKey(r),
+			Status: resp.StatusCode,
+			Header: make(map[string]string),
+			Body:   respBody,
+		}
+		for _, h := range recordedHeaders {
+			if v := resp.Header.Get(h); v != "" {
+				fixture.Header[h] = v
+			}
+		}
+		err = writeFixture(fr.dir, fixture)
+	}
+	if err != nil {
+		logger.Errorf("error recording fixture for %v: %v", r.URL.RequestURI(), err)
+	}
+
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(respBody)
+}
+
+// recordJSONRPC stores every call of a (batched) json-rpc request as a separate fixture
+func (fr *FixtureRecorder) recordJSONRPC(reqBody, respBody []byte) error {
+	requests, _, err := parseJSONRPCRequests(reqBody)
+	if err != nil {
+		return err
+	}
+
+	var responses []jsonRPCResponse
+	if trimmed := bytes.TrimSpace(respBody); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(respBody, &responses)
+	} else {
+		responses = make([]jsonRPCResponse, 1)
+		err = json.Unmarshal(respBody, &responses[0])
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing json-rpc response: %v", err)
+	}
+
+	responsesByID := make(map[string]jsonRPCResponse, len(responses))
+	for _, resp := range responses {
+		responsesByID[string(resp.ID)] = resp
+	}
+
+	for _, req := range requests {
+		resp, found := responsesByID[string(req.ID)]
+		if !found {
+			return fmt.Errorf("no response for json-rpc call %v with id %s", req.Method, req.ID)
+		}
+		resp.ID = nil
+		body, err := json.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		err = writeFixture(fr.dir, &Fixture{Key: jsonRPCFixtureKey(req), Status: http.StatusOK, Body: body})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FixtureReplayer serves the fixtures previously stored by a FixtureRecorder, requests without a fixture are
+// answered with a 404 for beacon api requests and a json-rpc error for execution client requests
+type FixtureReplayer struct {
+	dir string
+}
+
+// NewFixtureReplayer is used to create a new replayer serving the fixtures stored in dir
+func NewFixtureReplayer(dir string) (*FixtureReplayer, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error opening fixture directory %v: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %v is not a directory", dir)
+	}
+	return &FixtureReplayer{dir: dir}, nil
+}
+
+func (fr *FixtureReplayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		fr.replayJSONRPC(w, r)
+		return
+	}
+
+	fixture, err := readFixture(fr.dir, fixtureKey(r))
+	if err != nil {
+		logger.Warnf("no fixture found for %v: %v", fixtureKey(r), err)
+		http.Error(w, fmt.Sprintf(`{"code":404,"message":"no fixture for %v"}`, r.URL.RequestURI()), http.StatusNotFound)
+		return
+	}
+
+	for k, v := range fixture.Header {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(fixture.Status)
+	w.Write(fixture.Body)
+}
+
+func (fr *FixtureReplayer) replayJSONRPC(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	requests, batch, err := parseJSONRPCRequests(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	responses := make([]jsonRPCResponse, len(requests))
+	for i, req := range requests {
+		fixture, err := readFixture(fr.dir, jsonRPCFixtureKey(req))
+		if err == nil {
+			err = json.Unmarshal(fixture.Body, &responses[i])
+		}
+		if err != nil {
+			logger.Warnf("no fixture found for json-rpc call %v: %v", req.Method, err)
+			responses[i] = jsonRPCResponse{Error: json.RawMessage(fmt.Sprintf(`{"code":-32000,"message":"no fixture for %v"}`, req.Method))}
+		}
+		responses[i].JSONRPC = "2.0"
+		responses[i].ID = req.ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		json.NewEncoder(w).Encode(responses)
+	} else {
+		json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+// StartReplayServer serves the fixtures stored in dir on a random local port and returns the endpoint of the server
+func StartReplayServer(dir string) (string, func() error, error) {
+	replayer, err := NewFixtureReplayer(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("error starting replay server: %v", err)
+	}
+
+	srv := &http.Server{Handler: replayer}
+	go srv.Serve(listener)
+
+	return "http://" + listener.Addr().String(), srv.Close, nil
+}
+
+// NewReplayClient is used to create a beacon client that is served by the fixtures stored in dir
+func NewReplayClient(dir string, chainID *big.Int) (*LighthouseClient, func() error, error) {
+	endpoint, stop, err := StartReplayServer(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := NewLighthouseClient(endpoint, chainID)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+	return client, stop, nil
+}
+
+// NewReplayErigonClient is used to create an erigon client that is served by the fixtures stored in dir
+func NewReplayErigonClient(dir string) (*ErigonClient, func() error, error) {
+	endpoint, stop, err := StartReplayServer(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := NewErigonClient(endpoint)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+	return client, stop, nil
+}
+
+// fixtureKey identifies a beacon api request by its path, query and requested encoding
+func fixtureKey(r *http.Request) string {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		accept = "application/json"
+	}
+	return fmt.Sprintf("%v %v %v", r.Method, r.URL.RequestURI(), accept)
+}
+
+// jsonRPCFixtureKey identifies a json-rpc call by its method and parameters, ignoring the call id
+func jsonRPCFixtureKey(req jsonRPCRequest) string {
+	params := &bytes.Buffer{}
+	if len(req.Params) > 0 {
+		err := json.Compact(params, req.Params)
+		if err != nil {
+			params.Reset()
+			params.Write(req.Params)
+		}
+	}
+	return fmt.Sprintf("RPC %v %v", req.Method, params.String())
+}
+
+func parseJSONRPCRequests(body []byte) ([]jsonRPCRequest, bool, error) {
+	var requests []jsonRPCRequest
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(body, &requests)
+		if err != nil {
+			return nil, true, fmt.Errorf("error parsing json-rpc batch request: %v", err)
+		}
+		return requests, true, nil
+	}
+	requests = make([]jsonRPCRequest, 1)
+	err := json.Unmarshal(body, &requests[0])
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing json-rpc request: %v", err)
+	}
+	return requests, false, nil
+}
+
+func fixturePath(dir, key string) string {
+	return filepath.Join(dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key))))
+}
+
+func writeFixture(dir string, fixture *Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fixturePath(dir, fixture.Key), data, 0644)
+}
+
+func readFixture(dir, key string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(fixturePath(dir, key))
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	err = json.Unmarshal(data, fixture)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing fixture %v: %v", key, err)
+	}
+	return fixture, nil
+}
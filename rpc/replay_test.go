@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFixtureRecordAndReplay(t *testing.T) {
+	utils.Config = &types.Config{}
+	utils.Config.Chain.Config.SlotsPerEpoch = 32
+
+	root := "0x" + fmt.Sprintf("%064x", 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			body, _ := ioutil.ReadAll(r.Body)
+			req, _, err := parseJSONRPCRequests(body)
+			if err != nil || req[0].Method != "eth_chainId" {
+				t.Errorf("unexpected json-rpc request: %s", body)
+			}
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x5"}`, req[0].ID)
+		case r.URL.Path == "/eth/v1/beacon/headers/head":
+			fmt.Fprintf(w, `{"data":{"root":"%v","canonical":true,"header":{"message":{"slot":"100","proposer_index":"1","parent_root":"%v","state_root":"%v","body_root":"%v"},"signature":"0x"}}}`, root, root, root, root)
+		case r.URL.Path == fmt.Sprintf("/eth/v1/beacon/states/%v/finality_checkpoints", root):
+			fmt.Fprintf(w, `{"data":{"previous_justified":{"epoch":"1","root":"%v"},"current_justified":{"epoch":"2","root":"%v"},"finalized":{"epoch":"1","root":"%v"}}}`, root, root, root)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	dir := t.TempDir()
+	recorder, err := NewFixtureRecorder(upstream.URL, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(recorder)
+	defer proxy.Close()
+
+	recordingClient, err := NewLighthouseClient(proxy.URL, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordedHead, err := recordingClient.GetChainHead()
+	if err != nil {
+		t.Fatalf("error retrieving chain head through recorder: %v", err)
+	}
+	recordingErigonClient, err := NewErigonClient(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chainID string
+	err = recordingErigonClient.GetRPCClient().Call(&chainID, "eth_chainId")
+	if err != nil {
+		t.Fatalf("error calling eth_chainId through recorder: %v", err)
+	}
+	upstream.Close()
+
+	replayClient, stop, err := NewReplayClient(dir, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	head, err := replayClient.GetChainHead()
+	if err != nil {
+		t.Fatalf("error retrieving replayed chain head: %v", err)
+	}
+	if head.HeadSlot != recordedHead.HeadSlot || head.HeadEpoch != 3 || head.JustifiedEpoch != 2 {
+		t.Errorf("unexpected replayed chain head: %+v", head)
+	}
+
+	_, err = replayClient.GetBlocksBySlot(1)
+	if err != nil {
+		t.Errorf("expected missing fixture to be treated as an empty slot, got: %v", err)
+	}
+
+	replayErigonClient, stopErigon, err := NewReplayErigonClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stopErigon()
+
+	var replayedChainID string
+	err = replayErigonClient.GetRPCClient().Call(&replayedChainID, "eth_chainId")
+	if err != nil {
+		t.Fatalf("error calling replayed eth_chainId: %v", err)
+	}
+	if replayedChainID != chainID {
+		t.Errorf("unexpected replayed chain id %v, expected %v", replayedChainID, chainID)
+	}
+}
+
+// the fixtures in testdata/replay are hand-written in the format of the FixtureRecorder, they describe a chain with 8
+// validators and 4 slots per epoch whose block of slot 6 is missed and the execution block 16 with a single transaction
+func setReplayConfig() {
+	utils.Config = &types.Config{}
+	utils.Config.Chain.GenesisTimestamp = 1606824023
+	utils.Config.Chain.Config.SlotsPerEpoch = 4
+	utils.Config.Chain.Config.SecondsPerSlot = 12
+	utils.Config.Chain.Config.AltairForkEpoch = 1 << 60
+	LighthouseLatestHeadEpoch = 0
+}
+
+func TestReplayEpochData(t *testing.T) {
+	setReplayConfig()
+
+	client, stop, err := NewReplayClient("testdata/replay", big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	data, err := client.GetEpochData(1, true)
+	if err != nil {
+		t.Fatalf("error retrieving replayed epoch data: %v", err)
+	}
+
+	if len(data.Validators) != 8 || data.Validators[3].Balance != 32000003000 {
+		t.Errorf("unexpected replayed validators: %v", len(data.Validators))
+	}
+	if data.ValidatorAssignmentes.ProposerAssignments[6] != 2 || data.ValidatorAssignmentes.AttestorAssignments[utils.FormatAttestorAssignmentKey(5, 0, 1)] != 3 {
+		t.Errorf("unexpected replayed assignments: %+v", data.ValidatorAssignmentes)
+	}
+	if data.EpochParticipationStats.GlobalParticipationRate != 0.75 {
+		t.Errorf("unexpected replayed participation rate %v", data.EpochParticipationStats.GlobalParticipationRate)
+	}
+
+	if len(data.Blocks) != 4 {
+		t.Fatalf("expected blocks for 4 slots, got %v", len(data.Blocks))
+	}
+	if missed := data.Blocks[6]["0x0"]; missed == nil || missed.Status != 2 || missed.Proposer != 2 {
+		t.Errorf("expected the block of slot 6 to be missed, got %+v", data.Blocks[6])
+	}
+	block := data.Blocks[7][fmt.Sprintf("%x", utils.MustParseHex(fmt.Sprintf("0x%064x", 7)))]
+	if block == nil || block.Proposer != 3 || len(block.Attestations) != 1 {
+		t.Fatalf("unexpected replayed block of slot 7: %+v", block)
+	}
+	if attesters := block.Attestations[0].Attesters; len(attesters) != 1 || attesters[0] != 2 {
+		t.Errorf("unexpected attesters %v of the attestation included in slot 7", attesters)
+	}
+}
+
+func TestReplayEth1Block(t *testing.T) {
+	client, stop, err := NewReplayErigonClient("testdata/replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	block, _, err := client.GetBlock(16)
+	if err != nil {
+		t.Fatalf("error retrieving replayed eth1 block: %v", err)
+	}
+
+	if block.Number != 16 || len(block.Transactions) != 1 {
+		t.Fatalf("unexpected replayed eth1 block %v with %v transactions", block.Number, len(block.Transactions))
+	}
+	tx := block.Transactions[0]
+	if fmt.Sprintf("%x", tx.From) != "2c7536e3605d9c16a7a3d7b1898e529396a65c23" || tx.Status != 1 || tx.GasUsed != 50000 {
+		t.Errorf("unexpected replayed transaction: from %x, status %v, gas used %v", tx.From, tx.Status, tx.GasUsed)
+	}
+	if len(tx.Logs) != 1 || len(tx.Itx) != 2 {
+		t.Errorf("expected 1 log and 2 internal transactions, got %v and %v", len(tx.Logs), len(tx.Itx))
+	}
+
+	// the fee recipient is the coinbase, it receives the priority fee and the internal transfer of the transaction
+	if payment := utils.ProposerPayment(block, block.Coinbase); payment.String() != "10050000000000000" {
+		t.Errorf("unexpected proposer payment %v", payment)
+	}
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"encoding/json"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+)
+
+type StandardAttestationRewardsResponse struct {
+	Data struct {
+		TotalRewards []struct {
+			ValidatorIndex uint64Str `json:"validator_index"`
+			Head           int64Str  `json:"head"`
+			Target         int64Str  `json:"target"`
+			Source         int64Str  `json:"source"`
+			InclusionDelay int64Str  `json:"inclusion_delay"`
+			Inactivity     int64Str  `json:"inactivity"`
+		} `json:"total_rewards"`
+	} `json:"data"`
+}
+
+type StandardBlockRewardsResponse struct {
+	Data struct {
+		ProposerIndex     uint64Str `json:"proposer_index"`
+		Total             int64Str  `json:"total"`
+		Attestations      int64Str  `json:"attestations"`
+		SyncAggregate     int64Str  `json:"sync_aggregate"`
+		ProposerSlashings int64Str  `json:"proposer_slashings"`
+		AttesterSlashings int64Str  `json:"attester_slashings"`
+	} `json:"data"`
+}
+
+type StandardSyncCommitteeRewardsResponse struct {
+	Data []struct {
+		ValidatorIndex uint64Str `json:"validator_index"`
+		Reward         int64Str  `json:"reward"`
+	} `json:"data"`
+}
+
+// GetValidatorRewards retrieves the attestation, block proposal and sync committee rewards of all validators for an epoch.
+// Rewards are only available once the epoch has been processed by the following epoch transition.
+func (lc *LighthouseClient) GetValidatorRewards(epoch uint64) (map[uint64]*types.ValidatorEpochRewards, error) {
+	rewards := make(map[uint64]*types.ValidatorEpochRewards)
+	validatorRewards := func(validatorIndex uint64) *types.ValidatorEpochRewards {
+		if rewards[validatorIndex] == nil {
+			rewards[validatorIndex] = &types.ValidatorEpochRewards{ValidatorIndex: validatorIndex, Epoch: epoch}
+		}
+		return rewards[validatorIndex]
+	}
+
+	// an empty list of validator ids requests the rewards of all validators
+	resp, err := lc.post(fmt.Sprintf("%s/eth/v1/beacon/rewards/attestations/%d", lc.endpoint, epoch), []string{})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving attestation rewards for epoch %v: %v", epoch, err)
+	}
+	var parsedAttestationRewards StandardAttestationRewardsResponse
+	err = json.Unmarshal(resp, &parsedAttestationRewards)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing attestation rewards for epoch %v: %v", epoch, err)
+	}
+	for _, reward := range parsedAttestationRewards.Data.TotalRewards {
+		r := validatorRewards(uint64(reward.ValidatorIndex))
+		r.AttestationHead = int64(reward.Head)
+		r.AttestationTarget = int64(reward.Target)
+		r.AttestationSource = int64(reward.Source)
+		r.AttestationInclusionDelay = int64(reward.InclusionDelay)
+		r.AttestationInactivity = int64(reward.Inactivity)
+	}
+
+	startSlot := epoch * utils.Config.Chain.Config.SlotsPerEpoch
+	endSlot := startSlot + utils.Config.Chain.Config.SlotsPerEpoch
+	for slot := startSlot; slot < endSlot; slot++ {
+		resp, err := lc.get(fmt.Sprintf("%s/eth/v1/beacon/rewards/blocks/%d", lc.endpoint, slot))
+		if err == notFoundErr {
+			// no block has been proposed in this slot
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving block rewards for slot %v: %v", slot, err)
+		}
+		var parsedBlockRewards StandardBlockRewardsResponse
+		err = json.Unmarshal(resp, &parsedBlockRewards)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing block rewards for slot %v: %v", slot, err)
+		}
+		r := validatorRewards(uint64(parsedBlockRewards.Data.ProposerIndex))
+		r.ProposerAttestationInclusion += int64(parsedBlockRewards.Data.Attestations)
+		r.ProposerSyncInclusion += int64(parsedBlockRewards.Data.SyncAggregate)
+		r.ProposerSlashingInclusion += int64(parsedBlockRewards.Data.ProposerSlashings) + int64(parsedBlockRewards.Data.AttesterSlashings)
+
+		if epoch < utils.Config.Chain.Config.AltairForkEpoch {
+			continue
+		}
+
+		resp, err = lc.post(fmt.Sprintf("%s/eth/v1/beacon/rewards/sync_committee/%d", lc.endpoint, slot), []string{})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving sync committee rewards for slot %v: %v", slot, err)
+		}
+		var parsedSyncRewards StandardSyncCommitteeRewardsResponse
+		err = json.Unmarshal(resp, &parsedSyncRewards)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sync committee rewards for slot %v: %v", slot, err)
+		}
+		for _, reward := range parsedSyncRewards.Data {
+			validatorRewards(uint64(reward.ValidatorIndex)).SyncCommittee += int64(reward.Reward)
+		}
+	}
+
+	return rewards, nil
+}
@@ -0,0 +1,367 @@
+package rpc
+
+import (
+	"errors"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/prysm/v3/proto/prysm/v1alpha1"
+)
+
+// errSSZUnsupported is returned if the node or the fork of the requested object does not support ssz encoding
+var errSSZUnsupported = errors.New("ssz encoding not supported")
+
+// getSSZ requests the url with ssz encoding and returns the response together with the consensus version of the returned object
+func (lc *LighthouseClient) getSSZ(url string) ([]byte, string, error) {
+	if atomic.LoadUint32(&lc.sszUnsupported) == 1 {
+		return nil, "", errSSZUnsupported
+	}
+
+	resp, err := lc.request(lc.ctx, http.MethodGet, url, nil, http.Header{"Accept": []string{"application/octet-stream"}})
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case resp.status == http.StatusNotFound:
+		return nil, "", notFoundErr
+	case resp.status == http.StatusNotAcceptable, resp.status == http.StatusUnsupportedMediaType:
+		lc.disableSSZ(url)
+		return nil, "", errSSZUnsupported
+	case resp.status != http.StatusOK:
+		return nil, "", fmt.Errorf("error-response: %s", resp.data)
+	case !strings.HasPrefix(resp.header.Get("Content-Type"), "application/octet-stream"):
+		// the node ignored the accept header and responded with json
+		lc.disableSSZ(url)
+		return nil, "", errSSZUnsupported
+	}
+
+	return resp.data, strings.ToLower(resp.header.Get("Eth-Consensus-Version")), nil
+}
+
+func (lc *LighthouseClient) disableSSZ(url string) {
+	if atomic.CompareAndSwapUint32(&lc.sszUnsupported, 0, 1) {
+		logger.Warnf("node does not support ssz encoded responses (requested %v), falling back to json", url)
+	}
+}
+
+// sszSupported returns whether the block or state at slot may be of a fork the ssz decoding supports. Once an object of
+// an unsupported fork has been seen, the objects of all later slots are requested as json right away instead of being
+// downloaded twice.
+func (lc *LighthouseClient) sszSupported(slot uint64) bool {
+	unsupportedFrom := atomic.LoadUint64(&lc.sszUnsupportedFromSlot)
+	return unsupportedFrom == 0 || slot < unsupportedFrom
+}
+
+// skipSSZFrom records that the object at slot is of an unsupported fork, so all later slots skip the ssz request
+func (lc *LighthouseClient) skipSSZFrom(slot uint64) {
+	for {
+		unsupportedFrom := atomic.LoadUint64(&lc.sszUnsupportedFromSlot)
+		if unsupportedFrom != 0 && unsupportedFrom <= slot {
+			return
+		}
+		if atomic.CompareAndSwapUint64(&lc.sszUnsupportedFromSlot, unsupportedFrom, slot) {
+			logger.Infof("objects from slot %v are of a fork the ssz decoding does not support, requesting them as json", slot)
+			return
+		}
+	}
+}
+
+// getBlockSSZ retrieves the block with the given block id at slot using ssz encoding
+func (lc *LighthouseClient) getBlockSSZ(blockID string, slot uint64) (*StandardV2BlockResponse, error) {
+	if !lc.sszSupported(slot) {
+		return nil, errSSZUnsupported
+	}
+	data, version, err := lc.getSSZ(fmt.Sprintf("%s/eth/v2/beacon/blocks/%s", lc.endpoint, blockID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &StandardV2BlockResponse{Version: version}
+	switch version {
+	case "phase0":
+		block := &ethpb.SignedBeaconBlock{}
+		err = block.UnmarshalSSZ(data)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ssz %v block %v: %v", version, blockID, err)
+		}
+		sszBlockToAnySignedBlock(&res.Data, uint64(block.Block.Slot), uint64(block.Block.ProposerIndex), block.Block.ParentRoot, block.Block.StateRoot, block.Block.Body, block.Signature)
+	case "altair":
+		block := &ethpb.SignedBeaconBlockAltair{}
+		err = block.UnmarshalSSZ(data)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ssz %v block %v: %v", version, blockID, err)
+		}
+		sszBlockToAnySignedBlock(&res.Data, uint64(block.Block.Slot), uint64(block.Block.ProposerIndex), block.Block.ParentRoot, block.Block.StateRoot, block.Block.Body, block.Signature)
+		res.Data.Message.Body.SyncAggregate = sszSyncAggregate(block.Block.Body.SyncAggregate)
+	case "bellatrix":
+		block := &ethpb.SignedBeaconBlockBellatrix{}
+		err = block.UnmarshalSSZ(data)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ssz %v block %v: %v", version, blockID, err)
+		}
+		sszBlockToAnySignedBlock(&res.Data, uint64(block.Block.Slot), uint64(block.Block.ProposerIndex), block.Block.ParentRoot, block.Block.StateRoot, block.Block.Body, block.Signature)
+		res.Data.Message.Body.SyncAggregate = sszSyncAggregate(block.Block.Body.SyncAggregate)
+
+		if payload := block.Block.Body.ExecutionPayload; payload != nil {
+			transactions := make([]bytesHexStr, len(payload.Transactions))
+			for i, tx := range payload.Transactions {
+				transactions[i] = tx
+			}
+			res.Data.Message.Body.ExecutionPayload = &ExecutionPayload{
+				ParentHash:    payload.ParentHash,
+				FeeRecipient:  payload.FeeRecipient,
+				StateRoot:     payload.StateRoot,
+				ReceiptsRoot:  payload.ReceiptsRoot,
+				LogsBloom:     payload.LogsBloom,
+				PrevRandao:    payload.PrevRandao,
+				BlockNumber:   uint64Str(payload.BlockNumber),
+				GasLimit:      uint64Str(payload.GasLimit),
+				GasUsed:       uint64Str(payload.GasUsed),
+				Timestamp:     uint64Str(payload.Timestamp),
+				ExtraData:     payload.ExtraData,
+				BaseFeePerGas: uint64Str(littleEndianToBig(payload.BaseFeePerGas).Uint64()),
+				BlockHash:     payload.BlockHash,
+				Transactions:  transactions,
+			}
+		}
+	case "capella", "deneb":
+		err = decodeSSZBlock(&res.Data, data, version)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding ssz %v block %v: %v", version, blockID, err)
+		}
+	default:
+		lc.skipSSZFrom(slot)
+		return nil, errSSZUnsupported
+	}
+
+	return res, nil
+}
+
+// sszBlockBody contains the fields shared by the block bodies of all forks
+type sszBlockBody interface {
+	GetRandaoReveal() []byte
+	GetEth1Data() *ethpb.Eth1Data
+	GetGraffiti() []byte
+	GetProposerSlashings() []*ethpb.ProposerSlashing
+	GetAttesterSlashings() []*ethpb.AttesterSlashing
+	GetAttestations() []*ethpb.Attestation
+	GetDeposits() []*ethpb.Deposit
+	GetVoluntaryExits() []*ethpb.SignedVoluntaryExit
+}
+
+// sszBlockToAnySignedBlock fills the json block representation with the decoded ssz block, so it can be processed by blockFromResponse
+func sszBlockToAnySignedBlock(block *AnySignedBlock, slot, proposer uint64, parentRoot, stateRoot []byte, body sszBlockBody, signature []byte) {
+	block.Signature = signature
+	block.Message.Slot = uint64Str(slot)
+	block.Message.ProposerIndex = uint64Str(proposer)
+	block.Message.ParentRoot = hexStr(parentRoot)
+	block.Message.StateRoot = hexStr(stateRoot)
+	block.Message.Body.RandaoReveal = hexStr(body.GetRandaoReveal())
+	block.Message.Body.Graffiti = hexStr(body.GetGraffiti())
+	block.Message.Body.Eth1Data = Eth1Data{
+		DepositRoot:  hexStr(body.GetEth1Data().GetDepositRoot()),
+		DepositCount: uint64Str(body.GetEth1Data().GetDepositCount()),
+		BlockHash:    hexStr(body.GetEth1Data().GetBlockHash()),
+	}
+
+	block.Message.Body.ProposerSlashings = make([]ProposerSlashing, len(body.GetProposerSlashings()))
+	for i, slashing := range body.GetProposerSlashings() {
+		s := &block.Message.Body.ProposerSlashings[i]
+		s.SignedHeader1.Message.Slot = uint64Str(slashing.Header_1.Header.Slot)
+		s.SignedHeader1.Message.ProposerIndex = uint64Str(slashing.Header_1.Header.ProposerIndex)
+		s.SignedHeader1.Message.ParentRoot = hexStr(slashing.Header_1.Header.ParentRoot)
+		s.SignedHeader1.Message.StateRoot = hexStr(slashing.Header_1.Header.StateRoot)
+		s.SignedHeader1.Message.BodyRoot = hexStr(slashing.Header_1.Header.BodyRoot)
+		s.SignedHeader1.Signature = hexStr(slashing.Header_1.Signature)
+		s.SignedHeader2.Message.Slot = uint64Str(slashing.Header_2.Header.Slot)
+		s.SignedHeader2.Message.ProposerIndex = uint64Str(slashing.Header_2.Header.ProposerIndex)
+		s.SignedHeader2.Message.ParentRoot = hexStr(slashing.Header_2.Header.ParentRoot)
+		s.SignedHeader2.Message.StateRoot = hexStr(slashing.Header_2.Header.StateRoot)
+		s.SignedHeader2.Message.BodyRoot = hexStr(slashing.Header_2.Header.BodyRoot)
+		s.SignedHeader2.Signature = hexStr(slashing.Header_2.Signature)
+	}
+
+	block.Message.Body.AttesterSlashings = make([]AttesterSlashing, len(body.GetAttesterSlashings()))
+	for i, slashing := range body.GetAttesterSlashings() {
+		s := &block.Message.Body.AttesterSlashings[i]
+		s.Attestation1.AttestingIndices = uint64StrList(slashing.Attestation_1.AttestingIndices)
+		s.Attestation1.Signature = hexStr(slashing.Attestation_1.Signature)
+		s.Attestation1.Data.Slot = uint64Str(slashing.Attestation_1.Data.Slot)
+		s.Attestation1.Data.Index = uint64Str(slashing.Attestation_1.Data.CommitteeIndex)
+		s.Attestation1.Data.BeaconBlockRoot = hexStr(slashing.Attestation_1.Data.BeaconBlockRoot)
+		s.Attestation1.Data.Source.Epoch = uint64Str(slashing.Attestation_1.Data.Source.Epoch)
+		s.Attestation1.Data.Source.Root = hexStr(slashing.Attestation_1.Data.Source.Root)
+		s.Attestation1.Data.Target.Epoch = uint64Str(slashing.Attestation_1.Data.Target.Epoch)
+		s.Attestation1.Data.Target.Root = hexStr(slashing.Attestation_1.Data.Target.Root)
+		s.Attestation2.AttestingIndices = uint64StrList(slashing.Attestation_2.AttestingIndices)
+		s.Attestation2.Signature = hexStr(slashing.Attestation_2.Signature)
+		s.Attestation2.Data.Slot = uint64Str(slashing.Attestation_2.Data.Slot)
+		s.Attestation2.Data.Index = uint64Str(slashing.Attestation_2.Data.CommitteeIndex)
+		s.Attestation2.Data.BeaconBlockRoot = hexStr(slashing.Attestation_2.Data.BeaconBlockRoot)
+		s.Attestation2.Data.Source.Epoch = uint64Str(slashing.Attestation_2.Data.Source.Epoch)
+		s.Attestation2.Data.Source.Root = hexStr(slashing.Attestation_2.Data.Source.Root)
+		s.Attestation2.Data.Target.Epoch = uint64Str(slashing.Attestation_2.Data.Target.Epoch)
+		s.Attestation2.Data.Target.Root = hexStr(slashing.Attestation_2.Data.Target.Root)
+	}
+
+	block.Message.Body.Attestations = make([]Attestation, len(body.GetAttestations()))
+	for i, attestation := range body.GetAttestations() {
+		a := &block.Message.Body.Attestations[i]
+		a.AggregationBits = hexStr(attestation.AggregationBits)
+		a.Signature = hexStr(attestation.Signature)
+		a.Data.Slot = uint64Str(attestation.Data.Slot)
+		a.Data.Index = uint64Str(attestation.Data.CommitteeIndex)
+		a.Data.BeaconBlockRoot = hexStr(attestation.Data.BeaconBlockRoot)
+		a.Data.Source.Epoch = uint64Str(attestation.Data.Source.Epoch)
+		a.Data.Source.Root = hexStr(attestation.Data.Source.Root)
+		a.Data.Target.Epoch = uint64Str(attestation.Data.Target.Epoch)
+		a.Data.Target.Root = hexStr(attestation.Data.Target.Root)
+	}
+
+	block.Message.Body.Deposits = make([]Deposit, len(body.GetDeposits()))
+	for i, deposit := range body.GetDeposits() {
+		d := &block.Message.Body.Deposits[i]
+		d.Data.Pubkey = hexStr(deposit.Data.PublicKey)
+		d.Data.WithdrawalCredentials = hexStr(deposit.Data.WithdrawalCredentials)
+		d.Data.Amount = uint64Str(deposit.Data.Amount)
+		d.Data.Signature = hexStr(deposit.Data.Signature)
+	}
+
+	block.Message.Body.VoluntaryExits = make([]VoluntaryExit, len(body.GetVoluntaryExits()))
+	for i, exit := range body.GetVoluntaryExits() {
+		e := &block.Message.Body.VoluntaryExits[i]
+		e.Message.Epoch = uint64Str(exit.Exit.Epoch)
+		e.Message.ValidatorIndex = uint64Str(exit.Exit.ValidatorIndex)
+		e.Signature = hexStr(exit.Signature)
+	}
+}
+
+func sszSyncAggregate(agg *ethpb.SyncAggregate) *SyncAggregate {
+	if agg == nil {
+		return nil
+	}
+	return &SyncAggregate{
+		SyncCommitteeBits:      hexStr(agg.SyncCommitteeBits),
+		SyncCommitteeSignature: hexStr(agg.SyncCommitteeSignature),
+	}
+}
+
+// getStateSSZ retrieves the validators and balances of the beacon state at slot using ssz encoding. The balances of
+// states at least a day old are cached, they are requested for the same epochs by every export of an epoch.
+func (lc *LighthouseClient) getStateSSZ(slot uint64) ([]*types.Validator, []uint64, error) {
+	if !lc.sszSupported(slot) {
+		return nil, nil, errSSZUnsupported
+	}
+	data, version, err := lc.getSSZ(fmt.Sprintf("%s/eth/v2/debug/beacon/states/%d", lc.endpoint, slot))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch version {
+	case "phase0", "altair", "bellatrix", "capella", "deneb":
+	default:
+		lc.skipSSZFrom(slot)
+		return nil, nil, errSSZUnsupported
+	}
+
+	validators, balances, err := decodeSSZStateValidators(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding ssz %v state %v: %v", version, slot, err)
+	}
+	if cacheableBalances(slot) {
+		lc.balancesCache.Add(slot, balances)
+	}
+	return validators, balances, nil
+}
+
+// getValidatorsSSZ retrieves all validators of the state at slot using ssz encoding
+func (lc *LighthouseClient) getValidatorsSSZ(slot uint64) ([]*types.Validator, error) {
+	validators, _, err := lc.getStateSSZ(slot)
+	return validators, err
+}
+
+// getBalancesSSZ retrieves the balances of all validators of the state at slot using ssz encoding
+func (lc *LighthouseClient) getBalancesSSZ(slot uint64) (map[uint64]uint64, error) {
+	var balances []uint64
+	if cached, found := lc.balancesCache.Get(slot); found {
+		balances = cached.([]uint64)
+	} else {
+		var err error
+		_, balances, err = lc.getStateSSZ(slot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	validatorBalances := make(map[uint64]uint64, len(balances))
+	for i, balance := range balances {
+		validatorBalances[uint64(i)] = balance
+	}
+	return validatorBalances, nil
+}
+
+// cacheableBalances reports whether the balances of the state at slot are final enough to be cached
+func cacheableBalances(slot uint64) bool {
+	dayInSlots := 24 * 60 * 60 / utils.Config.Chain.Config.SecondsPerSlot
+	return slot+dayInSlots <= utils.TimeToSlot(uint64(time.Now().Unix()))
+}
+
+// validatorStatus calculates the status of a validator as defined by the standard beacon api
+// see https://hackmd.io/ofFJ5gOmQpu1jjHilHbdQQ
+func validatorStatus(validator *types.Validator, epoch uint64) string {
+	farFutureEpoch := uint64(math.MaxUint64)
+	switch {
+	case validator.ActivationEpoch > epoch:
+		if validator.ActivationEligibilityEpoch == farFutureEpoch {
+			return "pending_initialized"
+		}
+		return "pending_queued"
+	case epoch < validator.ExitEpoch:
+		if validator.ExitEpoch == farFutureEpoch {
+			return "active_ongoing"
+		}
+		if validator.Slashed {
+			return "active_slashed"
+		}
+		return "active_exiting"
+	case epoch < validator.WithdrawableEpoch:
+		if validator.Slashed {
+			return "exited_slashed"
+		}
+		return "exited_unslashed"
+	default:
+		if validator.Balance != 0 {
+			return "withdrawal_possible"
+		}
+		return "withdrawal_done"
+	}
+}
+
+func hexStr(b []byte) string {
+	return fmt.Sprintf("0x%x", b)
+}
+
+func uint64StrList(li []uint64) []uint64Str {
+	out := make([]uint64Str, len(li))
+	for i, v := range li {
+		out[i] = uint64Str(v)
+	}
+	return out
+}
+
+// littleEndianToBig converts a little endian encoded ssz uint256 into a big.Int
+func littleEndianToBig(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
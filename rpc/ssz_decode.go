@@ -0,0 +1,355 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+
+	ethpb "github.com/prysmaticlabs/prysm/v3/proto/prysm/v1alpha1"
+)
+
+// the generated ssz code of prysm v3 ends with bellatrix, blocks of later forks and the fields of the beacon state the
+// explorer reads are decoded by the functions of this file
+// https://github.com/ethereum/consensus-specs/blob/dev/ssz/simple-serialize.md
+
+const (
+	sszOffsetSize                     = 4
+	sszSignatureSize                  = 96
+	sszProposerSlashingSize           = 416
+	sszDepositSize                    = 1240
+	sszSignedVoluntaryExitSize        = 112
+	sszWithdrawalSize                 = 44
+	sszSignedBLSToExecutionChangeSize = 172
+	sszKzgCommitmentSize              = 48
+	sszValidatorSize                  = 121
+)
+
+// decodeSSZBlock decodes a ssz encoded signed bellatrix, capella or deneb block into the json block representation
+func decodeSSZBlock(block *AnySignedBlock, data []byte, version string) error {
+	parts, err := sszContainer(data, sszOffsetSize+sszSignatureSize, 0)
+	if err != nil {
+		return err
+	}
+	block.Signature = data[sszOffsetSize : sszOffsetSize+sszSignatureSize]
+
+	// slot, proposer index, parent root, state root and the offset of the body
+	message, err := sszContainer(parts[0], 84, 80)
+	if err != nil {
+		return err
+	}
+	slot := binary.LittleEndian.Uint64(parts[0][0:8])
+	proposer := binary.LittleEndian.Uint64(parts[0][8:16])
+	parentRoot := parts[0][16:48]
+	stateRoot := parts[0][48:80]
+	body := message[0]
+
+	syncAggregateSize := int(utils.Config.Chain.Config.SyncCommitteeSize/8) + sszSignatureSize
+	// randao reveal, eth1 data and graffiti are followed by the offsets of the operations
+	operationsStart := 96 + 72 + 32
+	syncAggregateStart := operationsStart + 5*sszOffsetSize
+	payloadOffsetStart := syncAggregateStart + syncAggregateSize
+	offsetPositions := []int{operationsStart, operationsStart + 4, operationsStart + 8, operationsStart + 12, operationsStart + 16, payloadOffsetStart}
+	// capella adds the bls to execution changes and deneb the blob kzg commitments
+	switch version {
+	case "capella":
+		offsetPositions = append(offsetPositions, payloadOffsetStart+4)
+	case "deneb":
+		offsetPositions = append(offsetPositions, payloadOffsetStart+4, payloadOffsetStart+8)
+	}
+	fixedSize := offsetPositions[len(offsetPositions)-1] + sszOffsetSize
+	bodyParts, err := sszContainer(body, fixedSize, offsetPositions...)
+	if err != nil {
+		return fmt.Errorf("error decoding block body: %v", err)
+	}
+
+	decodedBody := &ethpb.BeaconBlockBodyAltair{
+		RandaoReveal: body[0:96],
+		Eth1Data: &ethpb.Eth1Data{
+			DepositRoot:  body[96:128],
+			DepositCount: binary.LittleEndian.Uint64(body[128:136]),
+			BlockHash:    body[136:168],
+		},
+		Graffiti: body[168:200],
+	}
+
+	proposerSlashings, err := sszFixedList(bodyParts[0], sszProposerSlashingSize)
+	if err != nil {
+		return fmt.Errorf("error decoding proposer slashings: %v", err)
+	}
+	for _, buf := range proposerSlashings {
+		slashing := &ethpb.ProposerSlashing{}
+		if err := slashing.UnmarshalSSZ(buf); err != nil {
+			return fmt.Errorf("error decoding proposer slashing: %v", err)
+		}
+		decodedBody.ProposerSlashings = append(decodedBody.ProposerSlashings, slashing)
+	}
+
+	attesterSlashings, err := sszVariableList(bodyParts[1])
+	if err != nil {
+		return fmt.Errorf("error decoding attester slashings: %v", err)
+	}
+	for _, buf := range attesterSlashings {
+		slashing := &ethpb.AttesterSlashing{}
+		if err := slashing.UnmarshalSSZ(buf); err != nil {
+			return fmt.Errorf("error decoding attester slashing: %v", err)
+		}
+		decodedBody.AttesterSlashings = append(decodedBody.AttesterSlashings, slashing)
+	}
+
+	attestations, err := sszVariableList(bodyParts[2])
+	if err != nil {
+		return fmt.Errorf("error decoding attestations: %v", err)
+	}
+	for _, buf := range attestations {
+		attestation := &ethpb.Attestation{}
+		if err := attestation.UnmarshalSSZ(buf); err != nil {
+			return fmt.Errorf("error decoding attestation: %v", err)
+		}
+		decodedBody.Attestations = append(decodedBody.Attestations, attestation)
+	}
+
+	deposits, err := sszFixedList(bodyParts[3], sszDepositSize)
+	if err != nil {
+		return fmt.Errorf("error decoding deposits: %v", err)
+	}
+	for _, buf := range deposits {
+		deposit := &ethpb.Deposit{}
+		if err := deposit.UnmarshalSSZ(buf); err != nil {
+			return fmt.Errorf("error decoding deposit: %v", err)
+		}
+		decodedBody.Deposits = append(decodedBody.Deposits, deposit)
+	}
+
+	exits, err := sszFixedList(bodyParts[4], sszSignedVoluntaryExitSize)
+	if err != nil {
+		return fmt.Errorf("error decoding voluntary exits: %v", err)
+	}
+	for _, buf := range exits {
+		exit := &ethpb.SignedVoluntaryExit{}
+		if err := exit.UnmarshalSSZ(buf); err != nil {
+			return fmt.Errorf("error decoding voluntary exit: %v", err)
+		}
+		decodedBody.VoluntaryExits = append(decodedBody.VoluntaryExits, exit)
+	}
+
+	sszBlockToAnySignedBlock(block, slot, proposer, parentRoot, stateRoot, decodedBody, block.Signature)
+	block.Message.Body.SyncAggregate = &SyncAggregate{
+		SyncCommitteeBits:      hexStr(body[syncAggregateStart : payloadOffsetStart-sszSignatureSize]),
+		SyncCommitteeSignature: hexStr(body[payloadOffsetStart-sszSignatureSize : payloadOffsetStart]),
+	}
+
+	block.Message.Body.ExecutionPayload, err = decodeSSZExecutionPayload(bodyParts[5], version)
+	if err != nil {
+		return fmt.Errorf("error decoding execution payload: %v", err)
+	}
+
+	if version == "bellatrix" {
+		return nil
+	}
+
+	blsChanges, err := sszFixedList(bodyParts[6], sszSignedBLSToExecutionChangeSize)
+	if err != nil {
+		return fmt.Errorf("error decoding bls to execution changes: %v", err)
+	}
+	block.Message.Body.SignedBLSToExecutionChange = make([]*SignedBLSToExecutionChange, len(blsChanges))
+	for i, buf := range blsChanges {
+		change := &SignedBLSToExecutionChange{}
+		change.Message.ValidatorIndex = uint64Str(binary.LittleEndian.Uint64(buf[0:8]))
+		change.Message.FromBlsPubkey = buf[8:56]
+		change.Message.ToExecutionAddress = buf[56:76]
+		change.Signature = buf[76:172]
+		block.Message.Body.SignedBLSToExecutionChange[i] = change
+	}
+
+	if version == "deneb" {
+		commitments, err := sszFixedList(bodyParts[7], sszKzgCommitmentSize)
+		if err != nil {
+			return fmt.Errorf("error decoding blob kzg commitments: %v", err)
+		}
+		block.Message.Body.BlobKZGCommitments = make([]bytesHexStr, len(commitments))
+		for i, commitment := range commitments {
+			block.Message.Body.BlobKZGCommitments[i] = commitment
+		}
+	}
+	return nil
+}
+
+// decodeSSZExecutionPayload decodes a ssz encoded bellatrix, capella or deneb execution payload
+func decodeSSZExecutionPayload(data []byte, version string) (*ExecutionPayload, error) {
+	// the offsets of the extra data and the transactions are at 436 and 504, capella adds the offset of the
+	// withdrawals at 508 and deneb the blob gas used and the excess blob gas
+	fixedSize := 508
+	offsetPositions := []int{436, 504}
+	switch version {
+	case "capella":
+		fixedSize = 512
+		offsetPositions = append(offsetPositions, 508)
+	case "deneb":
+		fixedSize = 528
+		offsetPositions = append(offsetPositions, 508)
+	}
+	parts, err := sszContainer(data, fixedSize, offsetPositions...)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &ExecutionPayload{
+		ParentHash:    data[0:32],
+		FeeRecipient:  data[32:52],
+		StateRoot:     data[52:84],
+		ReceiptsRoot:  data[84:116],
+		LogsBloom:     data[116:372],
+		PrevRandao:    data[372:404],
+		BlockNumber:   uint64Str(binary.LittleEndian.Uint64(data[404:412])),
+		GasLimit:      uint64Str(binary.LittleEndian.Uint64(data[412:420])),
+		GasUsed:       uint64Str(binary.LittleEndian.Uint64(data[420:428])),
+		Timestamp:     uint64Str(binary.LittleEndian.Uint64(data[428:436])),
+		ExtraData:     parts[0],
+		BaseFeePerGas: uint64Str(littleEndianToBig(data[440:472]).Uint64()),
+		BlockHash:     data[472:504],
+	}
+	if version == "deneb" {
+		payload.BlobGasUsed = uint64Str(binary.LittleEndian.Uint64(data[512:520]))
+		payload.ExcessBlobGas = uint64Str(binary.LittleEndian.Uint64(data[520:528]))
+	}
+
+	transactions, err := sszVariableList(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding transactions: %v", err)
+	}
+	payload.Transactions = make([]bytesHexStr, len(transactions))
+	for i, tx := range transactions {
+		payload.Transactions[i] = tx
+	}
+
+	if version == "bellatrix" {
+		return payload, nil
+	}
+
+	withdrawals, err := sszFixedList(parts[2], sszWithdrawalSize)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding withdrawals: %v", err)
+	}
+	payload.Withdrawals = make([]WithdrawalPayload, len(withdrawals))
+	for i, buf := range withdrawals {
+		payload.Withdrawals[i] = WithdrawalPayload{
+			Index:          uint64Str(binary.LittleEndian.Uint64(buf[0:8])),
+			ValidatorIndex: uint64Str(binary.LittleEndian.Uint64(buf[8:16])),
+			Address:        buf[16:36],
+			Amount:         uint64Str(binary.LittleEndian.Uint64(buf[36:44])),
+		}
+	}
+	return payload, nil
+}
+
+// decodeSSZStateValidators decodes the validators and their balances of a ssz encoded beacon state. The fields up to
+// the balances are the same for the states of all forks, so the rest of the state does not have to be decoded.
+func decodeSSZStateValidators(data []byte) ([]*types.Validator, []uint64, error) {
+	// genesis time, genesis validators root, slot, fork, latest block header, block roots, state roots, the offset
+	// of the historical roots, eth1 data, the offset of the eth1 data votes and the eth1 deposit index
+	validatorsOffsetPos := 264 + 64*int(utils.Config.Chain.Config.SlotsPerHistoricalRoot)
+	if len(data) < validatorsOffsetPos+2*sszOffsetSize {
+		return nil, nil, fmt.Errorf("state of %v bytes is too short", len(data))
+	}
+	slot := binary.LittleEndian.Uint64(data[40:48])
+	validatorsOffset := int(binary.LittleEndian.Uint32(data[validatorsOffsetPos:]))
+	balancesOffset := int(binary.LittleEndian.Uint32(data[validatorsOffsetPos+sszOffsetSize:]))
+	if validatorsOffset > balancesOffset || balancesOffset > len(data) {
+		return nil, nil, fmt.Errorf("invalid validators offset %v and balances offset %v", validatorsOffset, balancesOffset)
+	}
+
+	encodedValidators, err := sszFixedList(data[validatorsOffset:balancesOffset], sszValidatorSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding validators: %v", err)
+	}
+	// every validator has a balance
+	if balancesOffset+len(encodedValidators)*8 > len(data) {
+		return nil, nil, fmt.Errorf("state does not contain the balances of %v validators", len(encodedValidators))
+	}
+
+	// the keys are copied so the validators do not keep the whole state in memory
+	epoch := slot / utils.Config.Chain.Config.SlotsPerEpoch
+	validators := make([]*types.Validator, len(encodedValidators))
+	balances := make([]uint64, len(encodedValidators))
+	for i, buf := range encodedValidators {
+		balances[i] = binary.LittleEndian.Uint64(data[balancesOffset+i*8:])
+		validators[i] = &types.Validator{
+			Index:                      uint64(i),
+			PublicKey:                  append([]byte{}, buf[0:48]...),
+			WithdrawalCredentials:      append([]byte{}, buf[48:80]...),
+			Balance:                    balances[i],
+			EffectiveBalance:           binary.LittleEndian.Uint64(buf[80:88]),
+			Slashed:                    buf[88] == 1,
+			ActivationEligibilityEpoch: binary.LittleEndian.Uint64(buf[89:97]),
+			ActivationEpoch:            binary.LittleEndian.Uint64(buf[97:105]),
+			ExitEpoch:                  binary.LittleEndian.Uint64(buf[105:113]),
+			WithdrawableEpoch:          binary.LittleEndian.Uint64(buf[113:121]),
+		}
+		validators[i].Status = validatorStatus(validators[i], epoch)
+	}
+	return validators, balances, nil
+}
+
+// sszContainer checks the fixed size part of a ssz encoded container and returns its variable size fields, whose
+// offsets are stored at the given positions of the fixed size part
+func sszContainer(data []byte, fixedSize int, offsetPositions ...int) ([][]byte, error) {
+	if len(data) < fixedSize {
+		return nil, fmt.Errorf("container of %v bytes is shorter than its fixed size of %v bytes", len(data), fixedSize)
+	}
+	offsets := make([]int, len(offsetPositions))
+	for i, pos := range offsetPositions {
+		offsets[i] = int(binary.LittleEndian.Uint32(data[pos:]))
+	}
+	if len(offsets) > 0 && offsets[0] != fixedSize {
+		return nil, fmt.Errorf("first offset %v does not match the fixed size of %v bytes", offsets[0], fixedSize)
+	}
+	return sszSplit(data, offsets)
+}
+
+// sszVariableList returns the elements of a ssz encoded list of variable size elements
+func sszVariableList(data []byte) ([][]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if len(data) < sszOffsetSize {
+		return nil, fmt.Errorf("list of %v bytes is too short", len(data))
+	}
+	first := int(binary.LittleEndian.Uint32(data))
+	if first == 0 || first%sszOffsetSize != 0 || first > len(data) {
+		return nil, fmt.Errorf("invalid first offset %v of list", first)
+	}
+	offsets := make([]int, first/sszOffsetSize)
+	for i := range offsets {
+		offsets[i] = int(binary.LittleEndian.Uint32(data[i*sszOffsetSize:]))
+	}
+	return sszSplit(data, offsets)
+}
+
+// sszFixedList returns the elements of a ssz encoded list of fixed size elements
+func sszFixedList(data []byte, size int) ([][]byte, error) {
+	if len(data)%size != 0 {
+		return nil, fmt.Errorf("list of %v bytes is not a multiple of its element size %v", len(data), size)
+	}
+	elements := make([][]byte, len(data)/size)
+	for i := range elements {
+		elements[i] = data[i*size : (i+1)*size]
+	}
+	return elements, nil
+}
+
+// sszSplit splits data at the offsets, the last part ends at the end of data
+func sszSplit(data []byte, offsets []int) ([][]byte, error) {
+	parts := make([][]byte, len(offsets))
+	for i, offset := range offsets {
+		end := len(data)
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+		if offset > end || end > len(data) {
+			return nil, fmt.Errorf("invalid offset %v", offset)
+		}
+		parts[i] = data[offset:end]
+	}
+	return parts, nil
+}
@@ -0,0 +1,328 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	enginev1 "github.com/prysmaticlabs/prysm/v3/proto/engine/v1"
+	ethpb "github.com/prysmaticlabs/prysm/v3/proto/prysm/v1alpha1"
+)
+
+// sszTestVariable marks a variable size field of a container assembled by sszTestContainer
+type sszTestVariable []byte
+
+// sszTestContainer assembles a ssz container from its fields in the order of the spec, the variable size fields are
+// replaced by their offsets and appended after the fixed size part
+func sszTestContainer(fields ...interface{}) []byte {
+	fixedSize := 0
+	for _, field := range fields {
+		switch f := field.(type) {
+		case sszTestVariable:
+			fixedSize += sszOffsetSize
+		case []byte:
+			fixedSize += len(f)
+		}
+	}
+	fixed, variable := []byte{}, []byte{}
+	for _, field := range fields {
+		switch f := field.(type) {
+		case sszTestVariable:
+			offset := make([]byte, sszOffsetSize)
+			binary.LittleEndian.PutUint32(offset, uint32(fixedSize+len(variable)))
+			fixed = append(fixed, offset...)
+			variable = append(variable, f...)
+		case []byte:
+			fixed = append(fixed, f...)
+		}
+	}
+	return append(fixed, variable...)
+}
+
+func sszTestUint64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b
+}
+
+func sszTestBytes(size int, b byte) []byte {
+	return bytes.Repeat([]byte{b}, size)
+}
+
+func sszTestRoots(n int, b byte) [][]byte {
+	roots := make([][]byte, n)
+	for i := range roots {
+		roots[i] = sszTestBytes(32, b)
+	}
+	return roots
+}
+
+func sszTestMarshal(t *testing.T, objects ...interface{ MarshalSSZ() ([]byte, error) }) []byte {
+	encoded := []byte{}
+	for _, o := range objects {
+		buf, err := o.MarshalSSZ()
+		if err != nil {
+			t.Fatalf("error encoding %T: %v", o, err)
+		}
+		encoded = append(encoded, buf...)
+	}
+	return encoded
+}
+
+func sszTestBellatrixBlock() *ethpb.SignedBeaconBlockBellatrix {
+	header := func(b byte) *ethpb.SignedBeaconBlockHeader {
+		return &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{Slot: 3, ProposerIndex: 4, ParentRoot: sszTestBytes(32, b), StateRoot: sszTestBytes(32, b), BodyRoot: sszTestBytes(32, b)},
+			Signature: sszTestBytes(96, b),
+		}
+	}
+	data := &ethpb.AttestationData{
+		Slot:            99,
+		CommitteeIndex:  2,
+		BeaconBlockRoot: sszTestBytes(32, 0x11),
+		Source:          &ethpb.Checkpoint{Epoch: 1, Root: sszTestBytes(32, 0x12)},
+		Target:          &ethpb.Checkpoint{Epoch: 2, Root: sszTestBytes(32, 0x13)},
+	}
+	indexed := &ethpb.IndexedAttestation{AttestingIndices: []uint64{5, 6}, Data: data, Signature: sszTestBytes(96, 0x14)}
+
+	return &ethpb.SignedBeaconBlockBellatrix{
+		Block: &ethpb.BeaconBlockBellatrix{
+			Slot:          100,
+			ProposerIndex: 7,
+			ParentRoot:    sszTestBytes(32, 0x01),
+			StateRoot:     sszTestBytes(32, 0x02),
+			Body: &ethpb.BeaconBlockBodyBellatrix{
+				RandaoReveal:      sszTestBytes(96, 0x03),
+				Eth1Data:          &ethpb.Eth1Data{DepositRoot: sszTestBytes(32, 0x04), DepositCount: 8, BlockHash: sszTestBytes(32, 0x05)},
+				Graffiti:          sszTestBytes(32, 0x06),
+				ProposerSlashings: []*ethpb.ProposerSlashing{{Header_1: header(0x07), Header_2: header(0x08)}},
+				AttesterSlashings: []*ethpb.AttesterSlashing{{Attestation_1: indexed, Attestation_2: indexed}},
+				Attestations: []*ethpb.Attestation{
+					{AggregationBits: []byte{0x0d}, Data: data, Signature: sszTestBytes(96, 0x15)},
+					{AggregationBits: []byte{0xff, 0x01}, Data: data, Signature: sszTestBytes(96, 0x16)},
+				},
+				Deposits: []*ethpb.Deposit{{
+					Proof: sszTestRoots(33, 0x17),
+					Data:  &ethpb.Deposit_Data{PublicKey: sszTestBytes(48, 0x19), WithdrawalCredentials: sszTestBytes(32, 0x1a), Amount: 32e9, Signature: sszTestBytes(96, 0x1b)},
+				}},
+				VoluntaryExits: []*ethpb.SignedVoluntaryExit{{Exit: &ethpb.VoluntaryExit{Epoch: 9, ValidatorIndex: 10}, Signature: sszTestBytes(96, 0x1c)}},
+				SyncAggregate:  &ethpb.SyncAggregate{SyncCommitteeBits: sszTestBytes(64, 0x1d), SyncCommitteeSignature: sszTestBytes(96, 0x1e)},
+				ExecutionPayload: &enginev1.ExecutionPayload{
+					ParentHash:    sszTestBytes(32, 0x21),
+					FeeRecipient:  sszTestBytes(20, 0x22),
+					StateRoot:     sszTestBytes(32, 0x23),
+					ReceiptsRoot:  sszTestBytes(32, 0x24),
+					LogsBloom:     sszTestBytes(256, 0x25),
+					PrevRandao:    sszTestBytes(32, 0x26),
+					BlockNumber:   11,
+					GasLimit:      12,
+					GasUsed:       13,
+					Timestamp:     14,
+					ExtraData:     []byte("extra"),
+					BaseFeePerGas: append(sszTestUint64(15), sszTestBytes(24, 0)...),
+					BlockHash:     sszTestBytes(32, 0x27),
+					Transactions:  [][]byte{{0x02, 0x01}, {0x02, 0x02, 0x03}},
+				},
+			},
+		},
+		Signature: sszTestBytes(96, 0x28),
+	}
+}
+
+// sszTestCapellaBlock assembles a ssz encoded capella or deneb block with the fields of the bellatrix block, its
+// withdrawals, bls to execution changes and blob kzg commitments
+func sszTestCapellaBlock(t *testing.T, block *ethpb.SignedBeaconBlockBellatrix, version string) []byte {
+	body := block.Block.Body
+	payload := body.ExecutionPayload
+
+	variableList := func(objects ...interface{ MarshalSSZ() ([]byte, error) }) sszTestVariable {
+		fields := []interface{}{}
+		for _, o := range objects {
+			fields = append(fields, sszTestVariable(sszTestMarshal(t, o)))
+		}
+		return sszTestContainer(fields...)
+	}
+	attesterSlashings := variableList(body.AttesterSlashings[0])
+	attestations := variableList(body.Attestations[0], body.Attestations[1])
+	transactions := sszTestContainer(sszTestVariable(payload.Transactions[0]), sszTestVariable(payload.Transactions[1]))
+
+	withdrawals := []byte{}
+	for i := uint64(0); i < 2; i++ {
+		withdrawals = append(withdrawals, sszTestUint64(20+i)...)
+		withdrawals = append(withdrawals, sszTestUint64(30+i)...)
+		withdrawals = append(withdrawals, sszTestBytes(20, 0x31)...)
+		withdrawals = append(withdrawals, sszTestUint64(40+i)...)
+	}
+	payloadFields := []interface{}{
+		payload.ParentHash, payload.FeeRecipient, payload.StateRoot, payload.ReceiptsRoot, payload.LogsBloom, payload.PrevRandao,
+		sszTestUint64(payload.BlockNumber), sszTestUint64(payload.GasLimit), sszTestUint64(payload.GasUsed), sszTestUint64(payload.Timestamp),
+		sszTestVariable(payload.ExtraData), payload.BaseFeePerGas, payload.BlockHash, sszTestVariable(transactions), sszTestVariable(withdrawals),
+	}
+	blsChanges := append(append(append(sszTestUint64(50), sszTestBytes(48, 0x32)...), sszTestBytes(20, 0x33)...), sszTestBytes(96, 0x34)...)
+	bodyFields := []interface{}{
+		body.RandaoReveal, sszTestMarshal(t, body.Eth1Data), body.Graffiti,
+		sszTestVariable(sszTestMarshal(t, body.ProposerSlashings[0])), attesterSlashings, attestations,
+		sszTestVariable(sszTestMarshal(t, body.Deposits[0])), sszTestVariable(sszTestMarshal(t, body.VoluntaryExits[0])),
+		sszTestMarshal(t, body.SyncAggregate),
+	}
+	if version == "deneb" {
+		payloadFields = append(payloadFields, sszTestUint64(60), sszTestUint64(61))
+		bodyFields = append(bodyFields, sszTestVariable(sszTestContainer(payloadFields...)), sszTestVariable(blsChanges), sszTestVariable(sszTestBytes(2*48, 0x35)))
+	} else {
+		bodyFields = append(bodyFields, sszTestVariable(sszTestContainer(payloadFields...)), sszTestVariable(blsChanges))
+	}
+
+	message := sszTestContainer(sszTestUint64(uint64(block.Block.Slot)), sszTestUint64(uint64(block.Block.ProposerIndex)), block.Block.ParentRoot, block.Block.StateRoot, sszTestVariable(sszTestContainer(bodyFields...)))
+	return sszTestContainer(sszTestVariable(message), block.Signature)
+}
+
+func TestGetBlockSSZ(t *testing.T) {
+	utils.Config = &types.Config{}
+	utils.Config.Chain.Config.SlotsPerEpoch = 32
+	utils.Config.Chain.Config.SyncCommitteeSize = 512
+
+	block := sszTestBellatrixBlock()
+	bellatrix, err := block.MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded := map[string][]byte{
+		"bellatrix": bellatrix,
+		"capella":   sszTestCapellaBlock(t, block, "capella"),
+		"deneb":     sszTestCapellaBlock(t, block, "deneb"),
+		"electra":   bellatrix,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.URL.Path[len("/eth/v2/beacon/blocks/"):]
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Eth-Consensus-Version", version)
+		w.Write(encoded[version])
+	}))
+	defer server.Close()
+
+	client, err := NewLighthouseClient(server.URL, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(version string) *AnySignedBlock {
+		res, err := client.getBlockSSZ(version, 100)
+		if err != nil {
+			t.Fatalf("error retrieving %v block: %v", version, err)
+		}
+		return &res.Data
+	}
+
+	// the bellatrix block is decoded by prysm and the hand written decoding has to agree with it
+	want := get("bellatrix")
+	got := &AnySignedBlock{}
+	if err := decodeSSZBlock(got, bellatrix, "bellatrix"); err != nil {
+		t.Fatal(err)
+	}
+	sszTestCompareBlocks(t, "bellatrix", got, want)
+	if len(want.Message.Body.Attestations) != 2 || len(want.Message.Body.ExecutionPayload.Transactions) != 2 {
+		t.Fatalf("unexpected bellatrix block: %+v", want.Message.Body)
+	}
+
+	want.Message.Body.ExecutionPayload.Withdrawals = []WithdrawalPayload{
+		{Index: 20, ValidatorIndex: 30, Address: sszTestBytes(20, 0x31), Amount: 40},
+		{Index: 21, ValidatorIndex: 31, Address: sszTestBytes(20, 0x31), Amount: 41},
+	}
+	change := &SignedBLSToExecutionChange{Signature: sszTestBytes(96, 0x34)}
+	change.Message.ValidatorIndex = 50
+	change.Message.FromBlsPubkey = sszTestBytes(48, 0x32)
+	change.Message.ToExecutionAddress = sszTestBytes(20, 0x33)
+	want.Message.Body.SignedBLSToExecutionChange = []*SignedBLSToExecutionChange{change}
+	sszTestCompareBlocks(t, "capella", get("capella"), want)
+
+	want.Message.Body.ExecutionPayload.BlobGasUsed = 60
+	want.Message.Body.ExecutionPayload.ExcessBlobGas = 61
+	want.Message.Body.BlobKZGCommitments = []bytesHexStr{sszTestBytes(48, 0x35), sszTestBytes(48, 0x35)}
+	sszTestCompareBlocks(t, "deneb", get("deneb"), want)
+
+	// blocks of unknown forks and all later slots are requested as json
+	if _, err := client.getBlockSSZ("electra", 200); err != errSSZUnsupported {
+		t.Errorf("expected ssz to be unsupported for an unknown fork, got %v", err)
+	}
+	if client.sszSupported(201) || !client.sszSupported(199) {
+		t.Errorf("expected ssz to be skipped from slot 200")
+	}
+}
+
+func sszTestCompareBlocks(t *testing.T, version string, got, want *AnySignedBlock) {
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if !bytes.Equal(gotJSON, wantJSON) {
+		t.Errorf("unexpected %v block:\ngot  %s\nwant %s", version, gotJSON, wantJSON)
+	}
+}
+
+func TestDecodeSSZStateValidators(t *testing.T) {
+	utils.Config = &types.Config{}
+	utils.Config.Chain.Config.SlotsPerEpoch = 32
+	utils.Config.Chain.Config.SlotsPerHistoricalRoot = 8192
+
+	checkpoint := &ethpb.Checkpoint{Root: sszTestBytes(32, 0)}
+	syncCommittee := &ethpb.SyncCommittee{Pubkeys: make([][]byte, 512), AggregatePubkey: sszTestBytes(48, 0)}
+	for i := range syncCommittee.Pubkeys {
+		syncCommittee.Pubkeys[i] = sszTestBytes(48, 0)
+	}
+	state := &ethpb.BeaconStateBellatrix{
+		GenesisValidatorsRoot: sszTestBytes(32, 0),
+		Slot:                  160,
+		Fork:                  &ethpb.Fork{PreviousVersion: sszTestBytes(4, 0), CurrentVersion: sszTestBytes(4, 0)},
+		LatestBlockHeader:     &ethpb.BeaconBlockHeader{ParentRoot: sszTestBytes(32, 0), StateRoot: sszTestBytes(32, 0), BodyRoot: sszTestBytes(32, 0)},
+		BlockRoots:            sszTestRoots(8192, 0),
+		StateRoots:            sszTestRoots(8192, 0),
+		HistoricalRoots:       sszTestRoots(3, 0),
+		Eth1Data:              &ethpb.Eth1Data{DepositRoot: sszTestBytes(32, 0), BlockHash: sszTestBytes(32, 0)},
+		Eth1DataVotes:         []*ethpb.Eth1Data{{DepositRoot: sszTestBytes(32, 0), BlockHash: sszTestBytes(32, 0)}},
+		Validators: []*ethpb.Validator{
+			{PublicKey: sszTestBytes(48, 0x01), WithdrawalCredentials: sszTestBytes(32, 0x02), EffectiveBalance: 32e9, ActivationEpoch: 1, ExitEpoch: math.MaxUint64, WithdrawableEpoch: math.MaxUint64},
+			{PublicKey: sszTestBytes(48, 0x03), WithdrawalCredentials: sszTestBytes(32, 0x04), EffectiveBalance: 31e9, Slashed: true, ActivationEligibilityEpoch: 1, ActivationEpoch: 2, ExitEpoch: 3, WithdrawableEpoch: 4},
+		},
+		Balances:                     []uint64{32000000001, 30000000000},
+		RandaoMixes:                  sszTestRoots(65536, 0),
+		Slashings:                    make([]uint64, 8192),
+		PreviousEpochParticipation:   []byte{0, 0},
+		CurrentEpochParticipation:    []byte{0, 0},
+		JustificationBits:            []byte{0},
+		PreviousJustifiedCheckpoint:  checkpoint,
+		CurrentJustifiedCheckpoint:   checkpoint,
+		FinalizedCheckpoint:          checkpoint,
+		InactivityScores:             []uint64{0, 0},
+		CurrentSyncCommittee:         syncCommittee,
+		NextSyncCommittee:            syncCommittee,
+		LatestExecutionPayloadHeader: &enginev1.ExecutionPayloadHeader{ParentHash: sszTestBytes(32, 0), FeeRecipient: sszTestBytes(20, 0), StateRoot: sszTestBytes(32, 0), ReceiptsRoot: sszTestBytes(32, 0), LogsBloom: sszTestBytes(256, 0), PrevRandao: sszTestBytes(32, 0), BaseFeePerGas: sszTestBytes(32, 0), BlockHash: sszTestBytes(32, 0), TransactionsRoot: sszTestBytes(32, 0)},
+	}
+	encoded, err := state.MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validators, balances, err := decodeSSZStateValidators(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(validators) != 2 || len(balances) != 2 {
+		t.Fatalf("expected 2 validators and balances, got %v and %v", len(validators), len(balances))
+	}
+	for i, v := range validators {
+		expected := state.Validators[i]
+		if v.Index != uint64(i) || !bytes.Equal(v.PublicKey, expected.PublicKey) || !bytes.Equal(v.WithdrawalCredentials, expected.WithdrawalCredentials) ||
+			v.EffectiveBalance != expected.EffectiveBalance || v.Slashed != expected.Slashed || v.Balance != state.Balances[i] || balances[i] != state.Balances[i] ||
+			v.ActivationEligibilityEpoch != uint64(expected.ActivationEligibilityEpoch) || v.ActivationEpoch != uint64(expected.ActivationEpoch) ||
+			v.ExitEpoch != uint64(expected.ExitEpoch) || v.WithdrawableEpoch != uint64(expected.WithdrawableEpoch) {
+			t.Errorf("unexpected validator %v: %+v", i, v)
+		}
+	}
+	if validators[0].Status != "active_ongoing" || validators[1].Status != "withdrawal_possible" {
+		t.Errorf("unexpected validator statuses %v and %v", validators[0].Status, validators[1].Status)
+	}
+}
@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// StandardBeaconClient holds the info of a client that only relies on the standard beacon node api
+// and can therefore be used with any consensus client (Prysm, Teku, Nimbus, Lodestar, Lighthouse)
+type StandardBeaconClient struct {
+	*LighthouseClient
+}
+
+// NewStandardBeaconClient is used to create a new client for a beacon node implementing the standard beacon api
+func NewStandardBeaconClient(endpoint string, chainID *big.Int) (*StandardBeaconClient, error) {
+	lc, err := NewLighthouseClient(endpoint, chainID)
+	if err != nil {
+		return nil, err
+	}
+	client := &StandardBeaconClient{
+		LighthouseClient: lc,
+	}
+	lc.participationFunc = client.GetValidatorParticipation
+
+	return client, nil
+}
+
+// GetValidatorParticipation derives the validator participation of an epoch from the attestations included in the
+// blocks of the epoch and the following epoch. Only votes with a correct target are counted, matching the
+// target attesting gwei reported by the lighthouse validator inclusion api.
+func (sc *StandardBeaconClient) GetValidatorParticipation(epoch uint64) (*types.ValidatorParticipation, error) {
+	head, err := sc.GetChainHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if epoch > head.HeadEpoch {
+		return nil, fmt.Errorf("epoch %v is newer than the latest head %v", epoch, head.HeadEpoch)
+	}
+	if epoch == head.HeadEpoch {
+		// participation stats are calculated at the end of an epoch,
+		// making it impossible to retrieve stats of an currently ongoing epoch
+		return nil, fmt.Errorf("epoch %v can't be retrieved as it hasn't finished yet", epoch)
+	}
+
+	targetRoot, err := sc.getEpochBoundaryRoot(epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving target root for epoch %v: %v", epoch, err)
+	}
+
+	validatorsResp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/states/%d/validators?status=active_ongoing,active_exiting,active_slashed", sc.endpoint, epoch*utils.Config.Chain.Config.SlotsPerEpoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving active validators for epoch %v: %v", epoch, err)
+	}
+
+	var parsedValidators StandardValidatorsResponse
+	err = json.Unmarshal(validatorsResp, &parsedValidators)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing active validators for epoch %v: %v", epoch, err)
+	}
+
+	effectiveBalances := make(map[uint64]uint64, len(parsedValidators.Data))
+	eligibleGwei := uint64(0)
+	for _, validator := range parsedValidators.Data {
+		effectiveBalances[uint64(validator.Index)] = uint64(validator.Validator.EffectiveBalance)
+		eligibleGwei += uint64(validator.Validator.EffectiveBalance)
+	}
+
+	// votes for an epoch can be included up until the end of the following epoch
+	startSlot := epoch * utils.Config.Chain.Config.SlotsPerEpoch
+	endSlot := (epoch+2)*utils.Config.Chain.Config.SlotsPerEpoch - 1
+	if endSlot > head.HeadSlot {
+		endSlot = head.HeadSlot
+	}
+
+	mux := &sync.Mutex{}
+	attested := make(map[uint64]bool)
+
+	g := new(errgroup.Group)
+	g.SetLimit(int(utils.Config.Chain.Config.SlotsPerEpoch))
+	for slot := startSlot; slot <= endSlot; slot++ {
+		if slot == 0 {
+			continue
+		}
+		slot := slot
+		g.Go(func() error {
+			blocks, err := sc.GetBlocksBySlot(slot)
+			if err != nil {
+				return fmt.Errorf("error retrieving blocks for slot %v: %v", slot, err)
+			}
+
+			mux.Lock()
+			defer mux.Unlock()
+			for _, block := range blocks {
+				if !block.Canonical {
+					continue
+				}
+				for _, attestation := range block.Attestations {
+					if attestation.Data.Target.Epoch != epoch || !bytes.Equal(attestation.Data.Target.Root, targetRoot) {
+						continue
+					}
+					for _, validator := range attestation.Attesters {
+						attested[validator] = true
+					}
+				}
+			}
+			return nil
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	votedGwei := uint64(0)
+	for validator := range attested {
+		votedGwei += effectiveBalances[validator]
+	}
+
+	res := &types.ValidatorParticipation{
+		Epoch:         epoch,
+		VotedEther:    votedGwei,
+		EligibleEther: eligibleGwei,
+	}
+	if eligibleGwei > 0 {
+		res.GlobalParticipationRate = float32(votedGwei) / float32(eligibleGwei)
+	}
+	return res, nil
+}
+
+// getEpochBoundaryRoot returns the root of the block at the first slot of the epoch, or of the latest block before it if that slot was missed
+func (sc *StandardBeaconClient) getEpochBoundaryRoot(epoch uint64) ([]byte, error) {
+	slot := epoch * utils.Config.Chain.Config.SlotsPerEpoch
+	for {
+		resp, err := sc.get(fmt.Sprintf("%s/eth/v1/beacon/headers/%d", sc.endpoint, slot))
+		if err == notFoundErr && slot > 0 {
+			slot--
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving header at slot %v: %v", slot, err)
+		}
+
+		var parsedHeader StandardBeaconHeaderResponse
+		err = json.Unmarshal(resp, &parsedHeader)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing header at slot %v: %v", slot, err)
+		}
+		return utils.MustParseHex(parsedHeader.Data.Root), nil
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"eth2-exporter/cache"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BuilderLeaderboardPeriods are the periods in days the builder leaderboard is precomputed for
+var BuilderLeaderboardPeriods = []uint64{1, 7, 31, 180}
+
+func builderLeaderboardUpdater(wg *sync.WaitGroup) {
+	sleepDuration := time.Minute * 10
+
+	firstrun := true
+	for {
+		now := time.Now()
+		for _, days := range BuilderLeaderboardPeriods {
+			builders, err := db.GetBuilderLeaderboard(PeriodStartSlot(days))
+			if err != nil {
+				logger.Errorf("error updating builder leaderboard of the last %v days: %v", days, err)
+				continue
+			}
+
+			err = cache.TieredCache.Set(builderLeaderboardCacheKey(days), builders, time.Hour*24)
+			if err != nil {
+				logger.Errorf("error caching builder leaderboard of the last %v days: %v", days, err)
+			}
+		}
+		logger.WithField("duration", time.Since(now)).Info("builder leaderboard update completed")
+
+		if firstrun {
+			wg.Done()
+			firstrun = false
+		}
+		time.Sleep(sleepDuration)
+	}
+}
+
+func builderLeaderboardCacheKey(days uint64) string {
+	return fmt.Sprintf("%d:frontend:builderLeaderboard:%d", utils.Config.Chain.Config.DepositChainID, days)
+}
+
+// LatestBuilderLeaderboard returns the precomputed builder leaderboard of the period, nil if it is not available
+func LatestBuilderLeaderboard(days uint64) []*types.BuilderLeaderboardEntry {
+	wanted := &[]*types.BuilderLeaderboardEntry{}
+	if wanted, err := cache.TieredCache.GetWithLocalTimeout(builderLeaderboardCacheKey(days), time.Second*5, wanted); err == nil {
+		return *wanted.(*[]*types.BuilderLeaderboardEntry)
+	} else {
+		logger.Errorf("error retrieving builder leaderboard of the last %v days from cache: %v", days, err)
+	}
+	return nil
+}
+
+// PeriodStartSlot returns the first slot of a period of days ending at the latest slot
+func PeriodStartSlot(days uint64) uint64 {
+	periodInSlots := days * 24 * 60 * 60 / utils.Config.Chain.Config.SecondsPerSlot
+	latestSlot := LatestSlot()
+	if latestSlot < periodInSlots {
+		return 0
+	}
+	return latestSlot - periodInSlots
+}
@@ -39,6 +39,8 @@ var ChartHandlers = map[string]chartHandler{
 	"graffiti_wordcloud":             {14, graffitiCloudChartData},
 	"pools_distribution":             {15, poolsDistributionChartData},
 	"historic_pool_performance":      {16, historicPoolPerformanceData},
+	"blobs":                          {17, blobsChartData},
+	"late_blocks":                    {18, lateBlocksChartData},
 }
 
 // LatestChartsPageData returns the latest chart page data
@@ -335,6 +337,119 @@ func stakedEtherChartData() (*types.GenericChartData, error) {
 	return chartData, nil
 }
 
+func blobsChartData() (*types.GenericChartData, error) {
+	if LatestEpoch() == 0 {
+		return nil, fmt.Errorf("chart-data not available pre-genesis")
+	}
+
+	rows := []struct {
+		Epoch       uint64
+		BlobsCount  uint64 `db:"blobscount"`
+		BlobGasUsed uint64 `db:"blob_gas_used"`
+	}{}
+
+	err := db.ReaderDb.Select(&rows, "SELECT epoch, blobscount, blob_gas_used FROM epochs WHERE blobscount > 0 ORDER BY epoch")
+	if err != nil {
+		return nil, fmt.Errorf("error getting epoch blob data: %w", err)
+	}
+
+	dailyBlobs := [][]float64{}
+	dailyBlobGasUsed := [][]float64{}
+
+	for _, row := range rows {
+		day := float64(utils.EpochToTime(row.Epoch).Truncate(time.Hour*24).Unix() * 1000)
+
+		if len(dailyBlobs) == 0 || dailyBlobs[len(dailyBlobs)-1][0] != day {
+			dailyBlobs = append(dailyBlobs, []float64{day, float64(row.BlobsCount)})
+			dailyBlobGasUsed = append(dailyBlobGasUsed, []float64{day, float64(row.BlobGasUsed) / 1e6})
+		} else {
+			dailyBlobs[len(dailyBlobs)-1][1] += float64(row.BlobsCount)
+			dailyBlobGasUsed[len(dailyBlobGasUsed)-1][1] += float64(row.BlobGasUsed) / 1e6
+		}
+	}
+
+	chartData := &types.GenericChartData{
+		Title:                           "Blobs",
+		Subtitle:                        "Daily number of blobs and blob gas used (in millions).",
+		XAxisTitle:                      "",
+		YAxisTitle:                      "Blobs",
+		StackingMode:                    "false",
+		Type:                            "column",
+		ColumnDataGroupingApproximation: "sum",
+		Series: []*types.GenericChartDataSeries{
+			{
+				Name: "Blobs",
+				Data: dailyBlobs,
+			},
+			{
+				Name: "Blob Gas Used [M]",
+				Data: dailyBlobGasUsed,
+			},
+		},
+	}
+
+	return chartData, nil
+}
+
+func lateBlocksChartData() (*types.GenericChartData, error) {
+	if LatestEpoch() == 0 {
+		return nil, fmt.Errorf("chart-data not available pre-genesis")
+	}
+
+	rows := []struct {
+		Day           uint64
+		Blocks        uint64
+		LateBlocks    uint64  `db:"late_blocks"`
+		MedianDelayMs float64 `db:"median_delay_ms"`
+	}{}
+
+	slotsPerDay := 24 * 60 * 60 / utils.Config.Chain.Config.SecondsPerSlot
+	err := db.ReaderDb.Select(&rows, `
+		SELECT
+			slot / $1 AS day,
+			COUNT(*) AS blocks,
+			COUNT(*) FILTER (WHERE block_delay_ms > $2) AS late_blocks,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY block_delay_ms) AS median_delay_ms
+		FROM blocks_arrival
+		WHERE block_delay_ms IS NOT NULL
+		GROUP BY day
+		ORDER BY day`, slotsPerDay, utils.LateBlockThreshold().Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("error getting block arrival data: %w", err)
+	}
+
+	lateBlocksShare := [][]float64{}
+	medianDelay := [][]float64{}
+
+	for _, row := range rows {
+		day := float64(utils.SlotToTime(row.Day*slotsPerDay).Unix() * 1000)
+		lateBlocksShare = append(lateBlocksShare, []float64{day, float64(row.LateBlocks) / float64(row.Blocks) * 100})
+		medianDelay = append(medianDelay, []float64{day, row.MedianDelayMs / 1000})
+	}
+
+	chartData := &types.GenericChartData{
+		Title:                           "Late Blocks",
+		Subtitle:                        fmt.Sprintf("Daily share of blocks that arrived more than %v after the start of their slot and the median arrival delay of all blocks.", utils.LateBlockThreshold()),
+		XAxisTitle:                      "",
+		YAxisTitle:                      "Late Blocks [%]",
+		StackingMode:                    "false",
+		Type:                            "line",
+		ColumnDataGroupingApproximation: "average",
+		Series: []*types.GenericChartDataSeries{
+			{
+				Name: "Late Blocks [%]",
+				Data: lateBlocksShare,
+			},
+			{
+				Name: "Median Arrival Delay [s]",
+				Data: medianDelay,
+			},
+		},
+	}
+
+	return chartData, nil
+}
+
 func averageBalanceChartData() (*types.GenericChartData, error) {
 	if LatestEpoch() == 0 {
 		return nil, fmt.Errorf("chart-data not available pre-genesis")
@@ -259,6 +259,27 @@ func collectNotifications() map[uint64]map[types.EventName][]types.Notification
 	}
 	logger.Infof("collecting validator got slashed notifications took: %v\n", time.Since(start))
 
+	err = collectWithdrawalCredentialsChangedNotifications(notificationsByUserID)
+	if err != nil {
+		logger.Errorf("error collecting %v notifications: %v", types.ValidatorWithdrawalCredentialsChangedEventName, err)
+		metrics.Errors.WithLabelValues("notifications_collect_withdrawal_credentials_changed").Inc()
+	}
+	logger.Infof("collecting withdrawal credentials changed notifications took: %v\n", time.Since(start))
+
+	err = collectQueueEstimateShiftedNotifications(notificationsByUserID)
+	if err != nil {
+		logger.Errorf("error collecting %v notifications: %v", types.ValidatorQueueEstimateShiftedEventName, err)
+		metrics.Errors.WithLabelValues("notifications_collect_queue_estimate_shifted").Inc()
+	}
+	logger.Infof("collecting queue estimate shifted notifications took: %v\n", time.Since(start))
+
+	err = collectPotentialSlashingNotifications(notificationsByUserID)
+	if err != nil {
+		logger.Errorf("error collecting %v notifications: %v", types.ValidatorPotentialSlashingEventName, err)
+		metrics.Errors.WithLabelValues("notifications_collect_potential_slashing").Inc()
+	}
+	logger.Infof("collecting potential slashing notifications took: %v\n", time.Since(start))
+
 	// executed Proposals
 	err = collectBlockProposalNotifications(notificationsByUserID, 1, types.ValidatorExecutedProposalEventName)
 	if err != nil {
@@ -1682,6 +1703,7 @@ func collectAttestationNotifications(notificationsByUserID map[uint64]map[types.
 		Slot           uint64 `db:"attesterslot"`
 		InclusionSlot  uint64 `db:"inclusionslot"`
 		EventFilter    []byte `db:"pubkey"`
+		MissedReason   string
 	}
 
 	// get attestations for all validators for the last n epochs
@@ -1690,6 +1712,11 @@ func collectAttestationNotifications(notificationsByUserID map[uint64]map[types.
 		return fmt.Errorf("error getting validator attestations from bigtable %w", err)
 	}
 
+	err = db.ClassifyMissedAttestations(attestations)
+	if err != nil {
+		logger.Errorf("error classifying missed attestations: %v", err)
+	}
+
 	events := make([]dbResult, 0)
 	batchSize := 5000
 	dataLen := len(pubkeys)
@@ -1735,6 +1762,7 @@ func collectAttestationNotifications(notificationsByUserID map[uint64]map[types.
 						Slot:           attestation.AttesterSlot,
 						InclusionSlot:  attestation.InclusionSlot,
 						EventFilter:    pubkey,
+						MissedReason:   attestation.MissedReason,
 					})
 				}
 			}
@@ -1765,6 +1793,7 @@ func collectAttestationNotifications(notificationsByUserID map[uint64]map[types.
 				Slot:           event.Slot,
 				InclusionSlot:  event.InclusionSlot,
 				EventFilter:    hex.EncodeToString(event.EventFilter),
+				MissedReason:   event.MissedReason,
 			}
 			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
 				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
@@ -1876,6 +1905,19 @@ type validatorAttestationNotification struct {
 	InclusionSlot      uint64
 	EventFilter        string
 	UnsubscribeHash    sql.NullString
+	MissedReason       string
+}
+
+// reasonPart returns a sentence explaining why the attestation was missed, if the cause is known
+func (n *validatorAttestationNotification) reasonPart() string {
+	if n.Status != 0 {
+		return ""
+	}
+	text := utils.MissedAttestationReasonText(n.MissedReason)
+	if text == "" {
+		return ""
+	}
+	return fmt.Sprintf(" Likely cause: %v.", text)
 }
 
 func (n *validatorAttestationNotification) GetLatestState() string {
@@ -1914,7 +1956,7 @@ func (n *validatorAttestationNotification) GetInfo(includeUrl bool) string {
 			generalPart = fmt.Sprintf(`Validator %[1]v submitted a successful attestation for slot %[2]v.`, n.ValidatorIndex, n.Slot)
 		}
 	}
-	return generalPart
+	return generalPart + n.reasonPart()
 }
 
 func (n *validatorAttestationNotification) GetTitle() string {
@@ -1950,7 +1992,7 @@ func (n *validatorAttestationNotification) GetInfoMarkdown() string {
 	case 1:
 		generalPart = fmt.Sprintf(`Validator [%[1]v](https://%[3]v/validator/%[1]v) submitted a successful attestation for slot [%[2]v](https://%[3]v/slot/%[2]v).`, n.ValidatorIndex, n.Slot, utils.Config.Frontend.SiteDomain)
 	}
-	return generalPart
+	return generalPart + n.reasonPart()
 }
 
 type validatorGotSlashedNotification struct {
@@ -2081,6 +2123,482 @@ func collectValidatorGotSlashedNotifications(notificationsByUserID map[uint64]ma
 	return nil
 }
 
+type withdrawalCredentialsChangedNotification struct {
+	SubscriptionID           uint64
+	ValidatorIndex           uint64
+	Epoch                    uint64
+	OldWithdrawalCredentials []byte
+	NewWithdrawalCredentials []byte
+	EventFilter              string
+	UnsubscribeHash          sql.NullString
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetEventName() types.EventName {
+	return types.ValidatorWithdrawalCredentialsChangedEventName
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`The withdrawal credentials of validator %[1]v changed at epoch %[2]v from 0x%[3]x to 0x%[4]x.`, n.ValidatorIndex, n.Epoch, n.OldWithdrawalCredentials, n.NewWithdrawalCredentials)
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetTitle() string {
+	return "Withdrawal Credentials Changed"
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetInfoMarkdown() string {
+	generalPart := fmt.Sprintf(`The withdrawal credentials of validator [%[1]v](https://%[5]v/validator/%[1]v) changed at epoch [%[2]v](https://%[5]v/epoch/%[2]v) from `+"`0x%[3]x`"+` to `+"`0x%[4]x`"+`.`, n.ValidatorIndex, n.Epoch, n.OldWithdrawalCredentials, n.NewWithdrawalCredentials, utils.Config.Frontend.SiteDomain)
+	return generalPart
+}
+
+func collectWithdrawalCredentialsChangedNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	latestEpoch := LatestEpoch()
+	if latestEpoch == 0 {
+		return nil
+	}
+
+	// only consider the most recent epochs
+	lookBack := int64(latestEpoch) - 10
+	if lookBack < 0 {
+		lookBack = 0
+	}
+
+	dbResult, err := db.GetWithdrawalCredentialsChanged(uint64(lookBack))
+	if err != nil {
+		return fmt.Errorf("error getting withdrawal credential changes from database, err: %w", err)
+	}
+	if len(dbResult) == 0 {
+		return nil
+	}
+
+	pubkeys := make([]string, 0, len(dbResult))
+	changesByPubkey := make(map[string]*types.WithdrawalCredentialsChange, len(dbResult))
+	for _, change := range dbResult {
+		pubkey := hex.EncodeToString(change.Pubkey)
+		pubkeys = append(pubkeys, pubkey)
+		changesByPubkey[pubkey] = change
+	}
+
+	var subscribers []struct {
+		Id              uint64         `db:"id"`
+		UserId          uint64         `db:"user_id"`
+		EventFilter     string         `db:"event_filter"`
+		LastSentEpoch   sql.NullInt64  `db:"last_sent_epoch"`
+		UnsubscribeHash sql.NullString `db:"unsubscribe_hash"`
+	}
+
+	name := string(types.ValidatorWithdrawalCredentialsChangedEventName)
+	if utils.Config.Chain.Config.ConfigName != "" {
+		name = utils.Config.Chain.Config.ConfigName + ":" + name
+	}
+	err = db.FrontendWriterDB.Select(&subscribers, `
+		SELECT id, user_id, event_filter, last_sent_epoch, ENCODE(unsubscribe_hash, 'hex') AS unsubscribe_hash
+		FROM users_subscriptions
+		WHERE event_name = $1 AND event_filter = ANY($2)`,
+		name, pq.StringArray(pubkeys))
+	if err != nil {
+		return fmt.Errorf("error querying subscribers, err: %w", err)
+	}
+
+	for _, sub := range subscribers {
+		change, exists := changesByPubkey[sub.EventFilter]
+		if !exists {
+			continue
+		}
+		if sub.LastSentEpoch.Valid && uint64(sub.LastSentEpoch.Int64) >= change.Epoch {
+			// subscriber has already been notified about this change
+			continue
+		}
+		n := &withdrawalCredentialsChangedNotification{
+			SubscriptionID:           sub.Id,
+			ValidatorIndex:           change.Validatorindex,
+			Epoch:                    change.Epoch,
+			OldWithdrawalCredentials: change.OldWithdrawalCredentials,
+			NewWithdrawalCredentials: change.NewWithdrawalCredentials,
+			EventFilter:              sub.EventFilter,
+			UnsubscribeHash:          sub.UnsubscribeHash,
+		}
+
+		if _, exists := notificationsByUserID[sub.UserId]; !exists {
+			notificationsByUserID[sub.UserId] = map[types.EventName][]types.Notification{}
+		}
+		if _, exists := notificationsByUserID[sub.UserId][n.GetEventName()]; !exists {
+			notificationsByUserID[sub.UserId][n.GetEventName()] = []types.Notification{}
+		}
+		notificationsByUserID[sub.UserId][n.GetEventName()] = append(notificationsByUserID[sub.UserId][n.GetEventName()], n)
+		metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+	}
+
+	return nil
+}
+
+type queueEstimateShiftedNotification struct {
+	SubscriptionID  uint64
+	ValidatorIndex  uint64
+	Epoch           uint64
+	PreviousEpoch   uint64
+	EstimatedEpoch  uint64
+	Estimated       bool
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *queueEstimateShiftedNotification) GetLatestState() string {
+	return strconv.FormatUint(n.EstimatedEpoch, 10)
+}
+
+func (n *queueEstimateShiftedNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *queueEstimateShiftedNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *queueEstimateShiftedNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *queueEstimateShiftedNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *queueEstimateShiftedNotification) GetEventName() types.EventName {
+	return types.ValidatorQueueEstimateShiftedEventName
+}
+
+func (n *queueEstimateShiftedNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`The estimated activation of validator %[1]v shifted from epoch %[2]v to epoch %[3]v (%[4]v).`, n.ValidatorIndex, n.PreviousEpoch, n.EstimatedEpoch, utils.EpochToTime(n.EstimatedEpoch).Format(time.RFC822))
+	if !n.Estimated {
+		generalPart = fmt.Sprintf(`Validator %[1]v will be activated in epoch %[2]v (%[3]v), the previous estimate was epoch %[4]v.`, n.ValidatorIndex, n.EstimatedEpoch, utils.EpochToTime(n.EstimatedEpoch).Format(time.RFC822), n.PreviousEpoch)
+	}
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *queueEstimateShiftedNotification) GetTitle() string {
+	return "Activation Estimate Shifted"
+}
+
+func (n *queueEstimateShiftedNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *queueEstimateShiftedNotification) GetInfoMarkdown() string {
+	if !n.Estimated {
+		return fmt.Sprintf(`Validator [%[1]v](https://%[4]v/validator/%[1]v) will be activated in epoch [%[2]v](https://%[4]v/epoch/%[2]v), the previous estimate was epoch %[3]v.`, n.ValidatorIndex, n.EstimatedEpoch, n.PreviousEpoch, utils.Config.Frontend.SiteDomain)
+	}
+	return fmt.Sprintf(`The estimated activation of validator [%[1]v](https://%[4]v/validator/%[1]v) shifted from epoch %[2]v to epoch %[3]v.`, n.ValidatorIndex, n.PreviousEpoch, n.EstimatedEpoch, utils.Config.Frontend.SiteDomain)
+}
+
+// collectQueueEstimateShiftedNotifications notifies the subscribers of pending validators once the estimated activation
+// epoch has shifted by more than the configured threshold since the last notification. The first estimate of a
+// subscription is stored without notifying.
+func collectQueueEstimateShiftedNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	latestEpoch := LatestEpoch()
+	if latestEpoch == 0 {
+		return nil
+	}
+	threshold := utils.Config.Notifications.QueueEstimateShiftThreshold
+	if threshold == 0 {
+		// default to a shift of one day
+		threshold = 24 * 60 * 60 / (utils.Config.Chain.Config.SecondsPerSlot * utils.Config.Chain.Config.SlotsPerEpoch)
+	}
+
+	var subscribers []struct {
+		Id              uint64         `db:"id"`
+		UserId          uint64         `db:"user_id"`
+		EventFilter     string         `db:"event_filter"`
+		InternalState   sql.NullString `db:"internal_state"`
+		UnsubscribeHash sql.NullString `db:"unsubscribe_hash"`
+	}
+
+	name := string(types.ValidatorQueueEstimateShiftedEventName)
+	if utils.Config.Chain.Config.ConfigName != "" {
+		name = utils.Config.Chain.Config.ConfigName + ":" + name
+	}
+	err := db.FrontendWriterDB.Select(&subscribers, `
+		SELECT id, user_id, event_filter, internal_state, ENCODE(unsubscribe_hash, 'hex') AS unsubscribe_hash
+		FROM users_subscriptions
+		WHERE event_name = $1 AND created_epoch <= $2`,
+		name, latestEpoch)
+	if err != nil {
+		return fmt.Errorf("error querying subscribers, err: %w", err)
+	}
+	if len(subscribers) == 0 {
+		return nil
+	}
+
+	pubkeys := make(pq.ByteaArray, 0, len(subscribers))
+	for _, sub := range subscribers {
+		pubkey, err := hex.DecodeString(sub.EventFilter)
+		if err != nil {
+			continue
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	var pending []struct {
+		ValidatorIndex uint64 `db:"validatorindex"`
+		Pubkey         string `db:"pubkey"`
+	}
+	// only validators that are not active yet have an activation that can shift
+	err = db.ReaderDb.Select(&pending, `
+		SELECT validatorindex, ENCODE(pubkey, 'hex') AS pubkey
+		FROM validators
+		WHERE pubkey = ANY($1) AND activationepoch > $2`, pubkeys, latestEpoch)
+	if err != nil {
+		return fmt.Errorf("error retrieving pending validators, err: %w", err)
+	}
+	pendingByPubkey := make(map[string]uint64, len(pending))
+	for _, p := range pending {
+		pendingByPubkey[p.Pubkey] = p.ValidatorIndex
+	}
+
+	estimates := make(map[uint64]*types.ValidatorQueueEstimate)
+	for _, sub := range subscribers {
+		validatorIndex, exists := pendingByPubkey[sub.EventFilter]
+		if !exists {
+			continue
+		}
+		estimate, exists := estimates[validatorIndex]
+		if !exists {
+			estimate, err = GetValidatorQueueEstimate(validatorIndex)
+			if err != nil {
+				return err
+			}
+			estimates[validatorIndex] = estimate
+		}
+
+		previousEpoch, err := strconv.ParseUint(sub.InternalState.String, 10, 64)
+		if !sub.InternalState.Valid || err != nil {
+			_, err = db.FrontendWriterDB.Exec(`UPDATE users_subscriptions SET internal_state = $1 WHERE id = $2`, strconv.FormatUint(estimate.ActivationEpoch, 10), sub.Id)
+			if err != nil {
+				return fmt.Errorf("error storing initial queue estimate of subscription %v, err: %w", sub.Id, err)
+			}
+			continue
+		}
+		shift := estimate.ActivationEpoch - previousEpoch
+		if previousEpoch > estimate.ActivationEpoch {
+			shift = previousEpoch - estimate.ActivationEpoch
+		}
+		if shift < threshold {
+			continue
+		}
+
+		n := &queueEstimateShiftedNotification{
+			SubscriptionID:  sub.Id,
+			ValidatorIndex:  validatorIndex,
+			Epoch:           latestEpoch,
+			PreviousEpoch:   previousEpoch,
+			EstimatedEpoch:  estimate.ActivationEpoch,
+			Estimated:       estimate.ActivationEstimated,
+			EventFilter:     sub.EventFilter,
+			UnsubscribeHash: sub.UnsubscribeHash,
+		}
+
+		if _, exists := notificationsByUserID[sub.UserId]; !exists {
+			notificationsByUserID[sub.UserId] = map[types.EventName][]types.Notification{}
+		}
+		if _, exists := notificationsByUserID[sub.UserId][n.GetEventName()]; !exists {
+			notificationsByUserID[sub.UserId][n.GetEventName()] = []types.Notification{}
+		}
+		notificationsByUserID[sub.UserId][n.GetEventName()] = append(notificationsByUserID[sub.UserId][n.GetEventName()], n)
+		metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+	}
+
+	return nil
+}
+
+type potentialSlashingNotification struct {
+	SubscriptionID  uint64
+	ValidatorIndex  uint64
+	Epoch           uint64
+	Kind            string
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *potentialSlashingNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *potentialSlashingNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *potentialSlashingNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *potentialSlashingNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *potentialSlashingNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *potentialSlashingNotification) GetEventName() types.EventName {
+	return types.ValidatorPotentialSlashingEventName
+}
+
+func (n *potentialSlashingNotification) offence() string {
+	switch n.Kind {
+	case types.PotentialSlashingDoubleVote:
+		return "a double vote"
+	case types.PotentialSlashingSurroundVote:
+		return "a surround vote"
+	default:
+		return "a double proposal"
+	}
+}
+
+func (n *potentialSlashingNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`Validator %[1]v committed %[2]v in epoch %[3]v and will be slashed once the evidence is included in a block. Please stop all duplicate instances of the validator immediately.`, n.ValidatorIndex, n.offence(), n.Epoch)
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *potentialSlashingNotification) GetTitle() string {
+	return "Potential Slashing Detected"
+}
+
+func (n *potentialSlashingNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *potentialSlashingNotification) GetInfoMarkdown() string {
+	generalPart := fmt.Sprintf(`Validator [%[1]v](https://%[4]v/validator/%[1]v) committed %[2]v in epoch [%[3]v](https://%[4]v/epoch/%[3]v) and will be slashed once the evidence is included in a block. Please stop all duplicate instances of the validator immediately.`, n.ValidatorIndex, n.offence(), n.Epoch, utils.Config.Frontend.SiteDomain)
+	return generalPart
+}
+
+func collectPotentialSlashingNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification) error {
+	latestEpoch := LatestEpoch()
+	if latestEpoch == 0 {
+		return nil
+	}
+
+	// only consider the most recent epochs
+	lookBack := int64(latestEpoch) - 10
+	if lookBack < 0 {
+		lookBack = 0
+	}
+
+	dbResult, err := db.GetPotentialSlashings(nil, uint64(lookBack), 1000)
+	if err != nil {
+		return fmt.Errorf("error getting potential slashings from database, err: %w", err)
+	}
+	if len(dbResult) == 0 {
+		return nil
+	}
+
+	pubkeys := make([]string, 0, len(dbResult))
+	slashingsByPubkey := make(map[string][]*types.PotentialSlashing, len(dbResult))
+	for _, slashing := range dbResult {
+		if slashing.Slashed {
+			// the validator has already been slashed, the got slashed notification covers it
+			continue
+		}
+		pubkey := hex.EncodeToString(slashing.Pubkey)
+		if _, exists := slashingsByPubkey[pubkey]; !exists {
+			pubkeys = append(pubkeys, pubkey)
+		}
+		slashingsByPubkey[pubkey] = append(slashingsByPubkey[pubkey], slashing)
+	}
+	if len(pubkeys) == 0 {
+		return nil
+	}
+
+	var subscribers []struct {
+		Id              uint64         `db:"id"`
+		UserId          uint64         `db:"user_id"`
+		EventFilter     string         `db:"event_filter"`
+		LastSentEpoch   sql.NullInt64  `db:"last_sent_epoch"`
+		UnsubscribeHash sql.NullString `db:"unsubscribe_hash"`
+	}
+
+	name := string(types.ValidatorPotentialSlashingEventName)
+	if utils.Config.Chain.Config.ConfigName != "" {
+		name = utils.Config.Chain.Config.ConfigName + ":" + name
+	}
+	err = db.FrontendWriterDB.Select(&subscribers, `
+		SELECT id, user_id, event_filter, last_sent_epoch, ENCODE(unsubscribe_hash, 'hex') AS unsubscribe_hash
+		FROM users_subscriptions
+		WHERE event_name = $1 AND event_filter = ANY($2)`,
+		name, pq.StringArray(pubkeys))
+	if err != nil {
+		return fmt.Errorf("error querying subscribers, err: %w", err)
+	}
+
+	for _, sub := range subscribers {
+		for _, slashing := range slashingsByPubkey[sub.EventFilter] {
+			if sub.LastSentEpoch.Valid && uint64(sub.LastSentEpoch.Int64) >= slashing.Epoch {
+				// subscriber has already been notified about this offence
+				continue
+			}
+			n := &potentialSlashingNotification{
+				SubscriptionID:  sub.Id,
+				ValidatorIndex:  slashing.ValidatorIndex,
+				Epoch:           slashing.Epoch,
+				Kind:            slashing.Kind,
+				EventFilter:     sub.EventFilter,
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+
+			if _, exists := notificationsByUserID[sub.UserId]; !exists {
+				notificationsByUserID[sub.UserId] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[sub.UserId][n.GetEventName()]; !exists {
+				notificationsByUserID[sub.UserId][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[sub.UserId][n.GetEventName()] = append(notificationsByUserID[sub.UserId][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return nil
+}
+
 type ethClientNotification struct {
 	SubscriptionID  uint64
 	UserID          uint64
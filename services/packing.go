@@ -0,0 +1,71 @@
+package services
+
+import (
+	"eth2-exporter/cache"
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PackingLeaderboardPeriods are the periods in days the block packing leaderboard is precomputed for
+var PackingLeaderboardPeriods = []uint64{7, 31, 180}
+
+// minimum number of evaluated blocks of a proposer or staking pool to be ranked on the block packing leaderboard
+const (
+	packingLeaderboardMinProposerBlocks = 3
+	packingLeaderboardMinPoolBlocks     = 50
+	packingLeaderboardLimit             = 100
+)
+
+func packingLeaderboardUpdater(wg *sync.WaitGroup) {
+	sleepDuration := time.Minute * 10
+
+	firstrun := true
+	for {
+		now := time.Now()
+		for _, days := range PackingLeaderboardPeriods {
+			for _, byPool := range []bool{false, true} {
+				minBlocks := uint64(packingLeaderboardMinProposerBlocks)
+				if byPool {
+					minBlocks = packingLeaderboardMinPoolBlocks
+				}
+				entries, err := db.GetPackingLeaderboard(PeriodStartSlot(days), byPool, minBlocks, packingLeaderboardLimit)
+				if err != nil {
+					logger.Errorf("error updating packing leaderboard of the last %v days: %v", days, err)
+					continue
+				}
+
+				err = cache.TieredCache.Set(packingLeaderboardCacheKey(days, byPool), entries, time.Hour*24)
+				if err != nil {
+					logger.Errorf("error caching packing leaderboard of the last %v days: %v", days, err)
+				}
+			}
+		}
+		logger.WithField("duration", time.Since(now)).Info("packing leaderboard update completed")
+
+		if firstrun {
+			wg.Done()
+			firstrun = false
+		}
+		time.Sleep(sleepDuration)
+	}
+}
+
+func packingLeaderboardCacheKey(days uint64, byPool bool) string {
+	return fmt.Sprintf("%d:frontend:packingLeaderboard:%d:%t", utils.Config.Chain.Config.DepositChainID, days, byPool)
+}
+
+// LatestPackingLeaderboard returns the precomputed block packing leaderboard of the period grouped by proposer or by
+// staking pool, nil if it is not available
+func LatestPackingLeaderboard(days uint64, byPool bool) []*types.PackingLeaderboardEntry {
+	wanted := &[]*types.PackingLeaderboardEntry{}
+	if wanted, err := cache.TieredCache.GetWithLocalTimeout(packingLeaderboardCacheKey(days, byPool), time.Second*5, wanted); err == nil {
+		return *wanted.(*[]*types.PackingLeaderboardEntry)
+	} else {
+		logger.Errorf("error retrieving packing leaderboard of the last %v days from cache: %v", days, err)
+	}
+	return nil
+}
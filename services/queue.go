@@ -0,0 +1,137 @@
+package services
+
+import (
+	"eth2-exporter/db"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"fmt"
+	"sync"
+)
+
+// epochs above this value have not been assigned by the beacon chain yet
+const unassignedEpochThreshold = 100_000_000
+
+var exitQueueCache = struct {
+	sync.Mutex
+	epoch          uint64
+	length         uint64
+	lastEpoch      uint64
+	lastEpochCount uint64
+}{}
+
+// GetValidatorQueueEstimate projects the activation and exit epochs of a validator from its position in the activation
+// queue, the length of the exit queue and the current churn limit
+func GetValidatorQueueEstimate(validatorIndex uint64) (*types.ValidatorQueueEstimate, error) {
+	validator := struct {
+		ActivationEpoch   uint64 `db:"activationepoch"`
+		ExitEpoch         uint64 `db:"exitepoch"`
+		WithdrawableEpoch uint64 `db:"withdrawableepoch"`
+	}{}
+	err := db.ReaderDb.Get(&validator, `SELECT activationepoch, exitepoch, withdrawableepoch FROM validators WHERE validatorindex = $1`, validatorIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving epochs of validator %v: %v", validatorIndex, err)
+	}
+
+	churnLimit, err := currentValidatorChurnLimit()
+	if err != nil {
+		return nil, err
+	}
+	activationChurnLimit := churnLimit
+	if max := utils.Config.Chain.Config.MaxPerEpochActivationChurnLimit; max > 0 && activationChurnLimit > max {
+		activationChurnLimit = max
+	}
+
+	latestEpoch := LatestEpoch()
+	estimate := &types.ValidatorQueueEstimate{
+		ValidatorIndex:       validatorIndex,
+		ChurnLimit:           churnLimit,
+		ActivationChurnLimit: activationChurnLimit,
+		ActivationEpoch:      validator.ActivationEpoch,
+		ExitEpoch:            validator.ExitEpoch,
+		WithdrawableEpoch:    validator.WithdrawableEpoch,
+	}
+
+	if validator.ActivationEpoch > unassignedEpochThreshold {
+		queueAhead, err := db.GetQueueAheadOfValidator(validatorIndex)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving queue ahead of validator %v: %v", validatorIndex, err)
+		}
+		estimate.ActivationQueuePosition = queueAhead + 1
+		estimate.ActivationEpoch = latestEpoch + queueAhead/activationChurnLimit + 1 + utils.Config.Chain.Config.MaxSeedLookahead + 1
+		estimate.ActivationEstimated = true
+	}
+	estimate.ActivationTs = utils.EpochToTime(estimate.ActivationEpoch).Unix()
+
+	length, lastEpoch, lastEpochCount, err := getExitQueue(latestEpoch)
+	if err != nil {
+		return nil, err
+	}
+	estimate.ExitQueueLength = length
+
+	if validator.ExitEpoch > unassignedEpochThreshold {
+		if estimate.ActivationEstimated {
+			// the exit of a validator that is not active yet can not be projected
+			estimate.ExitEpoch = 0
+			estimate.WithdrawableEpoch = 0
+			return estimate, nil
+		}
+
+		// a validator can only exit once it has been active for the shard committee period
+		exitRequestEpoch := latestEpoch
+		if earliest := estimate.ActivationEpoch + utils.Config.Chain.Config.ShardCommitteePeriod; earliest > exitRequestEpoch {
+			exitRequestEpoch = earliest
+		}
+		exitEpoch := exitRequestEpoch + 1 + utils.Config.Chain.Config.MaxSeedLookahead
+		if lastEpoch >= exitEpoch {
+			exitEpoch = lastEpoch
+			if lastEpochCount >= churnLimit {
+				exitEpoch++
+			}
+		}
+		estimate.ExitEpoch = exitEpoch
+		estimate.WithdrawableEpoch = exitEpoch + utils.Config.Chain.Config.MinValidatorWithdrawabilityDelay
+		estimate.ExitEstimated = true
+	}
+	estimate.ExitTs = utils.EpochToTime(estimate.ExitEpoch).Unix()
+	estimate.WithdrawableTs = utils.EpochToTime(estimate.WithdrawableEpoch).Unix()
+
+	return estimate, nil
+}
+
+// currentValidatorChurnLimit returns the churn limit of the latest stats or calculates it if the stats are not available yet
+func currentValidatorChurnLimit() (uint64, error) {
+	stats := GetLatestStats()
+	if stats != nil && stats.ValidatorChurnLimit != nil && *stats.ValidatorChurnLimit > 0 {
+		return *stats.ValidatorChurnLimit, nil
+	}
+	activeValidatorCount, err := db.GetActiveValidatorCount()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving active validator count: %v", err)
+	}
+	churnLimit, err := getValidatorChurnLimit(activeValidatorCount)
+	if err != nil {
+		return 0, err
+	}
+	if churnLimit == 0 {
+		return 0, fmt.Errorf("churn limit is not set, please set minPerEpochChurnLimit")
+	}
+	return churnLimit, nil
+}
+
+// getExitQueue returns the exit queue of the epoch, the queue is only retrieved once per epoch
+func getExitQueue(epoch uint64) (length, lastEpoch, lastEpochCount uint64, err error) {
+	exitQueueCache.Lock()
+	defer exitQueueCache.Unlock()
+
+	if exitQueueCache.epoch != epoch || epoch == 0 {
+		length, lastEpoch, lastEpochCount, err = db.GetExitQueue(epoch)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		exitQueueCache.epoch = epoch
+		exitQueueCache.length = length
+		exitQueueCache.lastEpoch = lastEpoch
+		exitQueueCache.lastEpochCount = lastEpochCount
+	}
+	return exitQueueCache.length, exitQueueCache.lastEpoch, exitQueueCache.lastEpochCount, nil
+}
@@ -58,6 +58,12 @@ func Init() {
 	ready.Add(1)
 	go relaysUpdater(ready)
 
+	ready.Add(1)
+	go builderLeaderboardUpdater(ready)
+
+	ready.Add(1)
+	go packingLeaderboardUpdater(ready)
+
 	ready.Add(1)
 	go chartsPageDataUpdater(ready)
 
@@ -90,7 +96,9 @@ func getRelaysPageData() (*types.RelaysResp, error) {
 				ROUND(avg(value)) as avg_value,
 				count(distinct builder_pubkey) as unique_builders,
 				max(value) as max_value,
-				(select rb2.block_slot from relays_blocks rb2 where rb2.value = max(rb.value) and rb2.tag_id = rb.tag_id limit 1) as max_value_slot
+				(select rb2.block_slot from relays_blocks rb2 where rb2.value = max(rb.value) and rb2.tag_id = rb.tag_id limit 1) as max_value_slot,
+				count(proposer_payment) as checked_blocks,
+				count(*) filter (where proposer_payment < value) as underpaid_blocks
 			from relays_blocks rb where rb.block_slot > $1 group by tag_id 
 		)
 		select 
@@ -130,12 +138,14 @@ func getRelaysPageData() (*types.RelaysResp, error) {
 		select 
 			builder_pubkey,
 			SUM(c) as c,
+			SUM(underpaid) as underpaid,
 			jsonb_agg(tags.metadata) as tags,
 			max(latest_slot) as latest_slot
 		from (
 			select 
 				builder_pubkey,
 				count(*) as c,
+				count(*) filter (where proposer_payment < value) as underpaid,
 				tag_id,
 				(
 					select block_slot
@@ -233,6 +243,35 @@ func getRelaysPageData() (*types.RelaysResp, error) {
 		return nil, err
 	}
 
+	err = db.ReaderDb.Select(&relaysData.Underpayments, `
+		select
+			jsonb_agg(tags.metadata order by id) as tags,
+			max(relays_blocks.value) as value,
+			max(relays_blocks.proposer_payment) as proposer_payment,
+			max(relays_blocks.value - relays_blocks.proposer_payment) as shortfall,
+			relays_blocks.block_slot as slot,
+			relays_blocks.builder_pubkey as builder_pubkey,
+			relays_blocks.proposer_fee_recipient as proposer_fee_recipient,
+			validators.validatorindex as proposer
+		from relays_blocks
+		left join tags
+			on tags.id = relays_blocks.tag_id
+		left join validators
+			on validators.pubkey = relays_blocks.proposer_pubkey
+		where relays_blocks.proposer_payment < relays_blocks.value and relays_blocks.block_slot > $1
+		group by
+			relays_blocks.block_root,
+			relays_blocks.block_slot,
+			relays_blocks.builder_pubkey,
+			relays_blocks.proposer_fee_recipient,
+			validators.validatorindex
+		order by relays_blocks.block_slot desc
+		limit 15`, LatestSlot()-(31*dayInSlots))
+	if err != nil {
+		logger.Errorf("failed to get underpaid blocks for relays page %v", err)
+		return nil, err
+	}
+
 	return &relaysData, nil
 }
 
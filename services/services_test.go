@@ -0,0 +1,35 @@
+package services
+
+import (
+	"eth2-exporter/rpc"
+	"eth2-exporter/types"
+	"eth2-exporter/utils"
+	"testing"
+)
+
+// TestReplayGasNow replays the pending block and the txpool of the execution client fixtures in rpc/testdata/replay
+func TestReplayGasNow(t *testing.T) {
+	endpoint, stop, err := rpc.StartReplayServer("../rpc/testdata/replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	utils.Config = &types.Config{}
+	utils.Config.Eth1GethEndpoint = endpoint
+
+	data, err := getGasNowData()
+	if err != nil {
+		t.Fatalf("error retrieving replayed gas now data: %v", err)
+	}
+
+	// the pending block contains transactions paying 30, 20 and 10 gwei, the txpool transactions with the lowest nonce
+	// of each account pay 25, 15 and 5 gwei
+	expected := map[string]int64{"rapid": 20e9, "fast": 10e9, "standard": 5e9, "slow": 5e9}
+	got := map[string]int64{"rapid": data.Data.Rapid.Int64(), "fast": data.Data.Fast.Int64(), "standard": data.Data.Standard.Int64(), "slow": data.Data.Slow.Int64()}
+	for price, want := range expected {
+		if got[price] != want {
+			t.Errorf("unexpected %v gas price %v, expected %v", price, got[price], want)
+		}
+	}
+}
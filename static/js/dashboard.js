@@ -962,6 +962,14 @@ $(document).ready(function () {
           // addChange("#earnings-total-change", result.total)
         },
       })
+      $.ajax({
+        url: "/dashboard/data/duties" + qryStr,
+        success: function (result) {
+          var t1 = Date.now()
+          console.log(`loaded duties: fetch: ${t1 - t0}ms`)
+          renderUpcomingDuties(result)
+        },
+      })
       $.ajax({
         url: "/dashboard/data/validators" + qryStr,
         success: function (result) {
@@ -1011,6 +1019,7 @@ $(document).ready(function () {
       })
     } else {
       document.querySelector("#copy-button").style.visibility = "hidden"
+      renderUpcomingDuties(null)
       document.querySelector("#rewards-button").style.visibility = "hidden"
       document.querySelector("#bookmark-button").style.visibility = "hidden"
       document.querySelector("#clear-search").style.visibility = "hidden"
@@ -1022,6 +1031,30 @@ $(document).ready(function () {
     renderCharts()
   }
 
+  function renderUpcomingDuties(duties) {
+    var rows = []
+    if (duties) {
+      for (var p of duties.proposals || []) {
+        rows.push({ duty: "Block Proposal", validator: p.validatorindex, slot: `<a href="/slot/${p.slot}">${p.slot}</a>`, ts: slotToTime(p.slot) })
+      }
+      for (var a of duties.attestations || []) {
+        rows.push({ duty: "Attestation", validator: a.validatorindex, slot: `<a href="/slot/${a.slot}">${a.slot}</a>`, ts: slotToTime(a.slot) })
+      }
+      for (var s of duties.sync_committees || []) {
+        rows.push({ duty: "Sync Committee", validator: s.validatorindex, slot: `<a href="/epoch/${s.start_epoch}">${s.start_epoch}</a> - <a href="/epoch/${s.end_epoch}">${s.end_epoch}</a>`, ts: epochToTime(s.start_epoch) })
+      }
+    }
+    if (!rows.length) {
+      $("#upcoming-duties-row").addClass("d-none")
+      return
+    }
+    rows.sort((a, b) => a.ts - b.ts || a.validator - b.validator)
+    var tbody = rows.map((r) => `<tr><td>${r.duty}</td><td><a href="/validator/${r.validator}">${r.validator}</a></td><td>${r.slot}</td><td><span class="timestamp" data-toggle="tooltip" data-placement="top" data-timestamp="${r.ts / 1000}"></span></td></tr>`).join("")
+    $("#upcoming-duties-table tbody").html(tbody)
+    $("#upcoming-duties-row").removeClass("d-none")
+    formatTimestamps("#upcoming-duties-table")
+  }
+
   window.onpopstate = function (event) {
     setValidatorsFromURL()
     renderSelectedValidators()
@@ -44,6 +44,66 @@ create table validator_pool
     primary key (publickey)
 );
 
+drop table if exists pool_operators;
+create table pool_operators
+(
+    pool            varchar(40)  not null,
+    operator_id     varchar(100) not null,
+    name            varchar(200) not null default '',
+    address         bytea,
+    active          bool         not null default true,
+    validator_count int          not null default 0,
+    updated_at      timestamp    not null default now(),
+    primary key (pool, operator_id)
+);
+
+drop table if exists pool_operator_validators;
+create table pool_operator_validators
+(
+    publickey   bytea        not null,
+    pool        varchar(40)  not null,
+    operator_id varchar(100) not null,
+    primary key (publickey)
+);
+create index idx_pool_operator_validators_operator on pool_operator_validators (pool, operator_id);
+
+drop table if exists ssv_operators;
+create table ssv_operators
+(
+    id              int       not null,
+    publickey       text      not null,
+    validator_count int       not null default 0,
+    updated_at      timestamp not null default now(),
+    primary key (id)
+);
+
+drop table if exists ssv_validator_operators;
+create table ssv_validator_operators
+(
+    publickey   bytea        not null,
+    operator_id int          not null,
+    cluster     varchar(200) not null, /* sorted ids of all operators of the validator, e.g. 1-5-9-20 */
+    primary key (publickey, operator_id)
+);
+create index idx_ssv_validator_operators_operator_id on ssv_validator_operators (operator_id);
+
+drop table if exists ssv_operator_stats;
+create table ssv_operator_stats
+(
+    operator_id               int              not null,
+    validators                int              not null default 0,
+    active_validators         int              not null default 0,
+    attestations_expected     bigint           not null default 0,
+    attestations_missed       bigint           not null default 0,
+    attestation_participation double precision not null default 0, -- share of attestation duties that were not missed
+    proposed_blocks           int              not null default 0,
+    missed_blocks             int              not null default 0,
+    orphaned_blocks           int              not null default 0,
+    performance7d             bigint           not null default 0,
+    updated_at                timestamp        not null default now(),
+    primary key (operator_id)
+);
+
 drop table if exists validator_names;
 create table validator_names
 (
@@ -120,6 +180,35 @@ create index idx_validator_balances_recent_epoch on validator_balances_recent (e
 create index idx_validator_balances_recent_validatorindex on validator_balances_recent (validatorindex);
 create index idx_validator_balances_recent_balance on validator_balances_recent (balance);
 
+drop table if exists validator_withdrawalcredentials_history;
+create table validator_withdrawalcredentials_history
+(
+    validatorindex            int   not null,
+    epoch                     int   not null,
+    old_withdrawalcredentials bytea not null,
+    new_withdrawalcredentials bytea not null,
+    primary key (validatorindex, epoch)
+);
+create index idx_validator_withdrawalcredentials_history_epoch on validator_withdrawalcredentials_history (epoch);
+
+drop table if exists potential_slashings;
+-- slashable offences detected by the exporter, kind is either double_vote, surround_vote or double_proposal. epoch is
+-- the epoch of the proposal or the highest target epoch of the conflicting votes
+create table potential_slashings
+(
+    id             serial      not null,
+    kind           varchar(20) not null,
+    validatorindex int         not null,
+    slot           int         not null,
+    epoch          int         not null,
+    evidence       jsonb       not null,
+    detected_ts    timestamp   not null,
+    primary key (id),
+    unique (kind, validatorindex, epoch)
+);
+create index idx_potential_slashings_validatorindex on potential_slashings (validatorindex);
+create index idx_potential_slashings_epoch on potential_slashings (epoch);
+
 drop table if exists validator_stats;
 create table validator_stats
 (
@@ -145,6 +234,8 @@ create table validator_stats
     proposer_slashings      int,
     deposits                int,
     deposits_amount         bigint,
+    withdrawals             int,
+    withdrawals_amount      bigint,
     primary key (validatorindex, day)
 );
 create index idx_validator_stats_day on validator_stats (day);
@@ -215,9 +306,56 @@ create table epochs
     eligibleether           bigint,
     globalparticipationrate float,
     votedether              bigint,
+    blobscount              int    not null default 0,
+    blob_gas_used           bigint not null default 0,
+    rewards_exported        bool   not null default false,
+    packing_exported        bool   not null default false,
     primary key (epoch)
 );
 
+drop table if exists chain_reorgs;
+create table chain_reorgs
+(
+    slot           int         not null,
+    depth          int         not null,
+    epoch          int         not null,
+    old_head_block bytea       not null,
+    new_head_block bytea       not null,
+    old_head_state bytea,
+    new_head_state bytea,
+    affected_slots int[]       not null default '{}',
+    source         varchar(20) not null,
+    ts             timestamp   not null default now(),
+    primary key (slot, old_head_block, new_head_block)
+);
+create index idx_chain_reorgs_ts on chain_reorgs (ts);
+
+drop table if exists epoch_export_failures;
+create table epoch_export_failures
+(
+    epoch           int       not null,
+    attempts        int       not null default 0,
+    last_error      text      not null,
+    first_failed_at timestamp not null default now(),
+    last_failed_at  timestamp not null default now(),
+    primary key (epoch)
+);
+
+drop table if exists backfill_ranges;
+create table backfill_ranges
+(
+    start_epoch int         not null,
+    end_epoch   int         not null,
+    next_epoch  int         not null,
+    status      varchar(10) not null default 'pending',
+    worker      varchar(100),
+    attempts    int         not null default 0,
+    last_error  text,
+    updated_at  timestamp   not null default now(),
+    primary key (start_epoch)
+);
+create index idx_backfill_ranges_status on backfill_ranges (status, start_epoch);
+
 drop table if exists blocks;
 create table blocks
 (
@@ -260,6 +398,11 @@ create table blocks
     exec_base_fee_per_gas       bigint,
     exec_block_hash             bytea, 
     exec_transactions_count     int     not null default 0,
+    withdrawalcount             int     not null default 0,
+    bls_change_count            int     not null default 0,
+    blobs_count                 int     not null default 0,
+    exec_blob_gas_used          bigint,
+    exec_excess_blob_gas        bigint,
 
     primary key (slot, blockroot)
 );
@@ -269,6 +412,19 @@ create index idx_blocks_graffiti_text on blocks using gin (graffiti_text gin_trg
 create index idx_blocks_blockrootstatus on blocks (blockroot, status);
 create index idx_blocks_exec_block_number on blocks (exec_block_number);
 
+drop table if exists blocks_arrival;
+-- time at which the exporter received the block and head events of a block, the delays are relative to the slot start
+create table blocks_arrival
+(
+    slot              int       not null,
+    blockroot         bytea     not null,
+    block_received_at timestamp null,
+    block_delay_ms    int       null,
+    head_received_at  timestamp null,
+    head_delay_ms     int       null,
+    primary key (slot, blockroot)
+);
+
 drop table if exists blocks_transactions;
 create table blocks_transactions
 (
@@ -358,6 +514,69 @@ create index idx_blocks_attestations_beaconblockroot on blocks_attestations (bea
 create index idx_blocks_attestations_source_root on blocks_attestations (source_root);
 create index idx_blocks_attestations_target_root on blocks_attestations (target_root);
 
+drop table if exists blocks_packing;
+create table blocks_packing
+(
+    slot                 int   not null,
+    blockroot            bytea not null,
+    proposer             int   not null,
+    included_votes       int   not null default 0,
+    available_votes      int   not null default 0,
+    aggregates           int   not null default 0,
+    redundant_aggregates int   not null default 0,
+    sync_participation   float not null default 0,
+    included_operations  int   not null default 0,
+    missed_slashings     int, /* null if the operation pool was not observed before the block */
+    missed_exits         int,
+    score                float, /* null until the packing of the block has been evaluated */
+    primary key (slot, blockroot)
+);
+create index idx_blocks_packing_proposer on blocks_packing (proposer);
+
+/* operations pending in the operation pool of the beacon node shortly before a slot, kept until the packing of the slot has been evaluated */
+drop table if exists operation_pool_snapshots;
+create table operation_pool_snapshots
+(
+    slot               int    not null,
+    proposer_slashings int[]  not null,
+    attester_slashings text[] not null,
+    voluntary_exits    int[]  not null,
+    primary key (slot)
+);
+
+/* proposer and attester assignments of the current and next epoch */
+drop table if exists duties_proposers;
+create table duties_proposers
+(
+    epoch          int not null,
+    slot           int not null,
+    validatorindex int not null,
+    primary key (slot)
+);
+create index idx_duties_proposers_validatorindex on duties_proposers (validatorindex);
+
+drop table if exists duties_committees;
+create table duties_committees
+(
+    epoch          int   not null,
+    slot           int   not null,
+    committeeindex int   not null,
+    validators     int[] not null,
+    primary key (slot, committeeindex)
+);
+create index idx_duties_committees_epoch on duties_committees (epoch);
+create index idx_duties_committees_validators on duties_committees using gin (validators);
+
+/* members of the current and next sync committee */
+drop table if exists duties_sync_committees;
+create table duties_sync_committees
+(
+    period         int not null,
+    validatorindex int not null,
+    primary key (period, validatorindex)
+);
+create index idx_duties_sync_committees_validatorindex on duties_sync_committees (validatorindex);
+
 drop table if exists blocks_deposits;
 create table blocks_deposits
 (
@@ -384,6 +603,49 @@ create table blocks_voluntaryexits
     primary key (block_slot, block_index)
 );
 
+drop table if exists blocks_withdrawals;
+create table blocks_withdrawals
+(
+    block_slot      int    not null,
+    block_root      bytea  not null,
+    withdrawalindex bigint not null,
+    validatorindex  int    not null,
+    address         bytea  not null,
+    amount          bigint not null, -- in GWei
+    primary key (block_slot, block_root, withdrawalindex)
+);
+create index idx_blocks_withdrawals_recipient on blocks_withdrawals (address);
+create index idx_blocks_withdrawals_validatorindex on blocks_withdrawals (validatorindex);
+
+drop table if exists blocks_bls_change;
+create table blocks_bls_change
+(
+    block_slot     int   not null,
+    block_root     bytea not null,
+    validatorindex int   not null,
+    signature      bytea not null,
+    pubkey         bytea not null,
+    address        bytea not null,
+    primary key (block_slot, block_root, validatorindex)
+);
+create index idx_blocks_bls_change_pubkey on blocks_bls_change (pubkey);
+create index idx_blocks_bls_change_address on blocks_bls_change (address);
+create index idx_blocks_bls_change_validatorindex on blocks_bls_change (validatorindex);
+
+drop table if exists blocks_blob_sidecars;
+create table blocks_blob_sidecars
+(
+    block_slot          int   not null,
+    block_root          bytea not null,
+    index               int   not null,
+    kzg_commitment      bytea not null,
+    kzg_proof           bytea,
+    blob_versioned_hash bytea not null,
+    primary key (block_root, index)
+);
+create index idx_blocks_blob_sidecars_block_slot on blocks_blob_sidecars (block_slot);
+create index idx_blocks_blob_sidecars_blob_versioned_hash on blocks_blob_sidecars (blob_versioned_hash);
+
 drop table if exists network_liveness;
 create table network_liveness
 (
@@ -1035,6 +1297,7 @@ CREATE TABLE relays_blocks (
 	proposer_pubkey bytea NOT NULL,
 	proposer_fee_recipient bytea NOT NULL,
 	value numeric NOT NULL,
+	proposer_payment numeric NULL, -- amount the fee recipient received in the execution block, null until checked
 	PRIMARY KEY (block_slot, block_root, tag_id)
 );
 CREATE INDEX relays_blocks_block_root_idx ON public.relays_blocks (block_root);
@@ -1042,6 +1305,80 @@ CREATE INDEX relays_blocks_builder_pubkey_idx ON public.relays_blocks (builder_p
 CREATE INDEX relays_blocks_exec_block_hash_idx ON public.relays_blocks (exec_block_hash);
 CREATE INDEX relays_blocks_value_idx ON public.relays_blocks (value);
 
+DROP TABLE IF EXISTS relays_bids;
+
+-- highest bid per builder and slot received by a relay
+CREATE TABLE relays_bids (
+	tag_id varchar NOT NULL,
+	slot int4 NOT NULL,
+	builder_pubkey bytea NOT NULL,
+	block_hash bytea NOT NULL,
+	proposer_fee_recipient bytea NOT NULL,
+	value numeric NOT NULL,
+	gas_used int8 NOT NULL,
+	num_tx int4 NOT NULL,
+	bid_count int4 NOT NULL DEFAULT 1,
+	received_at timestamp NULL,
+	PRIMARY KEY (slot, tag_id, builder_pubkey)
+);
+CREATE INDEX idx_relays_bids_tag_id_slot ON relays_bids (tag_id, slot desc);
+CREATE INDEX idx_relays_bids_builder_pubkey ON relays_bids (builder_pubkey);
+
+DROP TABLE IF EXISTS blocks_builders;
+DROP TABLE IF EXISTS builder_patterns;
+DROP TABLE IF EXISTS builders;
+
+CREATE TABLE builders (
+	id varchar NOT NULL,
+	name varchar NOT NULL,
+	link varchar NULL,
+	PRIMARY KEY (id)
+);
+
+-- patterns identifying the blocks of a builder, kind is either pubkey (the builder pubkey used at the relays),
+-- fee_recipient (the fee recipient of the execution block) or extra_data (a part of the block extra data)
+CREATE TABLE builder_patterns (
+	builder_id varchar NOT NULL,
+	kind varchar NOT NULL,
+	pattern bytea NOT NULL,
+	PRIMARY KEY (kind, pattern),
+	FOREIGN KEY (builder_id) REFERENCES builders(id)
+);
+
+-- seed data of well known mainnet builders, blocks of builders without patterns are attributed by their relay builder pubkey
+INSERT INTO builders (id, name, link) VALUES
+	('flashbots', 'Flashbots', 'https://www.flashbots.net'),
+	('beaverbuild', 'beaverbuild', 'https://beaverbuild.org'),
+	('titan', 'Titan Builder', 'https://titanbuilder.xyz'),
+	('rsync', 'rsync-builder', 'https://rsync-builder.xyz'),
+	('builder0x69', 'builder0x69', NULL),
+	('bloxroute', 'bloXroute', 'https://bloxroute.com');
+
+INSERT INTO builder_patterns (builder_id, kind, pattern) VALUES
+	('flashbots', 'fee_recipient', decode('dafea492d9c6733ae3d56b7ed1adb60692c98bc5', 'hex')),
+	('flashbots', 'extra_data', convert_to('Illuminate Dmocratize Dstribute', 'UTF8')),
+	('beaverbuild', 'fee_recipient', decode('95222290dd7278aa3ddd389cc1e1d165cc4bafe5', 'hex')),
+	('beaverbuild', 'extra_data', convert_to('beaverbuild.org', 'UTF8')),
+	('titan', 'fee_recipient', decode('4838b106fce9647bdf1e7877bf73ce8b0bad5f97', 'hex')),
+	('titan', 'extra_data', convert_to('titanbuilder.xyz', 'UTF8')),
+	('rsync', 'fee_recipient', decode('1f9090aae28b8a3dceadf281b0f12828e676c326', 'hex')),
+	('rsync', 'extra_data', convert_to('rsync-builder.xyz', 'UTF8')),
+	('builder0x69', 'fee_recipient', decode('690b9a9e9aa1c9db991c7721a92d351db4fac990', 'hex')),
+	('builder0x69', 'extra_data', convert_to('builder0x69', 'UTF8')),
+	('bloxroute', 'extra_data', convert_to('bloXroute', 'UTF8'));
+
+-- builder of every post-merge block, source is either relay, fee_recipient, extra_data or none if the block could not be attributed
+CREATE TABLE blocks_builders (
+	slot int4 NOT NULL,
+	blockroot bytea NOT NULL,
+	builder_id varchar NULL,
+	builder_pubkey bytea NULL,
+	source varchar NOT NULL,
+	PRIMARY KEY (slot, blockroot)
+);
+CREATE INDEX idx_blocks_builders_builder_id ON blocks_builders (builder_id);
+CREATE INDEX idx_blocks_builders_builder_pubkey ON blocks_builders (builder_pubkey);
+
 
 DROP TABLE IF EXISTS validator_queue_deposits;
 CREATE TABLE validator_queue_deposits (
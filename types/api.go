@@ -207,3 +207,21 @@ func (e *DiscordReq) Scan(value interface{}) error {
 func (a DiscordReq) Value() (driver.Value, error) {
 	return json.Marshal(a)
 }
+
+// ValidatorQueueEstimate holds the projected activation and exit of a validator. The epochs are exact once they have
+// been assigned by the beacon chain, the exit of an active validator is projected as if it exited now.
+type ValidatorQueueEstimate struct {
+	ValidatorIndex          uint64 `json:"validatorindex"`
+	ChurnLimit              uint64 `json:"churn_limit"`
+	ActivationChurnLimit    uint64 `json:"activation_churn_limit"`
+	ActivationQueuePosition uint64 `json:"activation_queue_position"`
+	ActivationEpoch         uint64 `json:"activation_epoch"`
+	ActivationTs            int64  `json:"activation_ts"`
+	ActivationEstimated     bool   `json:"activation_estimated"`
+	ExitQueueLength         uint64 `json:"exit_queue_length"`
+	ExitEpoch               uint64 `json:"exit_epoch"`
+	ExitTs                  int64  `json:"exit_ts"`
+	WithdrawableEpoch       uint64 `json:"withdrawable_epoch"`
+	WithdrawableTs          int64  `json:"withdrawable_ts"`
+	ExitEstimated           bool   `json:"exit_estimated"`
+}
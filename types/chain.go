@@ -17,6 +17,8 @@ type ChainConfig struct {
 	BellatrixForkEpoch               uint64 `yaml:"BELLATRIX_FORK_EPOCH"`
 	CappellaForkVersion              string `yaml:"CAPELLA_FORK_VERSION"`
 	CappellaForkEpoch                uint64 `yaml:"CAPELLA_FORK_EPOCH"`
+	DenebForkVersion                 string `yaml:"DENEB_FORK_VERSION"`
+	DenebForkEpoch                   uint64 `yaml:"DENEB_FORK_EPOCH"`
 	ShardingForkVersion              string `yaml:"SHARDING_FORK_VERSION"`
 	ShardingForkEpoch                uint64 `yaml:"SHARDING_FORK_EPOCH"`
 	SecondsPerSlot                   uint64 `yaml:"SECONDS_PER_SLOT"`
@@ -29,6 +31,7 @@ type ChainConfig struct {
 	EjectionBalance                  uint64 `yaml:"EJECTION_BALANCE"`
 	MinPerEpochChurnLimit            uint64 `yaml:"MIN_PER_EPOCH_CHURN_LIMIT"`
 	ChurnLimitQuotient               uint64 `yaml:"CHURN_LIMIT_QUOTIENT"`
+	MaxPerEpochActivationChurnLimit  uint64 `yaml:"MAX_PER_EPOCH_ACTIVATION_CHURN_LIMIT"`
 	ProposerScoreBoost               uint64 `yaml:"PROPOSER_SCORE_BOOST"`
 	DepositChainID                   uint64 `yaml:"DEPOSIT_CHAIN_ID"`
 	DepositNetworkID                 uint64 `yaml:"DEPOSIT_NETWORK_ID"`
@@ -88,4 +91,8 @@ type ChainConfig struct {
 	MaxTransactionsPerPayload               uint64 `yaml:"MAX_TRANSACTIONS_PER_PAYLOAD"`
 	BytesPerLogsBloom                       uint64 `yaml:"BYTES_PER_LOGS_BLOOM"`
 	MaxExtraDataBytes                       uint64 `yaml:"MAX_EXTRA_DATA_BYTES"`
+
+	// deneb
+	// https://eips.ethereum.org/EIPS/eip-4844#parameters
+	BlobBaseFeeUpdateFraction uint64 `yaml:"BLOB_BASE_FEE_UPDATE_FRACTION"`
 }
@@ -2,6 +2,7 @@ package types
 
 import (
 	"html/template"
+	"time"
 )
 
 // Config is a struct to hold the configuration data
@@ -48,6 +49,18 @@ type Config struct {
 			Host     string `yaml:"host" envconfig:"INDEXER_NODE_HOST"`
 			Type     string `yaml:"type" envconfig:"INDEXER_NODE_TYPE"`
 			PageSize int32  `yaml:"pageSize" envconfig:"INDEXER_NODE_PAGE_SIZE"`
+			// Endpoints is a list of additional beacon node endpoints (host:port) used for failover, ordered by priority
+			Endpoints []string `yaml:"endpoints" envconfig:"INDEXER_NODE_ENDPOINTS"`
+			// Quorum is the number of nodes that have to agree on the block roots of an epoch before it is exported
+			Quorum int `yaml:"quorum" envconfig:"INDEXER_NODE_QUORUM"`
+			// Timeouts of requests to the beacon node by call type, state and rewards requests can take considerably longer
+			Timeouts struct {
+				Default time.Duration `yaml:"default" envconfig:"INDEXER_NODE_TIMEOUT_DEFAULT"`
+				State   time.Duration `yaml:"state" envconfig:"INDEXER_NODE_TIMEOUT_STATE"`
+				Rewards time.Duration `yaml:"rewards" envconfig:"INDEXER_NODE_TIMEOUT_REWARDS"`
+			} `yaml:"timeouts"`
+			// MaxRetries is the number of times a request failing with a network or server error is retried
+			MaxRetries int `yaml:"maxRetries" envconfig:"INDEXER_NODE_MAX_RETRIES"`
 		} `yaml:"node"`
 		// Deprecated Please use Phase0 config DEPOSIT_CONTRACT_ADDRESS
 		Eth1DepositContractAddress    string `yaml:"eth1DepositContractAddress" envconfig:"INDEXER_ETH1_DEPOSIT_CONTRACT_ADDRESS"`
@@ -61,6 +74,38 @@ type Config struct {
 		PubKeyTagsExporter struct {
 			Enabled bool `yaml:"enabled" envconfig:"PUBKEY_TAGS_EXPORTER_ENABLED"`
 		} `yaml:"pubkeyTagsExporter"`
+		RewardsExporter struct {
+			Enabled bool `yaml:"enabled" envconfig:"INDEXER_REWARDS_EXPORTER_ENABLED"`
+		} `yaml:"rewardsExporter"`
+		// LeaderElection allows running redundant indexer instances, only the instance holding the leader lock exports data
+		LeaderElection struct {
+			Enabled  bool          `yaml:"enabled" envconfig:"INDEXER_LEADER_ELECTION_ENABLED"`
+			Interval time.Duration `yaml:"interval" envconfig:"INDEXER_LEADER_ELECTION_INTERVAL"`
+		} `yaml:"leaderElection"`
+		// Backfill configures the parallel export of historic epochs, progress is stored in the backfill_ranges table. With
+		// leader election enabled a standalone backfill only exports if no exporter holds the leader lock, otherwise it
+		// enqueues its ranges and the running leader exports them.
+		Backfill struct {
+			Workers   int    `yaml:"workers" envconfig:"INDEXER_BACKFILL_WORKERS"`
+			RangeSize uint64 `yaml:"rangeSize" envconfig:"INDEXER_BACKFILL_RANGE_SIZE"`
+			// StaleAfter is the time after which a range claimed by a worker that stopped reporting progress is handed out again
+			StaleAfter time.Duration `yaml:"staleAfter" envconfig:"INDEXER_BACKFILL_STALE_AFTER"`
+		} `yaml:"backfill"`
+		// Slasher detects double votes, surround votes and double proposals in the blocks and attestations processed by the exporter
+		Slasher struct {
+			Enabled bool `yaml:"enabled" envconfig:"INDEXER_SLASHER_ENABLED"`
+			// HistoryEpochs is the number of target epochs of votes kept in memory to detect surround votes
+			HistoryEpochs uint64 `yaml:"historyEpochs" envconfig:"INDEXER_SLASHER_HISTORY_EPOCHS"`
+		} `yaml:"slasher"`
+		// PackingExporter evaluates how well the blocks of finalized epochs were packed by their proposers
+		PackingExporter struct {
+			Enabled bool `yaml:"enabled" envconfig:"INDEXER_PACKING_EXPORTER_ENABLED"`
+		} `yaml:"packingExporter"`
+		// DutiesExporter stores the proposer and attester assignments of the current and next epoch and the current and
+		// next sync committee for the duties api
+		DutiesExporter struct {
+			Enabled bool `yaml:"enabled" envconfig:"INDEXER_DUTIES_EXPORTER_ENABLED"`
+		} `yaml:"dutiesExporter"`
 	} `yaml:"indexer"`
 	Frontend struct {
 		Debug                          bool   `yaml:"debug" envconfig:"FRONTEND_DEBUG"`
@@ -156,6 +201,7 @@ type Config struct {
 		UserDBNotifications                           bool   `yaml:"userDbNotifications" envconfig:"FRONTEND_USERDB_NOTIFICATIONS_ENABLED"`
 		FirebaseCredentialsPath                       string `yaml:"firebaseCredentialsPath" envconfig:"FRONTEND_NOTIFICATIONS_FIREBASE_CRED_PATH"`
 		ValidatorBalanceDecreasedNotificationsEnabled bool   `yaml:"validatorBalanceDecreasedNotificationsEnabled" envconfig:"FRONTEND_VALIDATOR_BALANCE_DECREASED_NOTIFICATIONS_ENABLED"`
+		QueueEstimateShiftThreshold                   uint64 `yaml:"queueEstimateShiftThreshold" envconfig:"FRONTEND_NOTIFICATIONS_QUEUE_ESTIMATE_SHIFT_THRESHOLD"`
 	} `yaml:"notifications"`
 	SSVExporter struct {
 		Enabled bool   `yaml:"enabled" envconfig:"SSV_EXPORTER_ENABLED"`
@@ -166,6 +212,13 @@ type Config struct {
 		StorageContractAddress    string `yaml:"storageContractAddress" envconfig:"ROCKETPOOL_EXPORTER_STORAGE_CONTRACT_ADDRESS"`
 		StorageContractFirstBlock uint64 `yaml:"storageContractFirstBlock" envconfig:"ROCKETPOOL_EXPORTER_STORAGE_CONTRACT_FIRST_BLOCK"`
 	} `yaml:"rocketpoolExporter"`
+	PoolAdapters struct {
+		Enabled bool `yaml:"enabled" envconfig:"POOL_ADAPTERS_ENABLED"`
+		Lido    struct {
+			Enabled                      bool   `yaml:"enabled" envconfig:"POOL_ADAPTERS_LIDO_ENABLED"`
+			NodeOperatorsRegistryAddress string `yaml:"nodeOperatorsRegistryAddress" envconfig:"POOL_ADAPTERS_LIDO_NODE_OPERATORS_REGISTRY_ADDRESS"`
+		} `yaml:"lido"`
+	} `yaml:"poolAdapters"`
 	MevBoostRelayExporter struct {
 		Enabled bool `yaml:"enabled" envconfig:"MEVBOOSTRELAY_EXPORTER_ENABLED"`
 	} `yaml:"mevBoostRelayExporter"`
@@ -2,11 +2,14 @@ package types
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/jackc/pgtype"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/prysm/v3/proto/prysm/v1alpha1"
 	"github.com/shopspring/decimal"
@@ -29,6 +32,13 @@ type ChainHead struct {
 	PreviousJustifiedBlockRoot []byte
 }
 
+// NodeSyncStatus is a struct to hold the sync status of a beacon node
+type NodeSyncStatus struct {
+	IsSyncing    bool
+	HeadSlot     uint64
+	SyncDistance uint64
+}
+
 type FinalityCheckpoints struct {
 	PreviousJustified struct {
 		Epoch uint64 `json:"epoch"`
@@ -44,6 +54,129 @@ type FinalityCheckpoints struct {
 	} `json:"finalized"`
 }
 
+// ValidatorEpochRewards is a struct to hold the reward breakdown of a validator for an epoch as reported by the
+// standard rewards api, all amounts are in gwei and negative for penalties
+type ValidatorEpochRewards struct {
+	ValidatorIndex               uint64 `json:"validatorindex"`
+	Epoch                        uint64 `json:"epoch"`
+	AttestationSource            int64  `json:"attestation_source"`
+	AttestationTarget            int64  `json:"attestation_target"`
+	AttestationHead              int64  `json:"attestation_head"`
+	AttestationInclusionDelay    int64  `json:"attestation_inclusion_delay"`
+	AttestationInactivity        int64  `json:"attestation_inactivity"`
+	ProposerAttestationInclusion int64  `json:"proposer_attestation_inclusion"`
+	ProposerSyncInclusion        int64  `json:"proposer_sync_inclusion"`
+	ProposerSlashingInclusion    int64  `json:"proposer_slashing_inclusion"`
+	SyncCommittee                int64  `json:"sync_committee"`
+}
+
+// Total returns the sum of all rewards and penalties
+func (r *ValidatorEpochRewards) Total() int64 {
+	return r.AttestationSource + r.AttestationTarget + r.AttestationHead + r.AttestationInclusionDelay + r.AttestationInactivity +
+		r.ProposerAttestationInclusion + r.ProposerSyncInclusion + r.ProposerSlashingInclusion + r.SyncCommittee
+}
+
+// BeaconEvent is a typed event received via the event stream of a beacon node
+type BeaconEvent interface {
+	// Topic returns the event stream topic the event was received on
+	Topic() string
+	// Key identifies the event, it is used to deduplicate events received from multiple nodes
+	Key() string
+}
+
+// HeadEvent is emitted by the beacon node whenever the head of the chain changes
+type HeadEvent struct {
+	Slot                      uint64
+	Block                     []byte
+	State                     []byte
+	EpochTransition           bool
+	PreviousDutyDependentRoot []byte
+	CurrentDutyDependentRoot  []byte
+	ExecutionOptimistic       bool
+	// ReceivedAt is the time at which the event was received from the beacon node
+	ReceivedAt time.Time
+}
+
+func (e *HeadEvent) Topic() string { return "head" }
+func (e *HeadEvent) Key() string   { return fmt.Sprintf("head-%d-%x", e.Slot, e.Block) }
+
+// BlockEvent is emitted by the beacon node whenever a block has been imported, the block data is retrieved by the client
+type BlockEvent struct {
+	Slot                uint64
+	BlockRoot           []byte
+	ExecutionOptimistic bool
+	Block               *Block
+	// ReceivedAt is the time at which the event was received from the beacon node, before the block was retrieved
+	ReceivedAt time.Time
+}
+
+func (e *BlockEvent) Topic() string { return "block" }
+func (e *BlockEvent) Key() string   { return fmt.Sprintf("block-%d-%x", e.Slot, e.BlockRoot) }
+
+// FinalizedCheckpointEvent is emitted by the beacon node whenever a new checkpoint has been finalized
+type FinalizedCheckpointEvent struct {
+	Epoch               uint64
+	Block               []byte
+	State               []byte
+	ExecutionOptimistic bool
+}
+
+func (e *FinalizedCheckpointEvent) Topic() string { return "finalized_checkpoint" }
+func (e *FinalizedCheckpointEvent) Key() string {
+	return fmt.Sprintf("finalized_checkpoint-%d-%x", e.Epoch, e.Block)
+}
+
+// ChainReorgEvent is emitted by the beacon node whenever the head block is replaced by a block that does not descend from it
+type ChainReorgEvent struct {
+	Slot                uint64
+	Depth               uint64
+	Epoch               uint64
+	OldHeadBlock        []byte
+	NewHeadBlock        []byte
+	OldHeadState        []byte
+	NewHeadState        []byte
+	ExecutionOptimistic bool
+}
+
+func (e *ChainReorgEvent) Topic() string { return "chain_reorg" }
+func (e *ChainReorgEvent) Key() string {
+	return fmt.Sprintf("chain_reorg-%d-%x-%x", e.Slot, e.OldHeadBlock, e.NewHeadBlock)
+}
+
+// VoluntaryExitEvent is emitted by the beacon node whenever a valid voluntary exit has been received via the api or gossip
+type VoluntaryExitEvent struct {
+	VoluntaryExit
+}
+
+func (e *VoluntaryExitEvent) Topic() string { return "voluntary_exit" }
+func (e *VoluntaryExitEvent) Key() string {
+	return fmt.Sprintf("voluntary_exit-%d-%d", e.ValidatorIndex, e.Epoch)
+}
+
+// AttesterSlashingEvent is emitted by the beacon node whenever a valid attester slashing has been received via the api or gossip
+type AttesterSlashingEvent struct {
+	AttesterSlashing
+}
+
+func (e *AttesterSlashingEvent) Topic() string { return "attester_slashing" }
+func (e *AttesterSlashingEvent) Key() string {
+	return fmt.Sprintf("attester_slashing-%x-%x", e.Attestation1.Signature, e.Attestation2.Signature)
+}
+
+// ChainReorg is a struct to hold a chain reorganization, AffectedSlots are the slots of the blocks orphaned by it
+type ChainReorg struct {
+	Slot          uint64        `db:"slot" json:"slot"`
+	Depth         uint64        `db:"depth" json:"depth"`
+	Epoch         uint64        `db:"epoch" json:"epoch"`
+	OldHeadBlock  []byte        `db:"old_head_block" json:"old_head_block"`
+	NewHeadBlock  []byte        `db:"new_head_block" json:"new_head_block"`
+	OldHeadState  []byte        `db:"old_head_state" json:"old_head_state"`
+	NewHeadState  []byte        `db:"new_head_state" json:"new_head_state"`
+	AffectedSlots pq.Int64Array `db:"affected_slots" json:"affected_slots"`
+	Source        string        `db:"source" json:"source"`
+	Ts            time.Time     `db:"ts" json:"ts"`
+}
+
 // EpochData is a struct to hold epoch data
 type EpochData struct {
 	Epoch                   uint64
@@ -105,25 +238,49 @@ type SyncAggregate struct {
 
 // Block is a struct to hold block data
 type Block struct {
-	Status            uint64
-	Proposer          uint64
-	BlockRoot         []byte
-	Slot              uint64
-	ParentRoot        []byte
-	StateRoot         []byte
-	Signature         []byte
-	RandaoReveal      []byte
-	Graffiti          []byte
-	Eth1Data          *Eth1Data
-	BodyRoot          []byte
-	ProposerSlashings []*ProposerSlashing
-	AttesterSlashings []*AttesterSlashing
-	Attestations      []*Attestation
-	Deposits          []*Deposit
-	VoluntaryExits    []*VoluntaryExit
-	SyncAggregate     *SyncAggregate    // warning: sync aggregate may be nil, for phase0 blocks
-	ExecutionPayload  *ExecutionPayload // warning: payload may be nil, for phase0/altair blocks
-	Canonical         bool
+	Status                     uint64
+	Proposer                   uint64
+	BlockRoot                  []byte
+	Slot                       uint64
+	ParentRoot                 []byte
+	StateRoot                  []byte
+	Signature                  []byte
+	RandaoReveal               []byte
+	Graffiti                   []byte
+	Eth1Data                   *Eth1Data
+	BodyRoot                   []byte
+	ProposerSlashings          []*ProposerSlashing
+	AttesterSlashings          []*AttesterSlashing
+	Attestations               []*Attestation
+	Deposits                   []*Deposit
+	VoluntaryExits             []*VoluntaryExit
+	SyncAggregate              *SyncAggregate    // warning: sync aggregate may be nil, for phase0 blocks
+	ExecutionPayload           *ExecutionPayload // warning: payload may be nil, for phase0/altair blocks
+	Canonical                  bool
+	SignedBLSToExecutionChange []*SignedBLSToExecutionChange
+	BlobKZGCommitments         [][]byte
+	BlobSidecars               []*BlobSidecar
+}
+
+// BlobSidecar is a struct to hold the metadata of a blob sidecar of a block, the blob itself is not stored
+type BlobSidecar struct {
+	Index             uint64
+	KzgCommitment     []byte
+	KzgProof          []byte // warning: proof may be nil if the sidecar has already been pruned by the node
+	BlobVersionedHash []byte
+}
+
+// SignedBLSToExecutionChange is a struct to hold a signed change of the withdrawal credentials of a validator
+type SignedBLSToExecutionChange struct {
+	Message   BLSToExecutionChange
+	Signature []byte
+}
+
+// BLSToExecutionChange is a struct to hold the change of bls withdrawal credentials to an execution address
+type BLSToExecutionChange struct {
+	Validatorindex uint64
+	BlsPubkey      []byte
+	Address        []byte
 }
 
 type Transaction struct {
@@ -159,6 +316,19 @@ type ExecutionPayload struct {
 	BaseFeePerGas uint64
 	BlockHash     []byte
 	Transactions  []*Transaction
+	Withdrawals   []*Withdrawal
+	BlobGasUsed   uint64
+	ExcessBlobGas uint64
+}
+
+// Withdrawal is a struct to hold a withdrawal of the execution payload
+type Withdrawal struct {
+	Slot           uint64 `json:"slot,omitempty" db:"slot"`
+	BlockRoot      []byte `json:"blockroot,omitempty" db:"blockroot"`
+	Index          uint64 `json:"index" db:"index"`
+	ValidatorIndex uint64 `json:"validatorindex" db:"validatorindex"`
+	Address        []byte `json:"address" db:"address"`
+	Amount         uint64 `json:"amount" db:"amount"`
 }
 
 // Eth1Data is a struct to hold the ETH1 data
@@ -205,6 +375,103 @@ type AttestationData struct {
 	Target          *Checkpoint
 }
 
+// AttestationVotes holds which votes of an attestation match the canonical chain, Checked is false if the canonical
+// block roots needed for the comparison were not available
+type AttestationVotes struct {
+	Checked bool
+	Source  bool
+	Target  bool
+	Head    bool
+}
+
+// Reasons why an attestation duty was missed, an empty reason means the cause could not be determined. The reasons
+// ending in _guess are derived from the votes of the rest of the network as the vote of the validator is unknown.
+const (
+	MissedAttestationReasonOffline            = "offline"
+	MissedAttestationReasonBlockMissing       = "block_missing"
+	MissedAttestationReasonBlockLate          = "block_late"
+	MissedAttestationReasonIncludedTooLate    = "included_too_late"
+	MissedAttestationReasonWrongHeadGuess     = "wrong_head_guess"
+	MissedAttestationReasonNotAggregatedGuess = "aggregate_not_picked_up_guess"
+)
+
+// AttestationSlotContext holds what happened at a slot with attestation duties, it is used to classify missed attestations
+type AttestationSlotContext struct {
+	Slot             uint64        `db:"slot"`
+	BlockStatus      string        `db:"block_status"`
+	BlockDelayMs     sql.NullInt64 `db:"block_delay_ms"`
+	IncludedVotes    uint64        `db:"included_votes"`
+	CorrectHeadVotes uint64        `db:"correct_head_votes"`
+}
+
+// BlockPacking holds how well a proposer packed a block. Votes are counted per validator duty, a vote is available to
+// a block if it was included by the block or by a later block although the block could have included it. The missed
+// operations are nil if the operation pool was not observed before the block.
+type BlockPacking struct {
+	Slot                uint64  `db:"slot" json:"slot"`
+	BlockRoot           []byte  `db:"blockroot" json:"-"`
+	Proposer            uint64  `db:"proposer" json:"proposer"`
+	IncludedVotes       uint64  `db:"included_votes" json:"included_votes"`
+	AvailableVotes      uint64  `db:"available_votes" json:"available_votes"`
+	Aggregates          uint64  `db:"aggregates" json:"aggregates"`
+	RedundantAggregates uint64  `db:"redundant_aggregates" json:"redundant_aggregates"`
+	SyncParticipation   float64 `db:"sync_participation" json:"sync_participation"`
+	IncludedOperations  uint64  `db:"included_operations" json:"included_operations"`
+	MissedSlashings     *uint64 `db:"missed_slashings" json:"missed_slashings"`
+	MissedExits         *uint64 `db:"missed_exits" json:"missed_exits"`
+	Score               float64 `db:"score" json:"score"`
+}
+
+// PackingBlock holds the data of a canonical block needed to evaluate its packing. PendingOperations is nil if the
+// operation pool was not observed before the slot of the block.
+type PackingBlock struct {
+	Slot                      uint64        `db:"slot"`
+	BlockRoot                 []byte        `db:"blockroot"`
+	Proposer                  uint64        `db:"proposer"`
+	SyncParticipation         float64       `db:"sync_participation"`
+	HasSyncAggregate          bool          `db:"has_sync_aggregate"`
+	IncludedOperations        uint64        `db:"included_operations"`
+	IncludedProposerSlashings pq.Int64Array `db:"included_proposer_slashings"`
+	IncludedAttesterSlashings []string
+	IncludedExits             pq.Int64Array `db:"included_exits"`
+	PendingOperations         *OperationPoolSnapshot
+	Aggregates                []*PackingAggregate
+}
+
+// OperationPool holds the slashings and voluntary exits pending in the operation pool of a beacon node, proposer
+// slashings are identified by the slashed proposer
+type OperationPool struct {
+	ProposerSlashings []uint64
+	AttesterSlashings []*AttesterSlashing
+	VoluntaryExits    []*VoluntaryExit
+}
+
+// OperationPoolSnapshot holds the operations that were pending in the operation pool of the beacon node shortly
+// before a slot started and could be included at that slot. Slashings are identified by the validators they slash and
+// exits by the exiting validator.
+type OperationPoolSnapshot struct {
+	Slot              uint64         `db:"slot"`
+	ProposerSlashings pq.Int64Array  `db:"proposer_slashings"`
+	AttesterSlashings pq.StringArray `db:"attester_slashings"`
+	VoluntaryExits    pq.Int64Array  `db:"voluntary_exits"`
+}
+
+// PackingAggregate holds the attested slot and the attesters of an aggregate included in a block
+type PackingAggregate struct {
+	BlockSlot  uint64        `db:"block_slot"`
+	BlockRoot  []byte        `db:"block_root"`
+	Slot       uint64        `db:"slot"`
+	Validators pq.Int64Array `db:"validators"`
+}
+
+// CanonicalBlockRoots holds the roots of the canonical blocks from StartSlot on, slots without a canonical block are absent.
+// Known holds the slots whose status is known to be canonical or missed, slots that have not been exported yet are absent.
+type CanonicalBlockRoots struct {
+	StartSlot uint64
+	Roots     map[uint64][]byte
+	Known     map[uint64]bool
+}
+
 // Checkpoint is a struct to hold checkpoint data
 type Checkpoint struct {
 	Epoch uint64
@@ -265,6 +532,38 @@ type EpochAssignments struct {
 	SyncAssignments     []uint64
 }
 
+// ProposerDuty is a block proposal a validator is scheduled for, the proposals of the next epoch are not final as they
+// depend on the last block of the current epoch
+type ProposerDuty struct {
+	Epoch          uint64 `db:"epoch" json:"epoch"`
+	Slot           uint64 `db:"slot" json:"slot"`
+	ValidatorIndex uint64 `db:"validatorindex" json:"validatorindex"`
+	Final          bool   `db:"-" json:"final"`
+}
+
+// AttesterDuty is an attestation a validator is scheduled for
+type AttesterDuty struct {
+	Epoch          uint64 `db:"epoch" json:"epoch"`
+	Slot           uint64 `db:"slot" json:"slot"`
+	CommitteeIndex uint64 `db:"committeeindex" json:"committeeindex"`
+	ValidatorIndex uint64 `db:"validatorindex" json:"validatorindex"`
+}
+
+// SyncCommitteeDuty is a sync committee period a validator is a member of
+type SyncCommitteeDuty struct {
+	Period         uint64 `db:"period" json:"period"`
+	StartEpoch     uint64 `db:"-" json:"start_epoch"`
+	EndEpoch       uint64 `db:"-" json:"end_epoch"`
+	ValidatorIndex uint64 `db:"validatorindex" json:"validatorindex"`
+}
+
+// ValidatorDuties holds the upcoming duties of a set of validators
+type ValidatorDuties struct {
+	Proposals      []*ProposerDuty      `json:"proposals"`
+	Attestations   []*AttesterDuty      `json:"attestations"`
+	SyncCommittees []*SyncCommitteeDuty `json:"sync_committees"`
+}
+
 // Eth1Deposit is a struct to hold eth1-deposit data
 type Eth1Deposit struct {
 	TxHash                []byte `db:"tx_hash"`
@@ -496,6 +795,30 @@ type RelayBlock struct {
 	ProposerFeeRecipient string `db:"proposer_fee_recipient" json:"proposer_fee_recipient"`
 }
 
+// RelayBid is the highest bid of a builder for a slot received by a relay
+type RelayBid struct {
+	ID                   string
+	Slot                 uint64
+	BuilderPubkey        []byte
+	BlockHash            []byte
+	ProposerFeeRecipient []byte
+	Value                WeiString
+	GasUsed              uint64
+	NumTx                uint64
+	BidCount             uint64
+	ReceivedAt           time.Time
+}
+
+// RelayPaymentCheck is a block delivered by a relay whose promised value has not been compared with the payment in the execution block yet
+type RelayPaymentCheck struct {
+	BlockSlot            uint64    `db:"block_slot"`
+	BlockRoot            []byte    `db:"block_root"`
+	ExecBlockHash        []byte    `db:"exec_block_hash"`
+	ExecBlockNumber      uint64    `db:"exec_block_number"`
+	ProposerFeeRecipient []byte    `db:"proposer_fee_recipient"`
+	Value                WeiString `db:"value"`
+}
+
 type RelayBlockSlice []RelayBlock
 
 func (s *RelayBlockSlice) Scan(src interface{}) error {
@@ -588,3 +911,108 @@ func (b *WeiString) BigInt() *big.Int {
 	num.Mul(num, mul)
 	return num
 }
+
+// BackfillRange is a range of epochs in the backfill work queue, NextEpoch is the checkpoint up to which the range has been exported
+type BackfillRange struct {
+	StartEpoch uint64         `db:"start_epoch"`
+	EndEpoch   uint64         `db:"end_epoch"`
+	NextEpoch  uint64         `db:"next_epoch"`
+	Status     string         `db:"status"`
+	Worker     sql.NullString `db:"worker"`
+	Attempts   uint64         `db:"attempts"`
+	LastError  sql.NullString `db:"last_error"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+}
+
+// BackfillProgress holds the number of ranges and remaining epochs of the backfill work queue
+type BackfillProgress struct {
+	Pending   uint64 `db:"pending"`
+	Running   uint64 `db:"running"`
+	Done      uint64 `db:"done"`
+	Failed    uint64 `db:"failed"`
+	Remaining uint64 `db:"remaining"`
+}
+
+// EpochExportFailure holds the number of failed export attempts of an epoch and the error of the last attempt
+type EpochExportFailure struct {
+	Epoch         uint64    `db:"epoch" json:"epoch"`
+	Attempts      uint64    `db:"attempts" json:"attempts"`
+	LastError     string    `db:"last_error" json:"last_error"`
+	FirstFailedAt time.Time `db:"first_failed_at" json:"first_failed_at"`
+	LastFailedAt  time.Time `db:"last_failed_at" json:"last_failed_at"`
+}
+
+// PoolOperator is a node operator of a staking pool as discovered from the on-chain data of the pool
+type PoolOperator struct {
+	Pool           string `db:"pool"`
+	ID             string `db:"operator_id"`
+	Name           string `db:"name"`
+	Address        []byte `db:"address"`
+	Active         bool   `db:"active"`
+	ValidatorCount uint64 `db:"validator_count"`
+	// NewValidators holds the pubkeys of the validators of the operator that have not been exported before
+	NewValidators [][]byte `db:"-"`
+}
+
+const (
+	PotentialSlashingDoubleVote     = "double_vote"
+	PotentialSlashingSurroundVote   = "surround_vote"
+	PotentialSlashingDoubleProposal = "double_proposal"
+)
+
+// PotentialSlashing is a slashable offence detected by the exporter, it is not necessarily included in a block yet
+type PotentialSlashing struct {
+	ID             uint64           `db:"id" json:"id"`
+	Kind           string           `db:"kind" json:"kind"`
+	ValidatorIndex uint64           `db:"validatorindex" json:"validatorindex"`
+	Pubkey         []byte           `db:"pubkey" json:"-"`
+	Slot           uint64           `db:"slot" json:"slot"`
+	Epoch          uint64           `db:"epoch" json:"epoch"`
+	Evidence       SlashingEvidence `db:"evidence" json:"evidence"`
+	DetectedTs     time.Time        `db:"detected_ts" json:"detected_ts"`
+	Slashed        bool             `db:"slashed" json:"slashed"`
+}
+
+// SlashingEvidence holds the two conflicting messages of a potential slashing
+type SlashingEvidence struct {
+	Attestations []*SlashingEvidenceAttestation `json:"attestations,omitempty"`
+	Proposals    []*SlashingEvidenceProposal    `json:"proposals,omitempty"`
+}
+
+func (e *SlashingEvidence) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &e)
+}
+
+func (e SlashingEvidence) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+// SlashingEvidenceAttestation is one of the conflicting votes of a potential attester slashing, the inclusion fields
+// are empty if the vote has not been seen in a block
+type SlashingEvidenceAttestation struct {
+	Slot               uint64 `json:"slot"`
+	CommitteeIndex     uint64 `json:"committee_index"`
+	BeaconBlockRoot    string `json:"beacon_block_root"`
+	SourceEpoch        uint64 `json:"source_epoch"`
+	SourceRoot         string `json:"source_root"`
+	TargetEpoch        uint64 `json:"target_epoch"`
+	TargetRoot         string `json:"target_root"`
+	Signature          string `json:"signature"`
+	InclusionSlot      uint64 `json:"inclusion_slot,omitempty"`
+	InclusionBlockRoot string `json:"inclusion_block_root,omitempty"`
+}
+
+// SlashingEvidenceProposal is one of the conflicting blocks of a potential proposer slashing
+type SlashingEvidenceProposal struct {
+	Slot       uint64 `json:"slot"`
+	BlockRoot  string `json:"block_root"`
+	ParentRoot string `json:"parent_root"`
+	StateRoot  string `json:"state_root"`
+	BodyRoot   string `json:"body_root"`
+	Signature  string `json:"signature"`
+}
@@ -44,6 +44,9 @@ const (
 	RocketpoolColleteralMinReached                   EventName = "rocketpool_colleteral_min"
 	RocketpoolColleteralMaxReached                   EventName = "rocketpool_colleteral_max"
 	SyncCommitteeSoon                                EventName = "validator_synccommittee_soon"
+	ValidatorWithdrawalCredentialsChangedEventName   EventName = "validator_withdrawal_credentials_changed"
+	ValidatorQueueEstimateShiftedEventName           EventName = "validator_queue_estimate_shifted"
+	ValidatorPotentialSlashingEventName              EventName = "validator_potential_slashing"
 )
 
 var UserIndexEvents = []EventName{
@@ -86,6 +89,9 @@ var EventLabel map[EventName]string = map[EventName]string{
 	RocketpoolColleteralMinReached:                   "You reached the rocketpool min collateral",
 	RocketpoolColleteralMaxReached:                   "You reached the rocketpool max collateral",
 	SyncCommitteeSoon:                                "Your validator(s) will soon be part of the sync committee",
+	ValidatorWithdrawalCredentialsChangedEventName:   "Your validator(s) withdrawal credentials changed",
+	ValidatorQueueEstimateShiftedEventName:           "The estimated activation of your validator(s) shifted",
+	ValidatorPotentialSlashingEventName:              "Your validator(s) committed a slashable offence",
 }
 
 func IsUserIndexed(event EventName) bool {
@@ -125,6 +131,9 @@ var EventNames = []EventName{
 	RocketpoolColleteralMinReached,
 	RocketpoolColleteralMaxReached,
 	SyncCommitteeSoon,
+	ValidatorWithdrawalCredentialsChangedEventName,
+	ValidatorQueueEstimateShiftedEventName,
+	ValidatorPotentialSlashingEventName,
 }
 
 type EventNameDesc struct {
@@ -158,6 +167,18 @@ var AddWatchlistEvents = []EventNameDesc{
 		Desc:  "Validator is Offline",
 		Event: ValidatorIsOfflineEventName,
 	},
+	{
+		Desc:  "Withdrawal credentials changed",
+		Event: ValidatorWithdrawalCredentialsChangedEventName,
+	},
+	{
+		Desc:  "Activation estimate shifted",
+		Event: ValidatorQueueEstimateShiftedEventName,
+	},
+	{
+		Desc:  "Potential slashing detected",
+		Event: ValidatorPotentialSlashingEventName,
+	},
 }
 
 // this is the source of truth for the network events that are supported by the user/notification page
@@ -345,6 +345,9 @@ type ValidatorPageData struct {
 	ExecutionIncomeHistoryData          []*ChartDataPoint
 	Deposits                            *ValidatorDeposits
 	Eth1DepositAddress                  []byte
+	WithdrawalCount                     uint64
+	BLSChange                           []*BLSChange
+	WithdrawalCredentialsHistory        []*WithdrawalCredentialsChange
 	FlashMessage                        string
 	Watchlist                           []*TaggedValidators
 	SubscriptionFlash                   []interface{}
@@ -357,6 +360,8 @@ type ValidatorPageData struct {
 	QueuePosition                       uint64
 	EstimatedActivationTs               time.Time
 	EstimatedActivationEpoch            uint64
+	QueueEstimate                       *ValidatorQueueEstimate
+	BlockArrival                        *ValidatorBlockArrival
 	InclusionDelay                      int64
 	CurrentAttestationStreak            uint64
 	LongestAttestationStreak            uint64
@@ -364,6 +369,13 @@ type ValidatorPageData struct {
 	Rocketpool                          *RocketpoolValidatorPageData
 }
 
+// ValidatorBlockArrival holds how late the blocks of a proposer arrived relative to the start of their slot
+type ValidatorBlockArrival struct {
+	Blocks     uint64  `db:"blocks"`
+	AvgDelayMs float64 `db:"avg_delay_ms"`
+	LateBlocks uint64  `db:"late_blocks"`
+}
+
 type RocketpoolValidatorPageData struct {
 	NodeAddress          *[]byte    `db:"node_address"`
 	MinipoolAddress      *[]byte    `db:"minipool_address"`
@@ -447,11 +459,12 @@ type ValidatorBalanceHistory struct {
 
 // ValidatorBalanceHistory is a struct for the validator income history data
 type ValidatorIncomeHistory struct {
-	Day           int64         `db:"day"` // day can be -1 which is pre-genesis
-	Income        int64         `db:"diff"`
-	EndBalance    sql.NullInt64 `db:"end_balance"`
-	StartBalance  sql.NullInt64 `db:"start_balance"`
-	DepositAmount sql.NullInt64 `db:"deposits_amount"`
+	Day              int64         `db:"day"` // day can be -1 which is pre-genesis
+	Income           int64         `db:"diff"`
+	EndBalance       sql.NullInt64 `db:"end_balance"`
+	StartBalance     sql.NullInt64 `db:"start_balance"`
+	DepositAmount    sql.NullInt64 `db:"deposits_amount"`
+	WithdrawalAmount sql.NullInt64 `db:"withdrawals_amount"`
 }
 
 type ValidatorBalanceHistoryChartData struct {
@@ -493,6 +506,8 @@ type ValidatorAttestation struct {
 	InclusionSlot  uint64 `db:"inclusionslot"`
 	Delay          int64  `db:"delay"`
 	// EarliestInclusionSlot uint64 `db:"earliestinclusionslot"`
+	Votes        AttestationVotes `db:"-"`
+	MissedReason string           `db:"-"`
 }
 
 // ValidatorSyncParticipation hold information about sync-participation of a validator
@@ -579,6 +594,8 @@ type BlockPageData struct {
 	AttestationsCount      uint64  `db:"attestationscount"`
 	DepositsCount          uint64  `db:"depositscount"`
 	VoluntaryExitscount    uint64  `db:"voluntaryexitscount"`
+	WithdrawalCount        uint64  `db:"withdrawalcount"`
+	BlobsCount             uint64  `db:"blobs_count"`
 	SlashingsCount         uint64
 	VotesCount             uint64
 	VotingValidatorsCount  uint64
@@ -599,6 +616,9 @@ type BlockPageData struct {
 	ExecBaseFeePerGas     sql.NullInt64 `db:"exec_base_fee_per_gas"`
 	ExecBlockHash         []byte        `db:"exec_block_hash"`
 	ExecTransactionsCount uint64        `db:"exec_transactions_count"`
+	ExecBlobGasUsed       sql.NullInt64 `db:"exec_blob_gas_used"`
+	ExecExcessBlobGas     sql.NullInt64 `db:"exec_excess_blob_gas"`
+	ExecBlobGasPrice      *big.Int
 
 	Transactions []*BlockPageTransaction
 
@@ -606,6 +626,8 @@ type BlockPageData struct {
 
 	Attestations      []*BlockPageAttestation // Attestations included in this block
 	VoluntaryExits    []*BlockPageVoluntaryExits
+	Withdrawals       []*BlockPageWithdrawal
+	BlobSidecars      []*BlockPageBlobSidecar
 	Votes             []*BlockVote // Attestations that voted for that block
 	AttesterSlashings []*BlockPageAttesterSlashing
 	ProposerSlashings []*BlockPageProposerSlashing
@@ -693,6 +715,123 @@ type BlockPageVoluntaryExits struct {
 	Signature      []byte `db:"signature"`
 }
 
+// SSVPageData is a struct to hold the summary of the ssv pool page
+type SSVPageData struct {
+	Operators  uint64 `db:"operators"`
+	Validators uint64 `db:"validators"`
+	Clusters   uint64 `db:"clusters"`
+}
+
+// SSVOperator is a struct to hold an ssv operator with the aggregated performance of its validators over the last 7 days
+type SSVOperator struct {
+	ID                       uint64    `db:"id" json:"id"`
+	PublicKey                string    `db:"publickey" json:"publickey"`
+	Validators               uint64    `db:"validators" json:"validators"`
+	ActiveValidators         uint64    `db:"active_validators" json:"active_validators"`
+	AttestationsExpected     uint64    `db:"attestations_expected" json:"attestations_expected"`
+	AttestationsMissed       uint64    `db:"attestations_missed" json:"attestations_missed"`
+	AttestationParticipation float64   `db:"attestation_participation" json:"attestation_participation"`
+	ProposedBlocks           uint64    `db:"proposed_blocks" json:"proposed_blocks"`
+	MissedBlocks             uint64    `db:"missed_blocks" json:"missed_blocks"`
+	OrphanedBlocks           uint64    `db:"orphaned_blocks" json:"orphaned_blocks"`
+	Performance7d            int64     `db:"performance7d" json:"performance7d"`
+	UpdatedAt                time.Time `db:"updated_at" json:"updated_at"`
+	TotalCount               uint64    `db:"total_count" json:"-"`
+}
+
+// ValidatorsSlashingsPageData is a struct to hold the potential slashings shown on the slashings page
+type ValidatorsSlashingsPageData struct {
+	PotentialSlashings []*PotentialSlashing
+}
+
+// BuildersPageData is a struct to hold the builder leaderboard of the selected period
+type BuildersPageData struct {
+	Days        uint64
+	Periods     []uint64
+	TotalBlocks uint64
+	Builders    []*BuilderLeaderboardEntry
+}
+
+// BuilderLeaderboardEntry holds the blocks of a builder within a period, Builder is the builder id for known builders,
+// the builder pubkey for unknown relay builders and "unknown" for blocks that could not be attributed
+type BuilderLeaderboardEntry struct {
+	Builder      string         `db:"builder" json:"builder"`
+	Name         string         `db:"name" json:"name"`
+	Link         string         `db:"link" json:"link"`
+	Pubkeys      uint64         `db:"pubkeys" json:"pubkeys"`
+	BlockCount   uint64         `db:"block_count" json:"block_count"`
+	BlockShare   float64        `db:"block_share" json:"block_share"`
+	RelayBlocks  uint64         `db:"relay_blocks" json:"relay_blocks"`
+	AverageValue WeiString      `db:"avg_value" json:"avg_value"`
+	TotalValue   WeiString      `db:"total_value" json:"total_value"`
+	Relays       pq.StringArray `db:"relays" json:"relays"`
+	LatestSlot   uint64         `db:"latest_slot" json:"latest_slot"`
+}
+
+// PackingPageData is a struct to hold the block packing leaderboards of the selected period
+type PackingPageData struct {
+	Days      uint64
+	Periods   []uint64
+	Proposers []*PackingLeaderboardEntry
+	Pools     []*PackingLeaderboardEntry
+}
+
+// PackingLeaderboardEntry holds the average block packing of a proposer or a staking pool within a period, Proposer is
+// nil for staking pools
+type PackingLeaderboardEntry struct {
+	Proposer             *uint64 `db:"proposer" json:"proposer,omitempty"`
+	Pool                 string  `db:"pool" json:"pool,omitempty"`
+	Blocks               uint64  `db:"blocks" json:"blocks"`
+	AvgScore             float64 `db:"avg_score" json:"avg_score"`
+	AvgVoteInclusion     float64 `db:"avg_vote_inclusion" json:"avg_vote_inclusion"`
+	AvgSyncParticipation float64 `db:"avg_sync_participation" json:"avg_sync_participation"`
+	RedundantAggregates  uint64  `db:"redundant_aggregates" json:"redundant_aggregates"`
+	MissedOperations     uint64  `db:"missed_operations" json:"missed_operations"`
+}
+
+// ReorgsPageData is a struct to hold the summary of the chain reorgs page
+type ReorgsPageData struct {
+	Total      uint64 `db:"total"`
+	SingleSlot uint64 `db:"single_slot"`
+	LastDay    uint64 `db:"last_day"`
+	LastWeek   uint64 `db:"last_week"`
+}
+
+// BlockPageBlobSidecar is a struct to hold data for blob sidecars on the block page
+type BlockPageBlobSidecar struct {
+	Index             uint64 `db:"index"`
+	KzgCommitment     []byte `db:"kzg_commitment"`
+	KzgProof          []byte `db:"kzg_proof"`
+	BlobVersionedHash []byte `db:"blob_versioned_hash"`
+}
+
+// BLSChange is a struct to hold a bls to execution change included in a block
+type BLSChange struct {
+	Slot           uint64 `db:"slot" json:"slot,omitempty"`
+	BlockRoot      []byte `db:"block_root" json:"blockroot,omitempty"`
+	Validatorindex uint64 `db:"validatorindex" json:"validatorindex"`
+	BlsPubkey      []byte `db:"pubkey" json:"bls_pubkey"`
+	Address        []byte `db:"address" json:"address"`
+	Signature      []byte `db:"signature" json:"signature"`
+}
+
+// WithdrawalCredentialsChange is a struct to hold a change of the withdrawal credentials of a validator
+type WithdrawalCredentialsChange struct {
+	Validatorindex           uint64 `db:"validatorindex"`
+	Pubkey                   []byte `db:"pubkey"`
+	Epoch                    uint64 `db:"epoch"`
+	OldWithdrawalCredentials []byte `db:"old_withdrawalcredentials"`
+	NewWithdrawalCredentials []byte `db:"new_withdrawalcredentials"`
+}
+
+// BlockPageWithdrawal is a struct to hold data for withdrawals on the block page
+type BlockPageWithdrawal struct {
+	Index          uint64 `db:"withdrawalindex"`
+	ValidatorIndex uint64 `db:"validatorindex"`
+	Address        []byte `db:"address"`
+	Amount         uint64 `db:"amount"`
+}
+
 // BlockPageAttesterSlashing is a struct to hold data for attester slashings on the block page
 type BlockPageAttesterSlashing struct {
 	BlockSlot                   uint64        `db:"block_slot"`
@@ -779,9 +918,13 @@ type EpochPageData struct {
 	EligibleEther           uint64  `db:"eligibleether"`
 	GlobalParticipationRate float64 `db:"globalparticipationrate"`
 	VotedEther              uint64  `db:"votedether"`
+	BlobsCount              uint64  `db:"blobscount"`
+	BlobGasUsed             uint64  `db:"blob_gas_used"`
 
 	Blocks []*IndexPageDataBlocks
 
+	BlobGasPrice *big.Int
+
 	SyncParticipationRate float64
 	Ts                    time.Time
 	NextEpoch             uint64
@@ -1689,16 +1832,30 @@ type RelaysResp struct {
 	RelaysInfoContainers [3]RelayInfoContainer
 	RecentBlocks         []*RelaysRespBlock
 	TopBlocks            []*RelaysRespBlock
+	Underpayments        []*RelayUnderpayment
 	LastUpdated          time.Time
 	TopBuilders          []*struct {
-		Tags       TagMetadataSlice `db:"tags"`
-		Builder    []byte           `db:"builder_pubkey"`
-		BlockCount uint64           `db:"c"`
-		LatestSlot uint64           `db:"latest_slot"`
-		BlockPerc  float64
+		Tags            TagMetadataSlice `db:"tags"`
+		Builder         []byte           `db:"builder_pubkey"`
+		BlockCount      uint64           `db:"c"`
+		UnderpaidBlocks uint64           `db:"underpaid"`
+		LatestSlot      uint64           `db:"latest_slot"`
+		BlockPerc       float64
 	}
 }
 
+// RelayUnderpayment is a relay block for which the fee recipient received less than the value promised by the relays
+type RelayUnderpayment struct {
+	Tags                 TagMetadataSlice `db:"tags"`
+	Slot                 uint64           `db:"slot"`
+	Builder              []byte           `db:"builder_pubkey"`
+	ProposerFeeRecipient []byte           `db:"proposer_fee_recipient"`
+	Proposer             uint64           `db:"proposer"`
+	Value                WeiString        `db:"value"`
+	ProposerPayment      WeiString        `db:"proposer_payment"`
+	Shortfall            WeiString        `db:"shortfall"`
+}
+
 type RelaysRespBlock struct {
 	Tags                 TagMetadataSlice `db:"tags"`
 	Value                WeiString        `db:"value"`
@@ -1716,17 +1873,20 @@ type RelayInfoContainer struct {
 	NetworkParticipation float64
 }
 
+
 type RelayInfo struct {
-	RelayID        string         `db:"relay_id"`
-	Name           sql.NullString `db:"name"`
-	Link           sql.NullString `db:"link"`
-	Censors        sql.NullBool   `db:"censors"`
-	Ethical        sql.NullBool   `db:"ethical"`
-	BlockCount     uint64         `db:"block_count"`
-	UniqueBuilders uint64         `db:"unique_builders"`
-	NetworkUsage   float64        `db:"network_usage"`
-	TotalValue     WeiString      `db:"total_value"`
-	AverageValue   WeiString      `db:"avg_value"`
-	MaxValue       WeiString      `db:"max_value"`
-	MaxValueSlot   uint64         `db:"max_value_slot"`
+	RelayID         string         `db:"relay_id"`
+	Name            sql.NullString `db:"name"`
+	Link            sql.NullString `db:"link"`
+	Censors         sql.NullBool   `db:"censors"`
+	Ethical         sql.NullBool   `db:"ethical"`
+	BlockCount      uint64         `db:"block_count"`
+	UniqueBuilders  uint64         `db:"unique_builders"`
+	NetworkUsage    float64        `db:"network_usage"`
+	TotalValue      WeiString      `db:"total_value"`
+	AverageValue    WeiString      `db:"avg_value"`
+	MaxValue        WeiString      `db:"max_value"`
+	MaxValueSlot    uint64         `db:"max_value_slot"`
+	CheckedBlocks   uint64         `db:"checked_blocks"`
+	UnderpaidBlocks uint64         `db:"underpaid_blocks"`
 }
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"eth2-exporter/types"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/params"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -44,6 +46,58 @@ func Eth1TotalReward(block *types.Eth1BlockIndexed) *big.Int {
 	return totalReward.Add(totalReward, uncleReward)
 }
 
+// ProposerPayment returns the amount the fee recipient received in the execution block. If the fee recipient is the
+// coinbase of the block this is the sum of the priority fees and all successful transfers to the coinbase, otherwise it is
+// the sum of all successful transfers to the fee recipient in transactions of the block builder (the coinbase of the
+// block). Transfers include the internal transfers of a transaction so payments made through a contract are counted.
+func ProposerPayment(block *types.Eth1Block, feeRecipient []byte) *big.Int {
+	payment := new(big.Int)
+	baseFee := new(big.Int).SetBytes(block.GetBaseFee())
+	isCoinbase := bytes.Equal(block.GetCoinbase(), feeRecipient)
+
+	for _, tx := range block.GetTransactions() {
+		if isCoinbase {
+			effectiveGasPrice := new(big.Int).SetBytes(tx.GetGasPrice())
+			if len(tx.GetMaxFeePerGas()) > 0 {
+				effectiveGasPrice = math.BigMin(new(big.Int).Add(new(big.Int).SetBytes(tx.GetMaxPriorityFeePerGas()), baseFee), new(big.Int).SetBytes(tx.GetMaxFeePerGas()))
+			}
+			priorityFee := new(big.Int).Sub(effectiveGasPrice, baseFee)
+			if priorityFee.Sign() > 0 {
+				payment.Add(payment, priorityFee.Mul(priorityFee, new(big.Int).SetUint64(tx.GetGasUsed())))
+			}
+		} else if !bytes.Equal(tx.GetFrom(), block.GetCoinbase()) {
+			continue
+		}
+		if tx.GetStatus() == 1 {
+			payment.Add(payment, transferredTo(tx, feeRecipient))
+		}
+	}
+	return payment
+}
+
+// transferredTo returns the value a transaction transferred to the recipient. If the transaction has been traced the
+// value is taken from its calls, which include the top level call, otherwise only the value of the transaction itself is
+// considered.
+func transferredTo(tx *types.Eth1Transaction, recipient []byte) *big.Int {
+	value := new(big.Int)
+	if len(tx.GetItx()) == 0 {
+		if bytes.Equal(tx.GetTo(), recipient) {
+			value.SetBytes(tx.GetValue())
+		}
+		return value
+	}
+	for _, itx := range tx.GetItx() {
+		// delegate and static calls do not transfer value of their own
+		if itx.GetType() == "delegatecall" || itx.GetType() == "staticcall" || itx.GetErrorMsg() != "" {
+			continue
+		}
+		if bytes.Equal(itx.GetTo(), recipient) {
+			value.Add(value, new(big.Int).SetBytes(itx.GetValue()))
+		}
+	}
+	return value
+}
+
 func StripPrefix(hexStr string) string {
 	return strings.Replace(hexStr, "0x", "", 1)
 }
@@ -398,3 +452,37 @@ func FormatTokenIcon(icon []byte, size int) template.HTML {
 	icon64 := base64.StdEncoding.EncodeToString(icon)
 	return template.HTML(fmt.Sprintf("<img class=\"mb-1 mr-1\" src=\"data:image/gif;base64,%v\" width=\"%v\" height=\"%v\">", icon64, size, size))
 }
+
+// deneb blob gas constants, see https://eips.ethereum.org/EIPS/eip-4844#parameters
+const (
+	BlobVersionedHashVersionKzg = 0x01
+	GasPerBlob                  = 131072
+	MinBlobBaseFee              = 1
+)
+
+// KzgCommitmentToVersionedHash returns the versioned hash of a blob as referenced by blob transactions
+func KzgCommitmentToVersionedHash(commitment []byte) []byte {
+	hash := sha256.Sum256(commitment)
+	hash[0] = BlobVersionedHashVersionKzg
+	return hash[:]
+}
+
+// BlobBaseFee returns the price per unit of blob gas in wei for the given excess blob gas
+func BlobBaseFee(excessBlobGas uint64) *big.Int {
+	return fakeExponential(big.NewInt(MinBlobBaseFee), new(big.Int).SetUint64(excessBlobGas), new(big.Int).SetUint64(Config.Chain.Config.BlobBaseFeeUpdateFraction))
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using taylor expansion as specified in EIP-4844
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	i := big.NewInt(1)
+	output := new(big.Int)
+	numeratorAccum := new(big.Int).Mul(factor, denominator)
+	for numeratorAccum.Sign() > 0 {
+		output.Add(output, numeratorAccum)
+
+		numeratorAccum.Mul(numeratorAccum, numerator)
+		numeratorAccum.Div(numeratorAccum, new(big.Int).Mul(denominator, i))
+		i.Add(i, big.NewInt(1))
+	}
+	return output.Div(output, denominator)
+}
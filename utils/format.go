@@ -158,6 +158,38 @@ func FormatBalanceChangeFormated(balance *int64, currencyName string) template.H
 	}
 }
 
+// FormatRewardsBreakdown will return an icon with a tooltip listing the reward components of a validator for an epoch
+func FormatRewardsBreakdown(rewards *types.ValidatorEpochRewards) template.HTML {
+	if rewards == nil {
+		return template.HTML("")
+	}
+	components := []struct {
+		Name   string
+		Amount int64
+	}{
+		{"Source", rewards.AttestationSource},
+		{"Target", rewards.AttestationTarget},
+		{"Head", rewards.AttestationHead},
+		{"Inclusion delay", rewards.AttestationInclusionDelay},
+		{"Inactivity", rewards.AttestationInactivity},
+		{"Proposer (attestations)", rewards.ProposerAttestationInclusion},
+		{"Proposer (sync aggregate)", rewards.ProposerSyncInclusion},
+		{"Proposer (slashings)", rewards.ProposerSlashingInclusion},
+		{"Sync committee", rewards.SyncCommittee},
+	}
+	var tooltip strings.Builder
+	for _, c := range components {
+		if c.Amount == 0 {
+			continue
+		}
+		fmt.Fprintf(&tooltip, "%s: %s GWei<br>", c.Name, FormatAddCommasFormated(float64(c.Amount), 0))
+	}
+	if tooltip.Len() == 0 {
+		return template.HTML("")
+	}
+	return template.HTML(fmt.Sprintf("<i class=\"fas fa-info-circle text-muted ml-1\" data-toggle=\"tooltip\" data-html=\"true\" data-placement=\"top\" title=\"%s\"></i>", html.EscapeString(tooltip.String())))
+}
+
 // FormatBalanceChange will return a string for a balance change
 func FormatBalanceChange(balance *int64, currency string) template.HTML {
 	balanceF := float64(*balance) / float64(1e9)
@@ -249,6 +281,48 @@ func FormatInclusionDelay(inclusionSlot uint64, delay int64) template.HTML {
 	}
 }
 
+// FormatAttestationVotes will return badges showing whether the source, target and head votes of an attestation were correct
+func FormatAttestationVotes(votes types.AttestationVotes) template.HTML {
+	if !votes.Checked {
+		return template.HTML("-")
+	}
+	badge := func(name, title string, correct bool) string {
+		if correct {
+			return fmt.Sprintf(`<span title="Correct %[2]v vote" data-toggle="tooltip" class="badge badge-pill bg-success text-white" style="font-size: 12px; font-weight: 500;">%[1]v</span>`, name, title)
+		}
+		return fmt.Sprintf(`<span title="Incorrect %[2]v vote" data-toggle="tooltip" class="badge badge-pill bg-danger text-white" style="font-size: 12px; font-weight: 500;">%[1]v</span>`, name, title)
+	}
+	return template.HTML(badge("S", "source", votes.Source) + " " + badge("T", "target", votes.Target) + " " + badge("H", "head", votes.Head))
+}
+
+// MissedAttestationReasonText returns a short explanation of why an attestation was missed
+func MissedAttestationReasonText(reason string) string {
+	switch reason {
+	case types.MissedAttestationReasonOffline:
+		return "the validator was offline"
+	case types.MissedAttestationReasonBlockMissing:
+		return "the block of the slot was missing"
+	case types.MissedAttestationReasonBlockLate:
+		return "the block of the slot arrived late"
+	case types.MissedAttestationReasonIncludedTooLate:
+		return "the attestation was included too late"
+	case types.MissedAttestationReasonWrongHeadGuess:
+		return "most of the network voted for another head, the attestation probably did too (guessed)"
+	case types.MissedAttestationReasonNotAggregatedGuess:
+		return "the attestation was probably not picked up by an aggregate (guessed)"
+	}
+	return ""
+}
+
+// FormatMissedAttestationReason will return the status of an attestation with the reason why it was missed as a tooltip
+func FormatMissedAttestationReason(status template.HTML, reason string) template.HTML {
+	text := MissedAttestationReasonText(reason)
+	if text == "" {
+		return status
+	}
+	return template.HTML(fmt.Sprintf(`%v <i class="fas fa-info-circle text-muted" data-toggle="tooltip" title="Likely cause: %v"></i>`, status, text))
+}
+
 // FormatSlotToTimestamp will return the time elapsed since blockSlot
 func FormatSlotToTimestamp(blockSlot uint64) template.HTML {
 	time := SlotToTime(blockSlot)
@@ -941,6 +1015,13 @@ func DerefString(str *string) string {
 	return ""
 }
 
+func DerefUint64(i *uint64) uint64 {
+	if i != nil {
+		return *i
+	}
+	return 0
+}
+
 // TrLang returns translated text based on language tag and text id
 func TrLang(lang string, key string) template.HTML {
 	I18n := getLocaliser()
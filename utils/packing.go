@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"eth2-exporter/types"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// weights of the parts of the packing score, they add up to 1
+const (
+	packingVotesWeight      = 0.6
+	packingAggregatesWeight = 0.1
+	packingSyncWeight       = 0.2
+	packingOperationsWeight = 0.1
+)
+
+// EvaluateEpochPacking computes the packing of the blocks of an epoch. blocks have to be the canonical blocks of the
+// previous, the evaluated and the following epoch ordered by slot: the previous epoch shows which votes were already
+// included and the following epoch which votes were still available.
+func EvaluateEpochPacking(blocks []*types.PackingBlock, epoch uint64) []*types.BlockPacking {
+	slotsPerEpoch := Config.Chain.Config.SlotsPerEpoch
+	epochStart := epoch * slotsPerEpoch
+	epochEnd := epochStart + slotsPerEpoch - 1
+	fromSlot := uint64(0)
+	if epoch > 0 {
+		fromSlot = epochStart - slotsPerEpoch
+	}
+
+	// a validator votes once per epoch, the first inclusion of a vote is kept per epoch of the attested slot. Votes
+	// for slots before the previous epoch may have been included earlier and are ignored.
+	type inclusion struct {
+		attestedSlot  uint64
+		inclusionSlot uint64
+	}
+	firstInclusions := make(map[uint64]map[uint64]*inclusion)
+	for _, b := range blocks {
+		for _, a := range b.Aggregates {
+			if a.Slot < fromSlot {
+				continue
+			}
+			attestedEpoch := EpochOfSlot(a.Slot)
+			if firstInclusions[attestedEpoch] == nil {
+				firstInclusions[attestedEpoch] = make(map[uint64]*inclusion)
+			}
+			for _, v := range a.Validators {
+				if _, exists := firstInclusions[attestedEpoch][uint64(v)]; !exists {
+					firstInclusions[attestedEpoch][uint64(v)] = &inclusion{attestedSlot: a.Slot, inclusionSlot: b.Slot}
+				}
+			}
+		}
+	}
+
+	// number of votes per attested slot and slot of their first inclusion, used to count the votes available to a block
+	votesByInclusion := make(map[uint64]map[uint64]uint64)
+	for _, inclusions := range firstInclusions {
+		for _, i := range inclusions {
+			if votesByInclusion[i.attestedSlot] == nil {
+				votesByInclusion[i.attestedSlot] = make(map[uint64]uint64)
+			}
+			votesByInclusion[i.attestedSlot][i.inclusionSlot]++
+		}
+	}
+
+	packings := []*types.BlockPacking{}
+	for _, b := range blocks {
+		if b.Slot < epochStart || b.Slot > epochEnd {
+			continue
+		}
+
+		p := &types.BlockPacking{
+			Slot:               b.Slot,
+			BlockRoot:          b.BlockRoot,
+			Proposer:           b.Proposer,
+			Aggregates:         uint64(len(b.Aggregates)),
+			SyncParticipation:  b.SyncParticipation,
+			IncludedOperations: b.IncludedOperations,
+		}
+		if missedSlashings, missedExits, ok := MissedOperations(b); ok {
+			p.MissedSlashings = &missedSlashings
+			p.MissedExits = &missedExits
+		}
+
+		// an aggregate is redundant if all of its votes were included by an earlier block or an earlier aggregate of the
+		// block, only votes within their inclusion window are counted as included, matching the available votes
+		seen := make(map[uint64]map[uint64]bool)
+		for _, a := range b.Aggregates {
+			attestedEpoch := EpochOfSlot(a.Slot)
+			if seen[attestedEpoch] == nil {
+				seen[attestedEpoch] = make(map[uint64]bool)
+			}
+			newVotes := 0
+			for _, v := range a.Validators {
+				i := firstInclusions[attestedEpoch][uint64(v)]
+				if i != nil && i.inclusionSlot == b.Slot && AttestationInclusionDeadline(a.Slot) >= b.Slot && !seen[attestedEpoch][uint64(v)] {
+					newVotes++
+				}
+				seen[attestedEpoch][uint64(v)] = true
+			}
+			if newVotes == 0 {
+				p.RedundantAggregates++
+			}
+			p.IncludedVotes += uint64(newVotes)
+		}
+
+		// votes of the inclusion window that were first included by this or a later block were available to this block
+		for attestedSlot, inclusions := range votesByInclusion {
+			if attestedSlot >= b.Slot || AttestationInclusionDeadline(attestedSlot) < b.Slot {
+				continue
+			}
+			for inclusionSlot, votes := range inclusions {
+				if inclusionSlot >= b.Slot {
+					p.AvailableVotes += votes
+				}
+			}
+		}
+
+		p.Score = PackingScore(p, b.HasSyncAggregate)
+		packings = append(packings, p)
+	}
+	return packings
+}
+
+// PackingScore rates the packing of a block from 0 to 100, the operations are left out of the score if the missed
+// operations of the block are unknown
+func PackingScore(p *types.BlockPacking, hasSyncAggregate bool) float64 {
+	votes := 1.0
+	if p.AvailableVotes > 0 {
+		votes = float64(p.IncludedVotes) / float64(p.AvailableVotes)
+	}
+	aggregates := 1.0
+	if p.Aggregates > 0 {
+		aggregates = 1 - float64(p.RedundantAggregates)/float64(p.Aggregates)
+	}
+	// blocks before altair do not contain a sync aggregate
+	sync := 1.0
+	if hasSyncAggregate {
+		sync = p.SyncParticipation
+	}
+	score := packingVotesWeight*votes + packingAggregatesWeight*aggregates + packingSyncWeight*sync
+	if p.MissedSlashings == nil || p.MissedExits == nil {
+		return 100 * score / (1 - packingOperationsWeight)
+	}
+	operations := 1.0
+	if missed := *p.MissedSlashings + *p.MissedExits; missed > 0 {
+		operations = float64(p.IncludedOperations) / float64(p.IncludedOperations+missed)
+	}
+	return 100 * (score + packingOperationsWeight*operations)
+}
+
+// AttesterSlashingKey identifies an attester slashing by the validators it slashes
+func AttesterSlashingKey(attestingIndices1, attestingIndices2 []uint64) string {
+	attesters := make(map[uint64]bool, len(attestingIndices1))
+	for _, v := range attestingIndices1 {
+		attesters[v] = true
+	}
+	slashed := []uint64{}
+	for _, v := range attestingIndices2 {
+		if attesters[v] {
+			slashed = append(slashed, v)
+		}
+	}
+	sort.Slice(slashed, func(i, j int) bool { return slashed[i] < slashed[j] })
+	keys := make([]string, 0, len(slashed))
+	for _, v := range slashed {
+		keys = append(keys, fmt.Sprintf("%d", v))
+	}
+	return strings.Join(keys, ",")
+}
+
+// MissedOperations returns the number of slashings and voluntary exits that were pending in the operation pool before
+// the slot of the block but were not included by it. Only as many operations as the block had room for are counted as
+// missed. ok is false if the operation pool was not observed before the block.
+func MissedOperations(b *types.PackingBlock) (slashings uint64, exits uint64, ok bool) {
+	pool := b.PendingOperations
+	if pool == nil {
+		return 0, 0, false
+	}
+
+	includedProposerSlashings := make(map[int64]bool, len(b.IncludedProposerSlashings))
+	for _, v := range b.IncludedProposerSlashings {
+		includedProposerSlashings[v] = true
+	}
+	missedProposerSlashings := uint64(0)
+	for _, v := range pool.ProposerSlashings {
+		if !includedProposerSlashings[v] {
+			missedProposerSlashings++
+		}
+	}
+
+	includedAttesterSlashings := make(map[string]bool, len(b.IncludedAttesterSlashings))
+	for _, key := range b.IncludedAttesterSlashings {
+		includedAttesterSlashings[key] = true
+	}
+	missedAttesterSlashings := uint64(0)
+	for _, key := range pool.AttesterSlashings {
+		if !includedAttesterSlashings[key] {
+			missedAttesterSlashings++
+		}
+	}
+
+	includedExits := make(map[int64]bool, len(b.IncludedExits))
+	for _, v := range b.IncludedExits {
+		includedExits[v] = true
+	}
+	missedExits := uint64(0)
+	for _, v := range pool.VoluntaryExits {
+		if !includedExits[v] {
+			missedExits++
+		}
+	}
+
+	cfg := Config.Chain.Config
+	slashings = missedWithinCapacity(missedProposerSlashings, uint64(len(b.IncludedProposerSlashings)), cfg.MaxProposerSlashings) +
+		missedWithinCapacity(missedAttesterSlashings, uint64(len(b.IncludedAttesterSlashings)), cfg.MaxAttesterSlashings)
+	exits = missedWithinCapacity(missedExits, uint64(len(b.IncludedExits)), cfg.MaxVoluntaryExits)
+	return slashings, exits, true
+}
+
+// missedWithinCapacity limits the missed operations of a kind to the room the block had left for them
+func missedWithinCapacity(missed, included, max uint64) uint64 {
+	if included >= max {
+		return 0
+	}
+	if missed > max-included {
+		return max - included
+	}
+	return missed
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"eth2-exporter/types"
+	"math"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func setupPackingConfig(denebForkEpoch uint64) {
+	Config = &types.Config{}
+	Config.Chain.Config.SlotsPerEpoch = 4
+	Config.Chain.Config.DenebForkEpoch = denebForkEpoch
+	Config.Chain.Config.MaxProposerSlashings = 16
+	Config.Chain.Config.MaxAttesterSlashings = 2
+	Config.Chain.Config.MaxVoluntaryExits = 16
+}
+
+func TestPackingScore(t *testing.T) {
+	zero := uint64(0)
+	one := uint64(1)
+	tests := []struct {
+		name             string
+		packing          *types.BlockPacking
+		hasSyncAggregate bool
+		want             float64
+	}{
+		{"perfect block", &types.BlockPacking{IncludedVotes: 10, AvailableVotes: 10, Aggregates: 2, SyncParticipation: 1, MissedSlashings: &zero, MissedExits: &zero}, true, 100},
+		{"phase0 block", &types.BlockPacking{IncludedVotes: 10, AvailableVotes: 10, MissedSlashings: &zero, MissedExits: &zero}, false, 100},
+		{"half of the votes", &types.BlockPacking{IncludedVotes: 5, AvailableVotes: 10, SyncParticipation: 1, MissedSlashings: &zero, MissedExits: &zero}, true, 70},
+		{"redundant aggregate", &types.BlockPacking{IncludedVotes: 10, AvailableVotes: 10, Aggregates: 2, RedundantAggregates: 1, SyncParticipation: 1, MissedSlashings: &zero, MissedExits: &zero}, true, 95},
+		{"missed exit", &types.BlockPacking{IncludedVotes: 10, AvailableVotes: 10, SyncParticipation: 1, IncludedOperations: 1, MissedSlashings: &zero, MissedExits: &one}, true, 95},
+		{"unknown operations", &types.BlockPacking{IncludedVotes: 5, AvailableVotes: 10, SyncParticipation: 1}, true, 60 / 0.9},
+	}
+	for _, tt := range tests {
+		if got := PackingScore(tt.packing, tt.hasSyncAggregate); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%v: got score %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMissedOperations(t *testing.T) {
+	setupPackingConfig(math.MaxUint64)
+
+	_, _, ok := MissedOperations(&types.PackingBlock{})
+	if ok {
+		t.Errorf("expected unknown missed operations without an operation pool snapshot")
+	}
+
+	block := &types.PackingBlock{
+		IncludedProposerSlashings: pq.Int64Array{1},
+		IncludedAttesterSlashings: []string{"2,3"},
+		IncludedExits:             pq.Int64Array{10},
+		PendingOperations: &types.OperationPoolSnapshot{
+			ProposerSlashings: pq.Int64Array{1, 4},
+			AttesterSlashings: pq.StringArray{"2,3", "5", "6"},
+			VoluntaryExits:    pq.Int64Array{10, 11, 12},
+		},
+	}
+	slashings, exits, ok := MissedOperations(block)
+	if !ok {
+		t.Fatalf("expected known missed operations")
+	}
+	// the block only had room for one more attester slashing
+	if slashings != 2 || exits != 2 {
+		t.Errorf("got %v missed slashings and %v missed exits, want 2 and 2", slashings, exits)
+	}
+}
+
+func TestAttesterSlashingKey(t *testing.T) {
+	if key := AttesterSlashingKey([]uint64{12, 3, 7}, []uint64{7, 12, 20}); key != "7,12" {
+		t.Errorf("got key %v, want 7,12", key)
+	}
+	if key := AttesterSlashingKey([]uint64{1}, []uint64{2}); key != "" {
+		t.Errorf("got key %v for attestations without common attesters", key)
+	}
+}
+
+func TestEvaluateEpochPacking(t *testing.T) {
+	// the vote for slot 1 of epoch 0 is first included at slot 7 of epoch 1, which is only possible since deneb
+	blocks := []*types.PackingBlock{
+		{Slot: 1, SyncParticipation: 1, HasSyncAggregate: true},
+		{Slot: 5, SyncParticipation: 1, HasSyncAggregate: true, Aggregates: []*types.PackingAggregate{
+			{BlockSlot: 5, Slot: 4, Validators: pq.Int64Array{1, 2}},
+			{BlockSlot: 5, Slot: 4, Validators: pq.Int64Array{2}},
+		}},
+		{Slot: 6, SyncParticipation: 1, HasSyncAggregate: true},
+		{Slot: 7, SyncParticipation: 1, HasSyncAggregate: true, Aggregates: []*types.PackingAggregate{
+			{BlockSlot: 7, Slot: 1, Validators: pq.Int64Array{5}},
+			{BlockSlot: 7, Slot: 6, Validators: pq.Int64Array{3}},
+		}},
+		{Slot: 9, SyncParticipation: 1, HasSyncAggregate: true, Aggregates: []*types.PackingAggregate{
+			{BlockSlot: 9, Slot: 6, Validators: pq.Int64Array{4}},
+		}},
+	}
+
+	type votes struct {
+		included, available, redundant uint64
+	}
+	tests := []struct {
+		name           string
+		denebForkEpoch uint64
+		want           map[uint64]votes
+	}{
+		{"before deneb", math.MaxUint64, map[uint64]votes{
+			5: {included: 2, available: 3, redundant: 1},
+			6: {included: 0, available: 0, redundant: 0},
+			7: {included: 1, available: 2, redundant: 1},
+		}},
+		{"after deneb", 0, map[uint64]votes{
+			5: {included: 2, available: 3, redundant: 1},
+			6: {included: 0, available: 1, redundant: 0},
+			7: {included: 2, available: 3, redundant: 0},
+		}},
+	}
+	for _, tt := range tests {
+		setupPackingConfig(tt.denebForkEpoch)
+		packings := EvaluateEpochPacking(blocks, 1)
+		if len(packings) != len(tt.want) {
+			t.Fatalf("%v: got %v packings, want %v", tt.name, len(packings), len(tt.want))
+		}
+		for _, p := range packings {
+			want := tt.want[p.Slot]
+			got := votes{included: p.IncludedVotes, available: p.AvailableVotes, redundant: p.RedundantAggregates}
+			if got != want {
+				t.Errorf("%v: block %v got %+v, want %+v", tt.name, p.Slot, got, want)
+			}
+			if p.MissedSlashings != nil || p.MissedExits != nil {
+				t.Errorf("%v: block %v has missed operations without an operation pool snapshot", tt.name, p.Slot)
+			}
+		}
+	}
+}
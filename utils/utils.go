@@ -153,6 +153,7 @@ func GetTemplateFuncs() template.FuncMap {
 		},
 		"formatStringThousands": FormatThousandsEnglish,
 		"derefString":           DerefString,
+		"derefUint64":           DerefUint64,
 		"trLang":                TrLang,
 		"firstCharToUpper":      func(s string) string { return strings.Title(s) },
 		"eqsp": func(a, b *string) bool {
@@ -252,6 +253,64 @@ func EpochOfSlot(slot uint64) uint64 {
 	return slot / Config.Chain.Config.SlotsPerEpoch
 }
 
+// AttestationInclusionDeadline returns the last slot an attestation for slot can be included in, since deneb
+// (EIP-7045) attestations can be included until the end of the epoch following their epoch
+func AttestationInclusionDeadline(slot uint64) uint64 {
+	if EpochOfSlot(slot) >= Config.Chain.Config.DenebForkEpoch {
+		return (EpochOfSlot(slot)+2)*Config.Chain.Config.SlotsPerEpoch - 1
+	}
+	return slot + Config.Chain.Config.SlotsPerEpoch
+}
+
+// CanonicalBlockRootAt returns the root of the latest canonical block at or before slot, ok is false if the root is
+// not covered by the canonical block roots or if a slot on the way back has an unknown status, e.g. because it has
+// not been exported yet
+func CanonicalBlockRootAt(roots *types.CanonicalBlockRoots, slot uint64) (root []byte, ok bool) {
+	for s := int64(slot); s >= int64(roots.StartSlot); s-- {
+		if !roots.Known[uint64(s)] {
+			return nil, false
+		}
+		if root, exists := roots.Roots[uint64(s)]; exists {
+			return root, true
+		}
+	}
+	return nil, false
+}
+
+// CheckAttestationVotes compares the source, target and head votes of an attestation with the canonical chain. The
+// source and target are correct if they match the canonical block at the start of their epoch, the head is correct if
+// it matches the latest canonical block at the attested slot.
+func CheckAttestationVotes(data *types.AttestationData, roots *types.CanonicalBlockRoots) types.AttestationVotes {
+	votes := types.AttestationVotes{}
+	if roots == nil || data == nil || data.Source == nil || data.Target == nil {
+		return votes
+	}
+
+	head, ok := CanonicalBlockRootAt(roots, data.Slot)
+	if !ok {
+		return votes
+	}
+	target, ok := CanonicalBlockRootAt(roots, data.Target.Epoch*Config.Chain.Config.SlotsPerEpoch)
+	if !ok {
+		return votes
+	}
+	// the genesis checkpoint is voted for with a zero root
+	if data.Source.Epoch == 0 {
+		votes.Source = true
+	} else {
+		source, ok := CanonicalBlockRootAt(roots, data.Source.Epoch*Config.Chain.Config.SlotsPerEpoch)
+		if !ok {
+			return votes
+		}
+		votes.Source = bytes.Equal(data.Source.Root, source)
+	}
+
+	votes.Checked = true
+	votes.Target = bytes.Equal(data.Target.Root, target)
+	votes.Head = bytes.Equal(data.BeaconBlockRoot, head)
+	return votes
+}
+
 // DayOfSlot returns the corresponding day of a slot
 func DayOfSlot(slot uint64) uint64 {
 	return Config.Chain.Config.SecondsPerSlot * slot / (24 * 3600)
@@ -267,6 +326,11 @@ func SlotToTime(slot uint64) time.Time {
 	return time.Unix(int64(Config.Chain.GenesisTimestamp+slot*Config.Chain.Config.SecondsPerSlot), 0)
 }
 
+// LateBlockThreshold returns the delay into a slot after which a block arrives too late to be attested to as head
+func LateBlockThreshold() time.Duration {
+	return time.Duration(Config.Chain.Config.SecondsPerSlot) * time.Second / 3
+}
+
 // TimeToSlot returns time to slot in seconds
 func TimeToSlot(timestamp uint64) uint64 {
 	if Config.Chain.GenesisTimestamp > timestamp {
@@ -337,6 +401,10 @@ func ReadConfig(cfg *types.Config, path string) error {
 		return err
 	}
 
+	// chain configs of networks without a scheduled deneb fork do not contain DENEB_FORK_EPOCH, it has to default to the
+	// far future instead of genesis
+	cfg.Chain.Config.DenebForkEpoch = math.MaxUint64
+
 	if cfg.Chain.ConfigPath == "" {
 		switch cfg.Chain.Name {
 		case "mainnet":
@@ -358,9 +426,9 @@ func ReadConfig(cfg *types.Config, path string) error {
 		if err != nil {
 			return fmt.Errorf("error opening Chain Config file %v: %w", cfg.Chain.ConfigPath, err)
 		}
-		var chainConfig *types.ChainConfig
+		chainConfig := &types.ChainConfig{DenebForkEpoch: math.MaxUint64}
 		decoder := yaml.NewDecoder(f)
-		err = decoder.Decode(&chainConfig)
+		err = decoder.Decode(chainConfig)
 		if err != nil {
 			return fmt.Errorf("error decoding Chain Config file %v: %v", cfg.Chain.ConfigPath, err)
 		}
@@ -368,6 +436,10 @@ func ReadConfig(cfg *types.Config, path string) error {
 	}
 	cfg.Chain.Name = cfg.Chain.Config.ConfigName
 
+	if cfg.Chain.Config.DenebForkEpoch != math.MaxUint64 && cfg.Chain.Config.BlobBaseFeeUpdateFraction == 0 {
+		return fmt.Errorf("chain config %v schedules the deneb fork but does not set BLOB_BASE_FEE_UPDATE_FRACTION", cfg.Chain.Config.ConfigName)
+	}
+
 	if cfg.Chain.GenesisTimestamp == 0 {
 		switch cfg.Chain.Name {
 		case "mainnet":
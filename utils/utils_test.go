@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"eth2-exporter/types"
+	"testing"
+)
+
+func TestCheckAttestationVotes(t *testing.T) {
+	Config = &types.Config{}
+	Config.Chain.Config.SlotsPerEpoch = 4
+
+	// slot 5 was missed, slot 6 has not been exported yet
+	roots := &types.CanonicalBlockRoots{
+		StartSlot: 0,
+		Roots:     map[uint64][]byte{0: {0x0}, 4: {0x4}, 7: {0x7}},
+		Known:     map[uint64]bool{0: true, 1: true, 2: true, 3: true, 4: true, 5: true, 7: true},
+	}
+	source := &types.Checkpoint{Epoch: 0, Root: []byte{0x0}}
+	target := &types.Checkpoint{Epoch: 1, Root: []byte{0x4}}
+
+	tests := []struct {
+		name string
+		data *types.AttestationData
+		want types.AttestationVotes
+	}{
+		{"head at missed slot", &types.AttestationData{Slot: 5, BeaconBlockRoot: []byte{0x4}, Source: source, Target: target}, types.AttestationVotes{Checked: true, Source: true, Target: true, Head: true}},
+		{"wrong head", &types.AttestationData{Slot: 7, BeaconBlockRoot: []byte{0x4}, Source: source, Target: target}, types.AttestationVotes{Checked: true, Source: true, Target: true}},
+		{"head at unknown slot", &types.AttestationData{Slot: 6, BeaconBlockRoot: []byte{0x4}, Source: source, Target: target}, types.AttestationVotes{}},
+	}
+	for _, tt := range tests {
+		if got := CheckAttestationVotes(tt.data, roots); got != tt.want {
+			t.Errorf("%v: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}